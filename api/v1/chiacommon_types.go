@@ -4,16 +4,111 @@ Copyright 2023 Chia Network Inc.
 
 package v1
 
+import (
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
 // ChiaExporterConfigSpec defines the desired state of Chia exporter configuration
 type ChiaExporterConfigSpec struct {
+	// Enabled defines whether a chia-exporter sidecar container and metrics Service should be created for this component. Defaults to true.
+	// +kubebuilder:default=true
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
 	// Image defines the image to use for the chia exporter containers
 	// +kubebuilder:default="ghcr.io/chia-network/chia-exporter:latest"
 	// +optional
 	Image string `json:"image"`
 
+	// ImagePullPolicy is the pull policy for the chia exporter container. Defaults to the pod's imagePullPolicy
+	// +optional
+	ImagePullPolicy *corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// MetricsPort is the port chia-exporter serves metrics and /healthz on, passed to it as CHIA_EXPORTER_METRICS_PORT.
+	// The container port, probes, metrics Service and ServiceMonitor all use it. Defaults to 9914
+	// +kubebuilder:default=9914
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	MetricsPort int32 `json:"metricsPort,omitempty"`
+
 	// Labels is a map of string keys and values to attach to the chia exporter k8s Service
 	// +optional
 	ServiceLabels map[string]string `json:"serviceLabels,omitempty"`
+
+	// Args are additional command line arguments passed to chia-exporter
+	// +optional
+	Args []string `json:"args,omitempty"`
+
+	// Env are additional environment variables for the chia exporter container, such as CHIA_EXPORTER_LOG_LEVEL or CHIA_EXPORTER_MAXMIND_DB_PATH
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// Periodic probe of container liveness. Defaults to an HTTP check of chia-exporter's /healthz endpoint
+	// +optional
+	LivenessProbe *corev1.Probe `json:"livenessProbe,omitempty"`
+
+	// Periodic probe of container service readiness. Defaults to an HTTP check of chia-exporter's /healthz endpoint
+	// +optional
+	ReadinessProbe *corev1.Probe `json:"readinessProbe,omitempty"`
+
+	// StartupProbe indicates that the Pod has successfully initialized. Defaults to an HTTP check of chia-exporter's /healthz endpoint
+	// +optional
+	StartupProbe *corev1.Probe `json:"startupProbe,omitempty"`
+
+	// Resources defines the compute resources for the chia exporter container
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// SecurityContext defines the security context for the chia exporter container. Defaults to the chia container's security context
+	// +optional
+	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
+
+	// ServiceMonitor configures a Prometheus Operator ServiceMonitor for the chia exporter metrics Service.
+	// It is only created if the monitoring.coreos.com CRDs are installed in the cluster.
+	// +optional
+	ServiceMonitor *ChiaExporterServiceMonitorSpec `json:"serviceMonitor,omitempty"`
+
+	// PrometheusRule configures a Prometheus Operator PrometheusRule with default alerts for this component.
+	// It is only created if the monitoring.coreos.com CRDs are installed in the cluster.
+	// +optional
+	PrometheusRule *ChiaExporterPrometheusRuleSpec `json:"prometheusRule,omitempty"`
+}
+
+// ChiaExporterServiceMonitorSpec defines the desired state of a chia exporter ServiceMonitor
+type ChiaExporterServiceMonitorSpec struct {
+	// Enabled defines whether a ServiceMonitor should be created
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Labels is a map of string keys and values to attach to the ServiceMonitor, often needed to match a Prometheus' serviceMonitorSelector
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Interval at which metrics should be scraped, eg. "30s". Defaults to the Prometheus' global scrape interval
+	// +optional
+	Interval string `json:"interval,omitempty"`
+
+	// ScrapeTimeout is the timeout after which a scrape is ended, eg. "10s". Defaults to the Prometheus' global scrape timeout
+	// +optional
+	ScrapeTimeout string `json:"scrapeTimeout,omitempty"`
+}
+
+// ChiaExporterPrometheusRuleSpec defines the desired state of a chia exporter PrometheusRule
+type ChiaExporterPrometheusRuleSpec struct {
+	// Enabled defines whether a PrometheusRule with default alerts should be created
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Labels is a map of string keys and values to attach to the PrometheusRule, often needed to match a Prometheus' ruleSelector
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// AlertLabels is a map of string keys and values to attach to every alert in the PrometheusRule, eg. a severity or routing label
+	// +optional
+	AlertLabels map[string]string `json:"alertLabels,omitempty"`
 }
 
 // ChiaKeysSpec defines the name of a kubernetes secret and key in that secret that contains the Chia mnemonic
@@ -23,6 +118,24 @@ type ChiaKeysSpec struct {
 
 	// Key is the key of the data item in the Secret
 	Key string `json:"key"`
+
+	// Fingerprint is the expected fingerprint of the mnemonic key. If set, pods fail to start if the key's fingerprint doesn't match
+	// +optional
+	Fingerprint *int64 `json:"fingerprint,omitempty"`
+}
+
+// ChiaConfigOverridesSpec defines YAML documents that are deep-merged into CHIA_ROOT/config/config.yaml before chia starts.
+// Maps are merged key by key, while lists and scalars replace the generated value entirely.
+// Settings controlled by other fields in this spec (testnet, log_level, etc.) are applied after these overrides and take precedence.
+type ChiaConfigOverridesSpec struct {
+	// Inline is a YAML document to merge into config.yaml, rendered into an operator managed ConfigMap
+	// +optional
+	Inline string `json:"inline,omitempty"`
+
+	// ConfigMapRef selects a key in an existing ConfigMap in the same namespace that contains a YAML document to merge into config.yaml.
+	// If both are specified, the ConfigMap document is merged first and the Inline document is merged on top of it.
+	// +optional
+	ConfigMapRef *corev1.ConfigMapKeySelector `json:"configMapRef,omitempty"`
 }
 
 // AdditionalMetadata contains labels and annotations to attach to created objects
@@ -68,7 +181,7 @@ type StorageConfig struct {
 }
 
 // ChiaRootConfig optional config for CHIA_ROOT persistent storage, likely only needed for Chia full_nodes, but may help in startup time for other components.
-// Both options may be specified but only one can be used, therefore PersistentVolumeClaims will be respected over HostPath volumes if both are specified.
+// Multiple options may be specified but only one can be used. They are respected in the order PersistentVolumeClaim, HostPathVolume, NFSVolume, CSIVolume, EphemeralVolume, ISCSIVolume.
 type ChiaRootConfig struct {
 	// PersistentVolumeClaim use an existing persistent volume claim to store CHIA_ROOT data
 	// +optional
@@ -77,10 +190,26 @@ type ChiaRootConfig struct {
 	// HostPathVolume use an existing persistent volume claim to store CHIA_ROOT data
 	// +optional
 	HostPathVolume *HostPathVolumeConfig `json:"hostPathVolume,omitempty"`
+
+	// NFSVolume use an NFS export to store CHIA_ROOT data
+	// +optional
+	NFSVolume *NFSVolumeConfig `json:"nfsVolume,omitempty"`
+
+	// CSIVolume use an inline CSI volume to store CHIA_ROOT data
+	// +optional
+	CSIVolume *CSIVolumeConfig `json:"csiVolume,omitempty"`
+
+	// EphemeralVolume use a generic ephemeral volume, a PVC created and deleted with the Pod, to store CHIA_ROOT data
+	// +optional
+	EphemeralVolume *EphemeralVolumeConfig `json:"ephemeralVolume,omitempty"`
+
+	// ISCSIVolume use an iSCSI disk to store CHIA_ROOT data
+	// +optional
+	ISCSIVolume *ISCSIVolumeConfig `json:"iscsiVolume,omitempty"`
 }
 
 // PlotsConfig optional config for harvester plots persistent storage, only needed for Chia harvesters.
-// Supports adding both PVCs and hostPath volumes.
+// Supports adding PVCs, hostPath, NFS, CSI, generic ephemeral, iSCSI and local volumes.
 type PlotsConfig struct {
 	// PersistentVolumeClaim use an existing persistent volume claim to mount plot directories
 	// +optional
@@ -89,22 +218,77 @@ type PlotsConfig struct {
 	// HostPathVolume use an existing directory on the host to mount plot directories
 	// +optional
 	HostPathVolume []*HostPathVolumeConfig `json:"hostPathVolume,omitempty"`
+
+	// NFSVolume use NFS exports to mount plot directories
+	// +optional
+	NFSVolume []*NFSVolumeConfig `json:"nfsVolume,omitempty"`
+
+	// CSIVolume use inline CSI volumes to mount plot directories
+	// +optional
+	CSIVolume []*CSIVolumeConfig `json:"csiVolume,omitempty"`
+
+	// EphemeralVolume use generic ephemeral volumes to mount plot directories, eg. to bind node-local persistent volumes by StorageClass
+	// +optional
+	EphemeralVolume []*EphemeralVolumeConfig `json:"ephemeralVolume,omitempty"`
+
+	// ISCSIVolume use iSCSI disks to mount plot directories
+	// +optional
+	ISCSIVolume []*ISCSIVolumeConfig `json:"iscsiVolume,omitempty"`
+
+	// LocalVolume use disks attached to a node to mount plot directories. The operator creates a local PersistentVolume bound to the node and a claim for it,
+	// so the harvester pod is only scheduled to that node
+	// +optional
+	LocalVolume []*LocalVolumeConfig `json:"localVolume,omitempty"`
+
+	// PlotDirectories are the directories in the harvester container to add to harvester.plot_directories.
+	// Defaults to the mount path of each plot volume
+	// +optional
+	PlotDirectories []string `json:"plotDirectories,omitempty"`
+
+	// RecursiveScan defines whether the harvester searches plot directories recursively for plots.
+	// Turning this off avoids walking large nested directory trees, but then every directory containing plots must be listed in PlotDirectories
+	// +optional
+	// +kubebuilder:default=true
+	RecursiveScan *bool `json:"recursiveScan,omitempty"`
+}
+
+// PlotMountConfig defines how a plot volume is mounted in the harvester container. It is ignored for CHIA_ROOT volumes
+type PlotMountConfig struct {
+	// MountPath is the path the plot volume is mounted to in the harvester container. Defaults to /plots/<volume name>, eg. /plots/pvc-plots-0
+	// +optional
+	MountPath string `json:"mountPath,omitempty"`
+
+	// SubPath is a path within the volume to mount instead of the volume's root
+	// +optional
+	SubPath string `json:"subPath,omitempty"`
+
+	// ReadOnly defines whether the plot volume is mounted read-only
+	// +optional
+	// +kubebuilder:default=true
+	ReadOnly *bool `json:"readOnly,omitempty"`
 }
 
 // PersistentVolumeClaimConfig config for PVC volumes in kubernetes
 type PersistentVolumeClaimConfig struct {
-	// ClaimName is the name of an existing PersistentVolumeClaim in the target namespace
+	// ClaimName is the name of an existing PersistentVolumeClaim in the target namespace.
+	// If empty for CHIA_ROOT, the operator creates and owns a PVC from StorageClass, ResourceRequest and AccessModes. ChiaNodes always create one per replica
 	// +optional
 	ClaimName string `json:"claimName,omitempty"`
 
-	// StorageClass is the name of a storage class for the PVC -- this is only relevant for ChiaNode objects and is ignored for others
+	// StorageClass is the name of a storage class for a PVC created by the operator. If empty the cluster's default StorageClass is used, except for ChiaNodes where dynamic provisioning is disabled
 	// +kubebuilder:default=""
 	// +optional
 	StorageClass string `json:"storageClass,omitempty"`
 
-	// StorageClass is the amount of storage requested -- this is only relevant for ChiaNode objects and is ignored for others
+	// ResourceRequest is the amount of storage requested for a PVC created by the operator
 	// +optional
 	ResourceRequest string `json:"resourceRequest,omitempty"`
+
+	// AccessModes for a PVC created by the operator. Defaults to ReadWriteOnce
+	// +optional
+	AccessModes []corev1.PersistentVolumeAccessMode `json:"accessModes,omitempty"`
+
+	PlotMountConfig `json:",inline"`
 }
 
 // HostPathVolumeConfig config for hostPath volumes in kubernetes
@@ -113,4 +297,268 @@ type HostPathVolumeConfig struct {
 	// If a HostPath is used, it is highly recommended that a NodeSelector is used to keep the Pod on the host that has the directory to mount.
 	// +optional
 	Path string `json:"path,omitempty"`
+
+	// Type of the hostPath volume, eg. Directory, DirectoryOrCreate or BlockDevice. Defaults to no checks before mounting
+	// +optional
+	Type *corev1.HostPathType `json:"type,omitempty"`
+
+	PlotMountConfig `json:",inline"`
+}
+
+// NFSVolumeConfig config for NFS volumes in kubernetes
+type NFSVolumeConfig struct {
+	// Server is the hostname or IP address of the NFS server
+	Server string `json:"server"`
+
+	// Path is the path exported by the NFS server
+	Path string `json:"path"`
+
+	PlotMountConfig `json:",inline"`
+}
+
+// CSIVolumeConfig config for inline CSI volumes in kubernetes
+type CSIVolumeConfig struct {
+	// Driver is the name of the CSI driver that handles this volume
+	Driver string `json:"driver"`
+
+	// FSType is the filesystem type to mount, eg. ext4. If empty the CSI driver's default is used
+	// +optional
+	FSType *string `json:"fsType,omitempty"`
+
+	// VolumeAttributes are driver-specific properties passed to the CSI driver
+	// +optional
+	VolumeAttributes map[string]string `json:"volumeAttributes,omitempty"`
+
+	// NodePublishSecretRef is a reference to a Secret in the target namespace containing sensitive information passed to the CSI driver
+	// +optional
+	NodePublishSecretRef *corev1.LocalObjectReference `json:"nodePublishSecretRef,omitempty"`
+
+	PlotMountConfig `json:",inline"`
+}
+
+// EphemeralVolumeConfig config for generic ephemeral volumes in kubernetes, which are PVCs created and deleted along with the Pod
+type EphemeralVolumeConfig struct {
+	// StorageClass is the name of a storage class for the ephemeral PVC. Uses the cluster's default StorageClass if empty
+	// +optional
+	StorageClass string `json:"storageClass,omitempty"`
+
+	// ResourceRequest is the amount of storage requested, eg. 10Ti
+	ResourceRequest resource.Quantity `json:"resourceRequest"`
+
+	// AccessModes for the ephemeral PVC. Defaults to ReadWriteOnce
+	// +optional
+	AccessModes []corev1.PersistentVolumeAccessMode `json:"accessModes,omitempty"`
+
+	PlotMountConfig `json:",inline"`
+}
+
+// ISCSIVolumeConfig config for iSCSI volumes in kubernetes
+type ISCSIVolumeConfig struct {
+	// TargetPortal is the iSCSI target portal, either an IP or ip_addr:port
+	TargetPortal string `json:"targetPortal"`
+
+	// IQN is the iSCSI target's qualified name
+	IQN string `json:"iqn"`
+
+	// Lun is the iSCSI target lun number
+	Lun int32 `json:"lun"`
+
+	// FSType is the filesystem type of the disk, eg. ext4
+	// +optional
+	FSType string `json:"fsType,omitempty"`
+
+	// Portals is a list of additional iSCSI target portals
+	// +optional
+	Portals []string `json:"portals,omitempty"`
+
+	// ChapAuthDiscovery defines whether iSCSI discovery CHAP authentication is supported
+	// +optional
+	ChapAuthDiscovery bool `json:"chapAuthDiscovery,omitempty"`
+
+	// ChapAuthSession defines whether iSCSI session CHAP authentication is supported
+	// +optional
+	ChapAuthSession bool `json:"chapAuthSession,omitempty"`
+
+	// SecretRef is a reference to a Secret in the target namespace containing the CHAP credentials
+	// +optional
+	SecretRef *corev1.LocalObjectReference `json:"secretRef,omitempty"`
+
+	PlotMountConfig `json:",inline"`
+}
+
+// LocalVolumeConfig config for local persistent volumes in kubernetes, which are a disk or directory on one node
+type LocalVolumeConfig struct {
+	// Path is the path of the disk's mount point or directory on the node
+	Path string `json:"path"`
+
+	// NodeName is the name of the node the path is on. The created PersistentVolume's node affinity requires pods using it to run there
+	NodeName string `json:"nodeName"`
+
+	// Capacity is the size of the disk, eg. 18Ti
+	Capacity resource.Quantity `json:"capacity"`
+
+	// StorageClass is the storageClassName of the created PersistentVolume and its claim. Empty means no StorageClass
+	// +optional
+	StorageClass string `json:"storageClass,omitempty"`
+
+	PlotMountConfig `json:",inline"`
+}
+
+// NetworkPolicyConfig defines the NetworkPolicy created for a chia component, which only allows the traffic the component needs.
+// The operator is always allowed to reach the component's RPC port to report status
+type NetworkPolicyConfig struct {
+	// Enabled defines whether a NetworkPolicy should be created for this component
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// PeerFrom lists additional sources allowed to reach the component's peer port, such as off-cluster harvesters connecting to a farmer
+	// +optional
+	PeerFrom []networkingv1.NetworkPolicyPeer `json:"peerFrom,omitempty"`
+
+	// RPCFrom lists additional sources allowed to reach the component's daemon and RPC ports
+	// +optional
+	RPCFrom []networkingv1.NetworkPolicyPeer `json:"rpcFrom,omitempty"`
+
+	// MetricsFrom lists the sources allowed to reach the chia-exporter metrics port, such as Prometheus. Any source is allowed when empty
+	// +optional
+	MetricsFrom []networkingv1.NetworkPolicyPeer `json:"metricsFrom,omitempty"`
+}
+
+// ExposeConfig defines how a chia component's peer and RPC ports are exposed outside the cluster, in addition to its Service
+type ExposeConfig struct {
+	// Peers exposes the component's peer port
+	// +optional
+	Peers *PortExposureConfig `json:"peers,omitempty"`
+
+	// RPC exposes the component's RPC port
+	// +optional
+	RPC *PortExposureConfig `json:"rpc,omitempty"`
+}
+
+// PortExposureConfig defines the Gateway API route exposing one port of a chia component's Service.
+// There's no Ingress option, since chia peers and RPC clients authenticate with mutual TLS, which an HTTP Ingress terminating TLS breaks
+type PortExposureConfig struct {
+	// Gateway configures a Gateway API TCPRoute or TLSRoute to the port. It is only created if the Gateway API CRDs are installed in the cluster
+	// +optional
+	Gateway *GatewayRouteConfig `json:"gateway,omitempty"`
+}
+
+// GatewayRouteKind is the kind of Gateway API route created for a port
+// +kubebuilder:validation:Enum=TCPRoute;TLSRoute
+type GatewayRouteKind string
+
+const (
+	// GatewayTCPRoute routes all TCP traffic on a Gateway listener to the port
+	GatewayTCPRoute GatewayRouteKind = "TCPRoute"
+
+	// GatewayTLSRoute routes TLS traffic on a Gateway listener to the port by SNI hostname, without terminating TLS
+	GatewayTLSRoute GatewayRouteKind = "TLSRoute"
+)
+
+// GatewayRouteConfig defines a Gateway API route to a chia component port
+type GatewayRouteConfig struct {
+	// Enabled defines whether the route should be created
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Kind is the kind of route to create. TLSRoutes should attach to a listener in Passthrough mode, so chia's mutual TLS reaches the pod
+	// +kubebuilder:default="TCPRoute"
+	// +optional
+	Kind GatewayRouteKind `json:"kind,omitempty"`
+
+	// ParentRefs are the Gateways, and optionally their listeners, the route attaches to
+	ParentRefs []GatewayParentReference `json:"parentRefs"`
+
+	// Hostnames are the SNI hostnames a TLSRoute matches. Ignored for TCPRoutes
+	// +optional
+	Hostnames []string `json:"hostnames,omitempty"`
+
+	// Labels is a map of string keys and values to attach to the route
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations is a map of string keys and values to attach to the route
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// GatewayParentReference identifies a Gateway listener a route attaches to
+type GatewayParentReference struct {
+	// Name is the name of the Gateway
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the Gateway. Defaults to the route's namespace
+	// +optional
+	Namespace *string `json:"namespace,omitempty"`
+
+	// SectionName is the name of the Gateway listener to attach to
+	// +optional
+	SectionName *string `json:"sectionName,omitempty"`
+}
+
+// ChiaServicesConfig defines the Services a chia component's ports are exposed on. Each port gets a Service of its own,
+// so the peer port can be made public without also exposing the daemon and RPC ports
+type ChiaServicesConfig struct {
+	// Peers configures the peer port's Service, which is named after the component. Its type defaults to serviceType
+	// +optional
+	Peers *ChiaServiceConfig `json:"peers,omitempty"`
+
+	// Daemon configures the daemon port's Service, named <component>-daemon. Its type defaults to ClusterIP
+	// +optional
+	Daemon *ChiaServiceConfig `json:"daemon,omitempty"`
+
+	// RPC configures the RPC port's Service, named <component>-rpc. Its type defaults to ClusterIP
+	// +optional
+	RPC *ChiaServiceConfig `json:"rpc,omitempty"`
+
+	// IPFamilies are the IP families of every Service the component creates, eg. [IPv6] for IPv6-only or [IPv4, IPv6] for dual-stack clusters.
+	// The first family is the primary one. When it is IPv6, chia is configured to prefer IPv6 peers and bind its RPC and daemon servers to IPv6
+	// +kubebuilder:validation:MaxItems=2
+	// +optional
+	IPFamilies []corev1.IPFamily `json:"ipFamilies,omitempty"`
+
+	// IPFamilyPolicy is the IP family policy of every Service the component creates, such as PreferDualStack or RequireDualStack
+	// +optional
+	IPFamilyPolicy *corev1.IPFamilyPolicy `json:"ipFamilyPolicy,omitempty"`
+}
+
+// ChiaServiceConfig defines the Service for one of a chia component's ports
+type ChiaServiceConfig struct {
+	// Enabled defines whether the Service should be created. Defaults to true
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// ServiceType is the type of the Service
+	// +optional
+	ServiceType *corev1.ServiceType `json:"serviceType,omitempty"`
+
+	// ExternalTrafficPolicy sets whether external traffic is routed to node-local or cluster-wide endpoints. Only used for NodePort and LoadBalancer Services
+	// +optional
+	ExternalTrafficPolicy *corev1.ServiceExternalTrafficPolicy `json:"externalTrafficPolicy,omitempty"`
+
+	// NodePort is the fixed node port to allocate for the Service's port. Only used for NodePort and LoadBalancer Services
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	NodePort *int32 `json:"nodePort,omitempty"`
+
+	// LoadBalancerIP requests a specific IP address from the load balancer, for implementations that support it. Only used for LoadBalancer Services
+	// +optional
+	LoadBalancerIP string `json:"loadBalancerIP,omitempty"`
+
+	// LoadBalancerClass selects the load balancer implementation. Only used for LoadBalancer Services
+	// +optional
+	LoadBalancerClass *string `json:"loadBalancerClass,omitempty"`
+
+	// LoadBalancerSourceRanges restricts traffic through the load balancer to the given client CIDRs. Only used for LoadBalancer Services
+	// +optional
+	LoadBalancerSourceRanges []string `json:"loadBalancerSourceRanges,omitempty"`
+
+	// Labels is a map of string keys and values to attach to the Service
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations is a map of string keys and values to attach to the Service
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
 }
@@ -6,6 +6,7 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -24,11 +25,23 @@ type ChiaFarmerSpec struct {
 	// +optional
 	Storage *StorageConfig `json:"storage,omitempty"`
 
-	// ServiceType is the type of the service for the farmer instance
+	// ServiceType is the type of the Service for the peer port. The daemon and RPC ports have their own ClusterIP Services, configured in services
 	// +optional
 	// +kubebuilder:default="ClusterIP"
 	ServiceType string `json:"serviceType"`
 
+	// Services configures the separate Services for the farmer's peer, daemon and RPC ports
+	// +optional
+	Services *ChiaServicesConfig `json:"services,omitempty"`
+
+	// NetworkPolicy configures a NetworkPolicy that only allows the traffic the farmer needs
+	// +optional
+	NetworkPolicy *NetworkPolicyConfig `json:"networkPolicy,omitempty"`
+
+	// Expose configures Gateway API routes that expose the farmer's peer and RPC ports outside the cluster
+	// +optional
+	Expose *ExposeConfig `json:"expose,omitempty"`
+
 	// ImagePullPolicy is the pull policy for containers in the pod
 	// +optional
 	// +kubebuilder:default="Always"
@@ -43,6 +56,32 @@ type ChiaFarmerSpec struct {
 	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
 }
 
+// ChiaFarmerPoolSpec defines a plotNFT a farmer farms to
+type ChiaFarmerPoolSpec struct {
+	// LauncherID is the launcher ID of the plotNFT
+	// +kubebuilder:validation:Pattern=`^(0x)?[0-9a-fA-F]{64}$`
+	LauncherID string `json:"launcherID"`
+
+	// PoolURL is the URL of the pool the plotNFT is joined to. Empty when self pooling
+	// +optional
+	PoolURL string `json:"poolURL,omitempty"`
+
+	// PayoutInstructions tells the pool where to send pool rewards, usually a puzzle hash
+	PayoutInstructions string `json:"payoutInstructions"`
+
+	// TargetPuzzleHash is the puzzle hash pool rewards are claimed to
+	// +kubebuilder:validation:Pattern=`^(0x)?[0-9a-fA-F]{64}$`
+	TargetPuzzleHash string `json:"targetPuzzleHash"`
+
+	// P2SingletonPuzzleHash is the plotNFT's pay-to-singleton puzzle hash, which its plots' pool contract address encodes
+	// +kubebuilder:validation:Pattern=`^(0x)?[0-9a-fA-F]{64}$`
+	P2SingletonPuzzleHash string `json:"p2SingletonPuzzleHash"`
+
+	// OwnerPublicKey is the plotNFT's owner public key
+	// +kubebuilder:validation:Pattern=`^(0x)?[0-9a-fA-F]{96}$`
+	OwnerPublicKey string `json:"ownerPublicKey"`
+}
+
 // ChiaFarmerConfigSpec defines the desired state of Chia component configuration
 type ChiaFarmerConfigSpec struct {
 	// CASecretName is the name of the secret that contains the CA crt and key.
@@ -51,10 +90,29 @@ type ChiaFarmerConfigSpec struct {
 	// SecretKeySpec defines the k8s Secret name and key for a Chia mnemonic
 	SecretKeySpec ChiaKeysSpec `json:"secretKey"`
 
+	// SecretKeys defines additional k8s Secret names and keys for Chia mnemonics, so more than one key is added to the keychain
+	// +optional
+	SecretKeys []ChiaKeysSpec `json:"secretKeys,omitempty"`
+
 	// FullNodePeer defines the farmer's full_node peer in host:port format.
 	// In Kubernetes this is likely to be <node service name>.<namespace>.svc.cluster.local:8555
 	FullNodePeer string `json:"fullNodePeer"`
 
+	// Pools are the plotNFTs the farmer farms to, rendered into pool.pool_list in config.yaml in place of any pool_list from configOverrides.
+	// The pool authentication key is derived from each plotNFT's owner key, so the owner key's mnemonic must be one of the farmer's keys
+	// +optional
+	Pools []ChiaFarmerPoolSpec `json:"pools,omitempty"`
+
+	// FarmerRewardAddress is the xch (or txch on testnet) address farmer rewards are paid to, set as farmer.xch_target_address in config.yaml
+	// +kubebuilder:validation:Pattern=`^t?xch1[02-9ac-hj-np-z]{58}$`
+	// +optional
+	FarmerRewardAddress *string `json:"farmerRewardAddress,omitempty"`
+
+	// PoolRewardAddress is the xch (or txch on testnet) address pool rewards for non-plotNFT plots are paid to, set as pool.xch_target_address in config.yaml
+	// +kubebuilder:validation:Pattern=`^t?xch1[02-9ac-hj-np-z]{58}$`
+	// +optional
+	PoolRewardAddress *string `json:"poolRewardAddress,omitempty"`
+
 	// Testnet is set to true if the Chia container should switch to the latest default testnet's settings
 	// +optional
 	Testnet *bool `json:"testnet,omitempty"`
@@ -91,6 +149,10 @@ type ChiaFarmerConfigSpec struct {
 	// SecurityContext defines the security context for the chia container
 	// +optional
 	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
+
+	// ConfigOverrides defines YAML documents that are deep-merged into the chia config.yaml on each container start
+	// +optional
+	ConfigOverrides *ChiaConfigOverridesSpec `json:"configOverrides,omitempty"`
 }
 
 // ChiaFarmerStatus defines the observed state of ChiaFarmer
@@ -98,10 +160,173 @@ type ChiaFarmerStatus struct {
 	// Ready says whether the node is ready, this should be true when the node statefulset is in the target namespace
 	// +kubebuilder:default=false
 	Ready bool `json:"ready,omitempty"`
+
+	// ConnectedHarvesters is the number of harvesters connected to the farmer
+	// +optional
+	ConnectedHarvesters int32 `json:"connectedHarvesters,omitempty"`
+
+	// TotalPlots is the number of plots across every connected harvester
+	// +optional
+	TotalPlots int32 `json:"totalPlots,omitempty"`
+
+	// Harvesters lists the harvesters connected to the farmer
+	// +optional
+	Harvesters []ChiaFarmerHarvesterStatus `json:"harvesters,omitempty"`
+
+	// Pools lists the state of each pool (plotNFT) the farmer is farming to
+	// +optional
+	Pools []ChiaFarmerPoolStatus `json:"pools,omitempty"`
+
+	// LastSignagePoint is the most recent signage point the farmer has received
+	// +optional
+	LastSignagePoint *ChiaFarmerSignagePointStatus `json:"lastSignagePoint,omitempty"`
+
+	// RecentProofs is the number of proofs found for the signage points the farmer currently holds in memory
+	// +optional
+	RecentProofs int32 `json:"recentProofs,omitempty"`
+
+	// RewardTargets are the addresses the farmer sends farming rewards to
+	// +optional
+	RewardTargets *ChiaFarmerRewardTargetsStatus `json:"rewardTargets,omitempty"`
+
+	// LastStatusUpdateTime is the last time the farm status was gathered
+	// +optional
+	LastStatusUpdateTime *metav1.Time `json:"lastStatusUpdateTime,omitempty"`
+
+	// Conditions represent the latest observations of the ChiaFarmer's state
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+const (
+	// ChiaFarmerConditionRewardAddressesValid is false when farmerRewardAddress or poolRewardAddress isn't a valid address for the farmer's network,
+	// in which case the farmer isn't deployed
+	ChiaFarmerConditionRewardAddressesValid = "RewardAddressesValid"
+)
+
+// ChiaFarmerHarvesterStatus defines the observed state of a harvester connected to a farmer
+type ChiaFarmerHarvesterStatus struct {
+	// NodeID is the harvester's peer node ID
+	NodeID string `json:"nodeID"`
+
+	// Host is the address the harvester connected from
+	Host string `json:"host"`
+
+	// Plots is the number of plots the harvester reports
+	Plots int32 `json:"plots"`
+
+	// EffectiveSize is the total uncompressed size of the harvester's plots
+	// +optional
+	EffectiveSize *resource.Quantity `json:"effectiveSize,omitempty"`
+
+	// Syncing is true while the harvester is still sending its initial plot list to the farmer
+	// +optional
+	Syncing bool `json:"syncing,omitempty"`
+}
+
+// ChiaFarmerPoolStatus defines the observed state of a pool a farmer is farming to
+type ChiaFarmerPoolStatus struct {
+	// LauncherID is the launcher ID of the plotNFT
+	LauncherID string `json:"launcherID"`
+
+	// PoolURL is the URL of the pool the plotNFT is joined to. Empty when self pooling
+	// +optional
+	PoolURL string `json:"poolURL,omitempty"`
+
+	// State is whether the farmer is connected to the pool
+	// +optional
+	State ChiaFarmerPoolConnectionState `json:"state,omitempty"`
+
+	// PlotCount is the number of plots farming to this plotNFT
+	// +optional
+	PlotCount int32 `json:"plotCount,omitempty"`
+
+	// CurrentPoints is the farmer's current point balance with the pool
+	// +optional
+	CurrentPoints int64 `json:"currentPoints,omitempty"`
+
+	// CurrentDifficulty is the partial difficulty the pool has set for the farmer
+	// +optional
+	CurrentDifficulty *int64 `json:"currentDifficulty,omitempty"`
+
+	// PointsFoundSinceStart is the number of points found since the farmer started
+	// +optional
+	PointsFoundSinceStart int64 `json:"pointsFoundSinceStart,omitempty"`
+
+	// PointsAcknowledgedSinceStart is the number of points the pool has acknowledged since the farmer started
+	// +optional
+	PointsAcknowledgedSinceStart int64 `json:"pointsAcknowledgedSinceStart,omitempty"`
+
+	// PointsFound24h is the number of points found in the last 24 hours
+	// +optional
+	PointsFound24h int64 `json:"pointsFound24h,omitempty"`
+
+	// PointsAcknowledged24h is the number of points the pool acknowledged in the last 24 hours
+	// +optional
+	PointsAcknowledged24h int64 `json:"pointsAcknowledged24h,omitempty"`
+
+	// PoolErrors24h is the number of errors the pool returned in the last 24 hours
+	// +optional
+	PoolErrors24h int32 `json:"poolErrors24h,omitempty"`
+
+	// LastPoolError is the most recent error the pool returned in the last 24 hours
+	// +optional
+	LastPoolError string `json:"lastPoolError,omitempty"`
+}
+
+// ChiaFarmerPoolConnectionState describes whether a farmer is connected to a plotNFT's pool
+// +kubebuilder:validation:Enum=Connected;Disconnected;SelfPooling
+type ChiaFarmerPoolConnectionState string
+
+const (
+	// ChiaFarmerPoolConnected means the farmer has fetched the pool's info and can submit partials
+	ChiaFarmerPoolConnected ChiaFarmerPoolConnectionState = "Connected"
+
+	// ChiaFarmerPoolDisconnected means the farmer hasn't been able to reach the pool yet
+	ChiaFarmerPoolDisconnected ChiaFarmerPoolConnectionState = "Disconnected"
+
+	// ChiaFarmerPoolSelfPooling means the plotNFT isn't joined to a pool
+	ChiaFarmerPoolSelfPooling ChiaFarmerPoolConnectionState = "SelfPooling"
+)
+
+// ChiaFarmerSignagePointStatus defines the observed state of a signage point received by a farmer
+type ChiaFarmerSignagePointStatus struct {
+	// ChallengeChainSP is the signage point's challenge chain hash
+	ChallengeChainSP string `json:"challengeChainSP"`
+
+	// Index is the signage point's index within its sub-slot
+	Index int32 `json:"index"`
+
+	// PeakHeight is the blockchain peak height when the signage point was made
+	// +optional
+	PeakHeight int64 `json:"peakHeight,omitempty"`
+
+	// ObservedTime is when the operator first saw this signage point. Signage points arrive every few seconds, so this is only as precise as the status refresh interval
+	ObservedTime metav1.Time `json:"observedTime"`
+}
+
+// ChiaFarmerRewardTargetsStatus defines the observed reward addresses of a farmer
+type ChiaFarmerRewardTargetsStatus struct {
+	// FarmerRewardAddress is the address farmer rewards are sent to
+	// +optional
+	FarmerRewardAddress string `json:"farmerRewardAddress,omitempty"`
+
+	// PoolRewardAddress is the address pool rewards are sent to for plots not farming to a plotNFT
+	// +optional
+	PoolRewardAddress string `json:"poolRewardAddress,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=boolean,JSONPath=`.status.ready`
+//+kubebuilder:printcolumn:name="Harvesters",type=integer,JSONPath=`.status.connectedHarvesters`
+//+kubebuilder:printcolumn:name="Plots",type=integer,JSONPath=`.status.totalPlots`
+//+kubebuilder:printcolumn:name="Recent Proofs",type=integer,JSONPath=`.status.recentProofs`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // ChiaFarmer is the Schema for the chiafarmers API
 type ChiaFarmer struct {
@@ -6,6 +6,7 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -24,11 +25,23 @@ type ChiaHarvesterSpec struct {
 	// +optional
 	Storage *StorageConfig `json:"storage,omitempty"`
 
-	// ServiceType is the type of the service for the harvester instance
+	// ServiceType is the type of the Service for the peer port. The daemon and RPC ports have their own ClusterIP Services, configured in services
 	// +optional
 	// +kubebuilder:default="ClusterIP"
 	ServiceType string `json:"serviceType"`
 
+	// Services configures the separate Services for the harvester's peer, daemon and RPC ports
+	// +optional
+	Services *ChiaServicesConfig `json:"services,omitempty"`
+
+	// NetworkPolicy configures a NetworkPolicy that only allows the traffic the harvester needs
+	// +optional
+	NetworkPolicy *NetworkPolicyConfig `json:"networkPolicy,omitempty"`
+
+	// Expose configures Gateway API routes that expose the harvester's peer and RPC ports outside the cluster
+	// +optional
+	Expose *ExposeConfig `json:"expose,omitempty"`
+
 	// ImagePullPolicy is the pull policy for containers in the pod
 	// +optional
 	// +kubebuilder:default="Always"
@@ -41,6 +54,45 @@ type ChiaHarvesterSpec struct {
 	// PodSecurityContext defines the security context for the pod
 	// +optional
 	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
+
+	// Mode is the kind of workload the harvester runs as. A Deployment runs a single harvester pod.
+	// A DaemonSet runs a harvester on every node matching the NodeSelector, each discovering the plots on its own node.
+	// +optional
+	// +kubebuilder:default="Deployment"
+	// +kubebuilder:validation:Enum=Deployment;DaemonSet
+	Mode ChiaHarvesterMode `json:"mode,omitempty"`
+
+	// PlotDiscovery configures how each harvester pod finds plot directories on its node. Only used in DaemonSet mode
+	// +optional
+	PlotDiscovery *PlotDiscoveryConfig `json:"plotDiscovery,omitempty"`
+}
+
+// ChiaHarvesterMode is the kind of workload a harvester runs as
+type ChiaHarvesterMode string
+
+const (
+	// ChiaHarvesterModeDeployment runs a single harvester pod in a Deployment
+	ChiaHarvesterModeDeployment ChiaHarvesterMode = "Deployment"
+
+	// ChiaHarvesterModeDaemonSet runs a harvester pod on every selected node in a DaemonSet
+	ChiaHarvesterModeDaemonSet ChiaHarvesterMode = "DaemonSet"
+)
+
+// PlotDiscoveryConfig defines how harvester pods in DaemonSet mode find plot directories on their node
+type PlotDiscoveryConfig struct {
+	// HostRoot is the directory on each node that plot disks are mounted under. It is mounted read-only into the harvester pod at the same path
+	// +optional
+	// +kubebuilder:default="/mnt"
+	HostRoot string `json:"hostRoot,omitempty"`
+
+	// PathGlobs are shell glob patterns matched on each node to find plot directories, eg. "/mnt/disk*/plots". They must be under HostRoot
+	// +optional
+	PathGlobs []string `json:"pathGlobs,omitempty"`
+
+	// NodeAnnotation is the key of a Node annotation listing a node's plot directories, separated by commas. They must be under HostRoot
+	// +optional
+	// +kubebuilder:default="k8s.chia.net/plot-directories"
+	NodeAnnotation string `json:"nodeAnnotation,omitempty"`
 }
 
 // ChiaHarvesterConfigSpec defines the desired state of Chia component configuration
@@ -52,6 +104,11 @@ type ChiaHarvesterConfigSpec struct {
 	// In Kubernetes this is likely to be <farmer service name>.<namespace>.svc.cluster.local
 	FarmerAddress string `json:"farmerAddress"`
 
+	// FarmerRPCAddress is the hostname of the farmer's RPC server, which the operator reads the harvester's duplicate and no-key plot counts from.
+	// Defaults to farmerAddress, pointed at the ChiaFarmer's <name>-farmer-rpc Service when farmerAddress names its <name>-farmer Service
+	// +optional
+	FarmerRPCAddress *string `json:"farmerRPCAddress,omitempty"`
+
 	// Testnet is set to true if the Chia container should switch to the latest default testnet's settings
 	// +optional
 	Testnet *bool `json:"testnet,omitempty"`
@@ -88,6 +145,10 @@ type ChiaHarvesterConfigSpec struct {
 	// SecurityContext defines the security context for the chia container
 	// +optional
 	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
+
+	// ConfigOverrides defines YAML documents that are deep-merged into the chia config.yaml on each container start
+	// +optional
+	ConfigOverrides *ChiaConfigOverridesSpec `json:"configOverrides,omitempty"`
 }
 
 // ChiaHarvesterStatus defines the observed state of ChiaHarvester
@@ -95,10 +156,92 @@ type ChiaHarvesterStatus struct {
 	// Ready says whether the node is ready, this should be true when the node statefulset is in the target namespace
 	// +kubebuilder:default=false
 	Ready bool `json:"ready,omitempty"`
+
+	// Plots reports the plots the harvester has loaded, as seen through its RPC server
+	// +optional
+	Plots *ChiaHarvesterPlotsStatus `json:"plots,omitempty"`
+
+	// LastStatusUpdateTime is the last time the plot inventory was gathered
+	// +optional
+	LastStatusUpdateTime *metav1.Time `json:"lastStatusUpdateTime,omitempty"`
+
+	// Conditions represent the latest observations of the ChiaHarvester's state
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+const (
+	// ChiaHarvesterConditionPlotsDropped is true when the harvester's plot count is below the highest count seen since the spec last changed, or plots failed to open
+	ChiaHarvesterConditionPlotsDropped = "PlotsDropped"
+
+	// ChiaHarvesterConditionPlotDirectoriesSkipped is true in DaemonSet mode when plot discovery path globs or node-annotated plot directories
+	// are skipped because they aren't under the plot discovery hostRoot mounted into the harvester pods
+	ChiaHarvesterConditionPlotDirectoriesSkipped = "PlotDirectoriesSkipped"
+
+	// ChiaHarvesterConditionDaemonSetStorageValid is false in DaemonSet mode when CHIA_ROOT is a PersistentVolumeClaim every DaemonSet pod would share,
+	// in which case the harvester isn't deployed
+	ChiaHarvesterConditionDaemonSetStorageValid = "DaemonSetStorageValid"
+)
+
+// ChiaHarvesterPlotsStatus defines the observed plot inventory of a ChiaHarvester
+type ChiaHarvesterPlotsStatus struct {
+	// TotalPlots is the number of plots the harvester has loaded
+	TotalPlots int32 `json:"totalPlots"`
+
+	// ExpectedPlots is the highest plot count reported while every harvester pod was running and done loading plots, since the spec last changed.
+	// PlotsDropped stays true while a complete count is below it
+	// +optional
+	ExpectedPlots int32 `json:"expectedPlots,omitempty"`
+
+	// RawSize is the total size of the loaded plot files on disk
+	RawSize resource.Quantity `json:"rawSize"`
+
+	// EffectiveSize is the total uncompressed size of the loaded plots, which is what they are worth when farming
+	EffectiveSize resource.Quantity `json:"effectiveSize"`
+
+	// FailedToOpen is the number of plot files the harvester found but could not open
+	// +optional
+	FailedToOpen int32 `json:"failedToOpen,omitempty"`
+
+	// Duplicates is the number of duplicate plots the farmer reports for this harvester. Unset if the farmer couldn't be queried
+	// +optional
+	Duplicates *int32 `json:"duplicates,omitempty"`
+
+	// NoKey is the number of plots the farmer reports it has no keys for on this harvester. Unset if the farmer couldn't be queried
+	// +optional
+	NoKey *int32 `json:"noKey,omitempty"`
+
+	// Directories breaks down the loaded plots by the volume they were found on
+	// +optional
+	Directories []ChiaHarvesterPlotDirectoryStatus `json:"directories,omitempty"`
+}
+
+// ChiaHarvesterPlotDirectoryStatus defines the observed plots in a single plot directory
+type ChiaHarvesterPlotDirectoryStatus struct {
+	// Path is the plot directory's mount path in the harvester container
+	Path string `json:"path"`
+
+	// Volume is the name of the pod volume mounted at Path, eg. pvc-plots-0 or hostpath-plots-1. Empty if the plots aren't on a mounted volume
+	// +optional
+	Volume string `json:"volume,omitempty"`
+
+	// Plots is the number of loaded plots in this directory
+	Plots int32 `json:"plots"`
+
+	// RawSize is the total size of the loaded plot files in this directory
+	RawSize resource.Quantity `json:"rawSize"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=boolean,JSONPath=`.status.ready`
+//+kubebuilder:printcolumn:name="Plots",type=integer,JSONPath=`.status.plots.totalPlots`
+//+kubebuilder:printcolumn:name="Effective Size",type=string,JSONPath=`.status.plots.effectiveSize`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // ChiaHarvester is the Schema for the chiaharvesters API
 type ChiaHarvester struct {
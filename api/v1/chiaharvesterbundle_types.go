@@ -0,0 +1,92 @@
+/*
+Copyright 2023 Chia Network Inc.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ChiaHarvesterBundleSpec defines the desired state of ChiaHarvesterBundle
+type ChiaHarvesterBundleSpec struct {
+	AdditionalMetadata `json:",inline"`
+
+	// CASecretName is the name of the secret that contains the CA crt and key the harvester certificate is signed with
+	CASecretName string `json:"caSecretName"`
+
+	// Secret defines the name of the secret to contain the harvester's certificates and config
+	Secret string `json:"secret"`
+
+	// FarmerPeer defines the farmer address the off-cluster harvester connects to
+	FarmerPeer ChiaHarvesterBundleFarmerPeerSpec `json:"farmerPeer"`
+}
+
+// ChiaHarvesterBundleFarmerPeerSpec defines the external address of a farmer, as reachable by harvesters outside the cluster
+type ChiaHarvesterBundleFarmerPeerSpec struct {
+	// Host is the farmer's external hostname or IP address
+	// +optional
+	Host string `json:"host,omitempty"`
+
+	// ChiaFarmerName is the name of a ChiaFarmer in this namespace whose Service's load balancer address is used when host is unset.
+	// The ChiaFarmer's peer Service must be a LoadBalancer, and if the ChiaFarmer has a NetworkPolicy, its networkPolicy.peerFrom must allow the harvesters' addresses
+	// +optional
+	ChiaFarmerName string `json:"chiaFarmerName,omitempty"`
+
+	// Port is the farmer's external port
+	// +kubebuilder:default=8447
+	// +optional
+	Port int32 `json:"port,omitempty"`
+}
+
+// ChiaHarvesterBundleStatus defines the observed state of ChiaHarvesterBundle
+type ChiaHarvesterBundleStatus struct {
+	// Ready says whether the bundle is ready, this should be true when the bundle secret is in the target namespace
+	// +kubebuilder:default=false
+	Ready bool `json:"ready,omitempty"`
+
+	// FarmerPeer is the farmer address in host:port format written to the bundle's config
+	// +optional
+	FarmerPeer string `json:"farmerPeer,omitempty"`
+
+	// Conditions represent the latest observations of the ChiaHarvesterBundle's state
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+const (
+	// ChiaHarvesterBundleConditionFarmerPeerResolved is true when the farmer address is known, and false with the reason while waiting for the ChiaFarmer's load balancer
+	ChiaHarvesterBundleConditionFarmerPeerResolved = "FarmerPeerResolved"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=boolean,JSONPath=`.status.ready`
+//+kubebuilder:printcolumn:name="Farmer Peer",type=string,JSONPath=`.status.farmerPeer`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// ChiaHarvesterBundle is the Schema for the chiaharvesterbundles API
+type ChiaHarvesterBundle struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ChiaHarvesterBundleSpec   `json:"spec,omitempty"`
+	Status ChiaHarvesterBundleStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ChiaHarvesterBundleList contains a list of ChiaHarvesterBundle
+type ChiaHarvesterBundleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ChiaHarvesterBundle `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ChiaHarvesterBundle{}, &ChiaHarvesterBundleList{})
+}
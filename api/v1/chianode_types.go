@@ -6,6 +6,7 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -24,11 +25,27 @@ type ChiaNodeSpec struct {
 	// +optional
 	Storage *StorageConfig `json:"storage,omitempty"`
 
-	// ServiceType is the type of the service that governs this ChiaNode StatefulSet.
+	// DatabaseBootstrap restores a blockchain database into CHIA_ROOT/db when a replica's volume doesn't have one yet, instead of syncing from scratch
+	// +optional
+	DatabaseBootstrap *ChiaNodeDatabaseBootstrapSpec `json:"databaseBootstrap,omitempty"`
+
+	// ServiceType is the type of the Service for the peer port. The daemon and RPC ports have their own ClusterIP Services, configured in services
 	// +optional
 	// +kubebuilder:default="ClusterIP"
 	ServiceType string `json:"serviceType"`
 
+	// Services configures the separate Services for the node's peer, daemon and RPC ports
+	// +optional
+	Services *ChiaServicesConfig `json:"services,omitempty"`
+
+	// NetworkPolicy configures a NetworkPolicy that only allows the traffic the node needs
+	// +optional
+	NetworkPolicy *NetworkPolicyConfig `json:"networkPolicy,omitempty"`
+
+	// Expose configures Gateway API routes that expose the node's peer and RPC ports outside the cluster
+	// +optional
+	Expose *ExposeConfig `json:"expose,omitempty"`
+
 	// ImagePullPolicy is the pull policy for containers in the pod
 	// +optional
 	// +kubebuilder:default="Always"
@@ -39,6 +56,12 @@ type ChiaNodeSpec struct {
 	// +kubebuilder:default=1
 	Replicas *int32 `json:"replicas,omitempty"`
 
+	// UpdatePolicy defines how changes to immutable StatefulSet fields, such as its selector, serviceName or volumeClaimTemplates, are applied.
+	// Update leaves the immutable fields as they are. OrphanRecreate deletes the StatefulSet while keeping its pods and PVCs, and recreates it so they are adopted. Defaults to Update
+	// +optional
+	// +kubebuilder:default=Update
+	UpdatePolicy ChiaNodeUpdatePolicy `json:"updatePolicy,omitempty"`
+
 	// NodeSelector selects a node by key value pairs
 	// +optional
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
@@ -48,6 +71,55 @@ type ChiaNodeSpec struct {
 	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
 }
 
+// ChiaNodeDatabaseBootstrapSpec defines where a ChiaNode's blockchain database is restored from.
+// Only one source should be set. They are respected in the order VolumeSnapshot, PersistentVolumeClaim, URL
+type ChiaNodeDatabaseBootstrapSpec struct {
+	// VolumeSnapshot provisions each new replica's CHIA_ROOT PersistentVolumeClaim from a VolumeSnapshot of another CHIA_ROOT volume.
+	// Requires CHIA_ROOT to use storage.chiaRoot.persistentVolumeClaim. Existing claims keep their data, and since the StatefulSet's volumeClaimTemplates can't change,
+	// setting it on an existing ChiaNode only takes effect for claims created after the StatefulSet is recreated with updatePolicy OrphanRecreate
+	// +optional
+	VolumeSnapshot *DatabaseBootstrapVolumeSnapshotSource `json:"volumeSnapshot,omitempty"`
+
+	// PersistentVolumeClaim copies the database, or a tar archive containing it, from an existing PersistentVolumeClaim in the target namespace
+	// +optional
+	PersistentVolumeClaim *DatabaseBootstrapPersistentVolumeClaimSource `json:"persistentVolumeClaim,omitempty"`
+
+	// URL downloads a tar archive of the database from an HTTP or HTTPS endpoint, such as an S3-compatible bucket or a server in the cluster
+	// +optional
+	URL *DatabaseBootstrapURLSource `json:"url,omitempty"`
+
+	// SHA256 is the expected hex-encoded SHA-256 checksum of the downloaded archive, or of the copied file for PersistentVolumeClaim sources.
+	// The restore fails if it doesn't match
+	// +optional
+	SHA256 string `json:"sha256,omitempty"`
+
+	// Image is the image of the init container that restores the database. It needs sh, wget, sha256sum and tar. Defaults to alpine
+	// +optional
+	Image string `json:"image,omitempty"`
+}
+
+// DatabaseBootstrapVolumeSnapshotSource references a VolumeSnapshot to restore a ChiaNode's database from
+type DatabaseBootstrapVolumeSnapshotSource struct {
+	// Name of a VolumeSnapshot in the target namespace
+	Name string `json:"name"`
+}
+
+// DatabaseBootstrapPersistentVolumeClaimSource references a PersistentVolumeClaim to copy a ChiaNode's database from
+type DatabaseBootstrapPersistentVolumeClaimSource struct {
+	// ClaimName is the name of an existing PersistentVolumeClaim in the target namespace
+	ClaimName string `json:"claimName"`
+
+	// Path is the path of the database file within the claim, eg. db/blockchain_v2_mainnet.sqlite.
+	// Files not ending in .sqlite are extracted as a tar archive, optionally gzipped, containing the database
+	Path string `json:"path"`
+}
+
+// DatabaseBootstrapURLSource is an HTTP or HTTPS endpoint to download a ChiaNode's database from
+type DatabaseBootstrapURLSource struct {
+	// URL of a tar archive, optionally gzip compressed, containing a blockchain_v2_<network>.sqlite database file
+	URL string `json:"url"`
+}
+
 // ChiaConfigSpec defines the desired state of Chia component configuration
 type ChiaNodeConfigSpec struct {
 	// Image defines the image to use for the chia component containers
@@ -89,6 +161,10 @@ type ChiaNodeConfigSpec struct {
 	// SecurityContext defines the security context for the chia container
 	// +optional
 	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
+
+	// ConfigOverrides defines YAML documents that are deep-merged into the chia config.yaml on each container start
+	// +optional
+	ConfigOverrides *ChiaConfigOverridesSpec `json:"configOverrides,omitempty"`
 }
 
 // ChiaNodeStatus defines the observed state of ChiaNode
@@ -96,10 +172,185 @@ type ChiaNodeStatus struct {
 	// Ready says whether the node is ready, this should be true when the node statefulset is in the target namespace
 	// +kubebuilder:default=false
 	Ready bool `json:"ready,omitempty"`
+
+	// Replicas reports the sync status of each running full_node replica, as seen through its RPC server
+	// +optional
+	Replicas []ChiaNodeReplicaStatus `json:"replicas,omitempty"`
+
+	// SyncedReplicas is the number of full_node replicas that report being synced
+	// +optional
+	SyncedReplicas int32 `json:"syncedReplicas,omitempty"`
+
+	// LastStatusUpdateTime is the last time the replica statuses were gathered
+	// +optional
+	LastStatusUpdateTime *metav1.Time `json:"lastStatusUpdateTime,omitempty"`
+
+	// Volumes reports the size of each replica's CHIA_ROOT PersistentVolumeClaim and the progress of any expansion
+	// +optional
+	Volumes []ChiaNodeVolumeStatus `json:"volumes,omitempty"`
+
+	// DatabaseBootstrap reports the progress of restoring the blockchain database on each replica
+	// +optional
+	DatabaseBootstrap []ChiaNodeDatabaseBootstrapStatus `json:"databaseBootstrap,omitempty"`
+
+	// Conditions represent the latest observations of the ChiaNode's state
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+// ChiaNodeUpdatePolicy describes how changes to immutable StatefulSet fields are applied
+// +kubebuilder:validation:Enum=Update;OrphanRecreate
+type ChiaNodeUpdatePolicy string
+
+const (
+	// ChiaNodeUpdatePolicyUpdate only updates mutable StatefulSet fields, and reports changes to immutable fields in Events
+	ChiaNodeUpdatePolicyUpdate ChiaNodeUpdatePolicy = "Update"
+
+	// ChiaNodeUpdatePolicyOrphanRecreate deletes the StatefulSet without its pods and PVCs when immutable fields change, and recreates it
+	ChiaNodeUpdatePolicyOrphanRecreate ChiaNodeUpdatePolicy = "OrphanRecreate"
+)
+
+// ChiaNodeSyncMode describes whether a full_node is synced to the blockchain
+// +kubebuilder:validation:Enum=Synced;Syncing;NotSynced;Unknown
+type ChiaNodeSyncMode string
+
+const (
+	// ChiaNodeSynced means the full_node is synced to the blockchain
+	ChiaNodeSynced ChiaNodeSyncMode = "Synced"
+
+	// ChiaNodeSyncing means the full_node is catching up to its peers
+	ChiaNodeSyncing ChiaNodeSyncMode = "Syncing"
+
+	// ChiaNodeNotSynced means the full_node is neither synced nor syncing, which usually means it has no peers
+	ChiaNodeNotSynced ChiaNodeSyncMode = "NotSynced"
+
+	// ChiaNodeSyncUnknown means the full_node's RPC server could not be reached
+	ChiaNodeSyncUnknown ChiaNodeSyncMode = "Unknown"
+)
+
+const (
+	// ChiaNodeConditionInSync is true when at least one full_node replica is synced to the blockchain
+	ChiaNodeConditionInSync = "InSync"
+
+	// ChiaNodeConditionVolumesExpanded is true when every replica's CHIA_ROOT PersistentVolumeClaim has the requested capacity
+	ChiaNodeConditionVolumesExpanded = "VolumesExpanded"
+
+	// ChiaNodeConditionStatefulSetUpToDate is false when the ChiaNode changes immutable StatefulSet fields that are only applied once the StatefulSet is recreated
+	ChiaNodeConditionStatefulSetUpToDate = "StatefulSetUpToDate"
+
+	// ChiaNodeConditionDatabaseBootstrapApplied is false when the StatefulSet's volumeClaimTemplates don't provision claims from the configured VolumeSnapshot
+	ChiaNodeConditionDatabaseBootstrapApplied = "DatabaseBootstrapApplied"
+)
+
+// ChiaNodeDatabaseBootstrapPhase describes the progress of restoring a replica's blockchain database
+// +kubebuilder:validation:Enum=Pending;Restoring;Completed;Skipped;Failed
+type ChiaNodeDatabaseBootstrapPhase string
+
+const (
+	// ChiaNodeDatabaseBootstrapPending means the restore hasn't started yet
+	ChiaNodeDatabaseBootstrapPending ChiaNodeDatabaseBootstrapPhase = "Pending"
+
+	// ChiaNodeDatabaseBootstrapRestoring means the database is being downloaded or copied and verified
+	ChiaNodeDatabaseBootstrapRestoring ChiaNodeDatabaseBootstrapPhase = "Restoring"
+
+	// ChiaNodeDatabaseBootstrapCompleted means the database was restored
+	ChiaNodeDatabaseBootstrapCompleted ChiaNodeDatabaseBootstrapPhase = "Completed"
+
+	// ChiaNodeDatabaseBootstrapSkipped means the volume already had a database, so nothing was restored
+	ChiaNodeDatabaseBootstrapSkipped ChiaNodeDatabaseBootstrapPhase = "Skipped"
+
+	// ChiaNodeDatabaseBootstrapFailed means the last restore attempt failed, such as on a checksum mismatch
+	ChiaNodeDatabaseBootstrapFailed ChiaNodeDatabaseBootstrapPhase = "Failed"
+)
+
+// ChiaNodeDatabaseBootstrapStatus defines the observed progress of restoring a single replica's blockchain database
+type ChiaNodeDatabaseBootstrapStatus struct {
+	// PodName is the name of the replica's pod
+	PodName string `json:"podName"`
+
+	// Phase is the progress of the restore
+	Phase ChiaNodeDatabaseBootstrapPhase `json:"phase"`
+
+	// Message describes the result of the restore, or why it failed
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ChiaNodeVolumeState describes the progress of a CHIA_ROOT PersistentVolumeClaim towards its requested size
+// +kubebuilder:validation:Enum=Ready;Resizing;FileSystemResizePending;ExpansionNotSupported
+type ChiaNodeVolumeState string
+
+const (
+	// ChiaNodeVolumeReady means the claim has the requested capacity
+	ChiaNodeVolumeReady ChiaNodeVolumeState = "Ready"
+
+	// ChiaNodeVolumeResizing means the claim's volume is being expanded by its storage provider
+	ChiaNodeVolumeResizing ChiaNodeVolumeState = "Resizing"
+
+	// ChiaNodeVolumeFileSystemResizePending means the volume was expanded and its filesystem will be resized when the pod next starts
+	ChiaNodeVolumeFileSystemResizePending ChiaNodeVolumeState = "FileSystemResizePending"
+
+	// ChiaNodeVolumeExpansionNotSupported means the claim is smaller than requested but its StorageClass doesn't allow volume expansion
+	ChiaNodeVolumeExpansionNotSupported ChiaNodeVolumeState = "ExpansionNotSupported"
+)
+
+// ChiaNodeVolumeStatus defines the observed state of a replica's CHIA_ROOT PersistentVolumeClaim
+type ChiaNodeVolumeStatus struct {
+	// ClaimName is the name of the replica's PersistentVolumeClaim
+	ClaimName string `json:"claimName"`
+
+	// Requested is the storage requested on the claim
+	// +optional
+	Requested *resource.Quantity `json:"requested,omitempty"`
+
+	// Capacity is the actual capacity of the claim's volume
+	// +optional
+	Capacity *resource.Quantity `json:"capacity,omitempty"`
+
+	// State is the progress of the claim towards the ChiaNode's requested size
+	State ChiaNodeVolumeState `json:"state"`
+}
+
+// ChiaNodeReplicaStatus defines the observed state of a single full_node replica
+type ChiaNodeReplicaStatus struct {
+	// PodName is the name of the replica's pod
+	PodName string `json:"podName"`
+
+	// NodeID is the replica's peer node ID
+	// +optional
+	NodeID string `json:"nodeID,omitempty"`
+
+	// PeakHeight is the height of the replica's blockchain peak
+	// +optional
+	PeakHeight int64 `json:"peakHeight,omitempty"`
+
+	// SyncMode is whether the replica is synced, syncing, or neither
+	SyncMode ChiaNodeSyncMode `json:"syncMode"`
+
+	// SyncProgressPercent is how far through syncing to its peers' peak the replica is, from 0 to 100
+	// +optional
+	SyncProgressPercent int32 `json:"syncProgressPercent,omitempty"`
+
+	// Peers is the number of full_node peers the replica is connected to
+	// +optional
+	Peers int32 `json:"peers,omitempty"`
+
+	// DatabaseVersion is the schema version of the replica's blockchain database, eg. 2 for blockchain_v2_mainnet.sqlite,
+	// read from the version in the full_node's configured database_path
+	// +optional
+	DatabaseVersion int32 `json:"databaseVersion,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=boolean,JSONPath=`.status.ready`
+//+kubebuilder:printcolumn:name="InSync",type=string,JSONPath=`.status.conditions[?(@.type=="InSync")].status`
+//+kubebuilder:printcolumn:name="Synced Replicas",type=integer,JSONPath=`.status.syncedReplicas`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // ChiaNode is the Schema for the chianodes API
 type ChiaNode struct {
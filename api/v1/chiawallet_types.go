@@ -24,11 +24,23 @@ type ChiaWalletSpec struct {
 	// +optional
 	Storage *StorageConfig `json:"storage,omitempty"`
 
-	// ServiceType is the type of the service for the harvester instance
+	// ServiceType is the type of the Service for the peer port. The daemon and RPC ports have their own ClusterIP Services, configured in services
 	// +optional
 	// +kubebuilder:default="ClusterIP"
 	ServiceType string `json:"serviceType"`
 
+	// Services configures the separate Services for the wallet's peer, daemon and RPC ports
+	// +optional
+	Services *ChiaServicesConfig `json:"services,omitempty"`
+
+	// NetworkPolicy configures a NetworkPolicy that only allows the traffic the wallet needs
+	// +optional
+	NetworkPolicy *NetworkPolicyConfig `json:"networkPolicy,omitempty"`
+
+	// Expose configures Gateway API routes that expose the wallet's peer and RPC ports outside the cluster
+	// +optional
+	Expose *ExposeConfig `json:"expose,omitempty"`
+
 	// ImagePullPolicy is the pull policy for containers in the pod
 	// +optional
 	// +kubebuilder:default="Always"
@@ -41,6 +53,22 @@ type ChiaWalletSpec struct {
 	// PodSecurityContext defines the security context for the pod
 	// +optional
 	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
+
+	// ReportBalances defines whether the balance of each wallet should be reported in the ChiaWallet's status.
+	// Off by default, since anyone who can read the ChiaWallet can then see the balances.
+	// +optional
+	ReportBalances bool `json:"reportBalances,omitempty"`
+}
+
+// ChiaWalletTrustedNodeSpec defines the full_nodes a ChiaWallet trusts
+type ChiaWalletTrustedNodeSpec struct {
+	// ChiaNodeName is the name of a ChiaNode in the wallet's namespace. The peer IDs its replicas report in its status are trusted
+	// +optional
+	ChiaNodeName string `json:"chiaNodeName,omitempty"`
+
+	// PeerIDs are the peer IDs of other full_nodes to trust. A peer ID is the hex-encoded SHA-256 hash of the full_node's public certificate
+	// +optional
+	PeerIDs []string `json:"peerIDs,omitempty"`
 }
 
 // ChiaWalletConfigSpec defines the desired state of Chia component configuration
@@ -51,10 +79,23 @@ type ChiaWalletConfigSpec struct {
 	// SecretKeySpec defines the k8s Secret name and key for a Chia mnemonic
 	SecretKeySpec ChiaKeysSpec `json:"secretKey"`
 
+	// SecretKeys defines additional k8s Secret names and keys for Chia mnemonics, so more than one key is added to the keychain
+	// +optional
+	SecretKeys []ChiaKeysSpec `json:"secretKeys,omitempty"`
+
+	// Fingerprint is the fingerprint of the key the wallet logs into when it starts. Defaults to whichever key chia picks, usually the first one
+	// +optional
+	Fingerprint *int64 `json:"fingerprint,omitempty"`
+
 	// FullNodePeer defines the farmer's full_node peer in host:port format.
 	// In Kubernetes this is likely to be <node service name>.<namespace>.svc.cluster.local:8555
 	FullNodePeer string `json:"fullNodePeer"`
 
+	// TrustedNode defines full_nodes the wallet trusts, so it syncs from them in trusted mode, which is much faster than syncing from untrusted peers.
+	// FullNodePeer should point at one of them
+	// +optional
+	TrustedNode *ChiaWalletTrustedNodeSpec `json:"trustedNode,omitempty"`
+
 	// Testnet is set to true if the Chia container should switch to the latest default testnet's settings
 	// +optional
 	Testnet *bool `json:"testnet,omitempty"`
@@ -91,6 +132,10 @@ type ChiaWalletConfigSpec struct {
 	// SecurityContext defines the security context for the chia container
 	// +optional
 	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
+
+	// ConfigOverrides defines YAML documents that are deep-merged into the chia config.yaml on each container start
+	// +optional
+	ConfigOverrides *ChiaConfigOverridesSpec `json:"configOverrides,omitempty"`
 }
 
 // ChiaWalletStatus defines the observed state of ChiaWallet
@@ -98,10 +143,84 @@ type ChiaWalletStatus struct {
 	// Ready says whether the node is ready, this should be true when the node statefulset is in the target namespace
 	// +kubebuilder:default=false
 	Ready bool `json:"ready,omitempty"`
+
+	// Fingerprint is the fingerprint of the key the wallet is logged in with
+	// +optional
+	Fingerprint *int64 `json:"fingerprint,omitempty"`
+
+	// Synced is true when the wallet is synced to the blockchain
+	// +optional
+	Synced bool `json:"synced,omitempty"`
+
+	// Syncing is true while the wallet is catching up to the blockchain
+	// +optional
+	Syncing bool `json:"syncing,omitempty"`
+
+	// SyncedHeight is the blockchain height the wallet has synced to
+	// +optional
+	SyncedHeight int64 `json:"syncedHeight,omitempty"`
+
+	// FullNodePeer is the address of the full_node the wallet is connected to
+	// +optional
+	FullNodePeer string `json:"fullNodePeer,omitempty"`
+
+	// TrustedPeers are the peer IDs written to the wallet's trusted_peers
+	// +optional
+	TrustedPeers []string `json:"trustedPeers,omitempty"`
+
+	// Balances lists the balance of each wallet belonging to the logged in key. Only reported if spec.reportBalances is true
+	// +optional
+	Balances []ChiaWalletBalanceStatus `json:"balances,omitempty"`
+
+	// LastStatusUpdateTime is the last time the wallet status was gathered
+	// +optional
+	LastStatusUpdateTime *metav1.Time `json:"lastStatusUpdateTime,omitempty"`
+
+	// Conditions represent the latest observations of the ChiaWallet's state
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+const (
+	// ChiaWalletConditionSynced is true when the wallet is synced to the blockchain
+	ChiaWalletConditionSynced = "Synced"
+
+	// ChiaWalletConditionSelectedKeyLoggedIn is true when the wallet is logged in with the key selected by the ChiaWallet's fingerprint.
+	// It is only set when a fingerprint is selected
+	ChiaWalletConditionSelectedKeyLoggedIn = "SelectedKeyLoggedIn"
+)
+
+// ChiaWalletBalanceStatus defines the observed balance of a single wallet
+type ChiaWalletBalanceStatus struct {
+	// WalletID is the ID of the wallet
+	WalletID int64 `json:"walletID"`
+
+	// Name is the name of the wallet
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Type is chia's numeric wallet type, eg. 0 for the standard XCH wallet
+	// +optional
+	Type int32 `json:"type,omitempty"`
+
+	// Confirmed is the confirmed balance of the wallet, in mojos
+	Confirmed int64 `json:"confirmed"`
+
+	// Spendable is the spendable balance of the wallet, in mojos
+	Spendable int64 `json:"spendable"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=boolean,JSONPath=`.status.ready`
+//+kubebuilder:printcolumn:name="Synced",type=string,JSONPath=`.status.conditions[?(@.type=="Synced")].status`
+//+kubebuilder:printcolumn:name="Height",type=integer,JSONPath=`.status.syncedHeight`
+//+kubebuilder:printcolumn:name="Fingerprint",type=integer,JSONPath=`.status.fingerprint`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // ChiaWallet is the Schema for the chiawallets API
 type ChiaWallet struct {
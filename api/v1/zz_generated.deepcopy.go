@@ -23,6 +23,8 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -55,6 +57,39 @@ func (in *AdditionalMetadata) DeepCopy() *AdditionalMetadata {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CSIVolumeConfig) DeepCopyInto(out *CSIVolumeConfig) {
+	*out = *in
+	if in.FSType != nil {
+		in, out := &in.FSType, &out.FSType
+		*out = new(string)
+		**out = **in
+	}
+	if in.VolumeAttributes != nil {
+		in, out := &in.VolumeAttributes, &out.VolumeAttributes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NodePublishSecretRef != nil {
+		in, out := &in.NodePublishSecretRef, &out.NodePublishSecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	in.PlotMountConfig.DeepCopyInto(&out.PlotMountConfig)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CSIVolumeConfig.
+func (in *CSIVolumeConfig) DeepCopy() *CSIVolumeConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CSIVolumeConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChiaCA) DeepCopyInto(out *ChiaCA) {
 	*out = *in
@@ -144,9 +179,39 @@ func (in *ChiaCAStatus) DeepCopy() *ChiaCAStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiaConfigOverridesSpec) DeepCopyInto(out *ChiaConfigOverridesSpec) {
+	*out = *in
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaConfigOverridesSpec.
+func (in *ChiaConfigOverridesSpec) DeepCopy() *ChiaConfigOverridesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiaConfigOverridesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChiaExporterConfigSpec) DeepCopyInto(out *ChiaExporterConfigSpec) {
 	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ImagePullPolicy != nil {
+		in, out := &in.ImagePullPolicy, &out.ImagePullPolicy
+		*out = new(corev1.PullPolicy)
+		**out = **in
+	}
 	if in.ServiceLabels != nil {
 		in, out := &in.ServiceLabels, &out.ServiceLabels
 		*out = make(map[string]string, len(*in))
@@ -154,6 +219,53 @@ func (in *ChiaExporterConfigSpec) DeepCopyInto(out *ChiaExporterConfigSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LivenessProbe != nil {
+		in, out := &in.LivenessProbe, &out.LivenessProbe
+		*out = new(corev1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReadinessProbe != nil {
+		in, out := &in.ReadinessProbe, &out.ReadinessProbe
+		*out = new(corev1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StartupProbe != nil {
+		in, out := &in.StartupProbe, &out.StartupProbe
+		*out = new(corev1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(corev1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceMonitor != nil {
+		in, out := &in.ServiceMonitor, &out.ServiceMonitor
+		*out = new(ChiaExporterServiceMonitorSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PrometheusRule != nil {
+		in, out := &in.PrometheusRule, &out.PrometheusRule
+		*out = new(ChiaExporterPrometheusRuleSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaExporterConfigSpec.
@@ -166,13 +278,64 @@ func (in *ChiaExporterConfigSpec) DeepCopy() *ChiaExporterConfigSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiaExporterPrometheusRuleSpec) DeepCopyInto(out *ChiaExporterPrometheusRuleSpec) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AlertLabels != nil {
+		in, out := &in.AlertLabels, &out.AlertLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaExporterPrometheusRuleSpec.
+func (in *ChiaExporterPrometheusRuleSpec) DeepCopy() *ChiaExporterPrometheusRuleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiaExporterPrometheusRuleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiaExporterServiceMonitorSpec) DeepCopyInto(out *ChiaExporterServiceMonitorSpec) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaExporterServiceMonitorSpec.
+func (in *ChiaExporterServiceMonitorSpec) DeepCopy() *ChiaExporterServiceMonitorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiaExporterServiceMonitorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChiaFarmer) DeepCopyInto(out *ChiaFarmer) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaFarmer.
@@ -196,7 +359,29 @@ func (in *ChiaFarmer) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChiaFarmerConfigSpec) DeepCopyInto(out *ChiaFarmerConfigSpec) {
 	*out = *in
-	out.SecretKeySpec = in.SecretKeySpec
+	in.SecretKeySpec.DeepCopyInto(&out.SecretKeySpec)
+	if in.SecretKeys != nil {
+		in, out := &in.SecretKeys, &out.SecretKeys
+		*out = make([]ChiaKeysSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Pools != nil {
+		in, out := &in.Pools, &out.Pools
+		*out = make([]ChiaFarmerPoolSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.FarmerRewardAddress != nil {
+		in, out := &in.FarmerRewardAddress, &out.FarmerRewardAddress
+		*out = new(string)
+		**out = **in
+	}
+	if in.PoolRewardAddress != nil {
+		in, out := &in.PoolRewardAddress, &out.PoolRewardAddress
+		*out = new(string)
+		**out = **in
+	}
 	if in.Testnet != nil {
 		in, out := &in.Testnet, &out.Testnet
 		*out = new(bool)
@@ -237,6 +422,11 @@ func (in *ChiaFarmerConfigSpec) DeepCopyInto(out *ChiaFarmerConfigSpec) {
 		*out = new(corev1.SecurityContext)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ConfigOverrides != nil {
+		in, out := &in.ConfigOverrides, &out.ConfigOverrides
+		*out = new(ChiaConfigOverridesSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaFarmerConfigSpec.
@@ -249,6 +439,26 @@ func (in *ChiaFarmerConfigSpec) DeepCopy() *ChiaFarmerConfigSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiaFarmerHarvesterStatus) DeepCopyInto(out *ChiaFarmerHarvesterStatus) {
+	*out = *in
+	if in.EffectiveSize != nil {
+		in, out := &in.EffectiveSize, &out.EffectiveSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaFarmerHarvesterStatus.
+func (in *ChiaFarmerHarvesterStatus) DeepCopy() *ChiaFarmerHarvesterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiaFarmerHarvesterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChiaFarmerList) DeepCopyInto(out *ChiaFarmerList) {
 	*out = *in
@@ -281,6 +491,72 @@ func (in *ChiaFarmerList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiaFarmerPoolSpec) DeepCopyInto(out *ChiaFarmerPoolSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaFarmerPoolSpec.
+func (in *ChiaFarmerPoolSpec) DeepCopy() *ChiaFarmerPoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiaFarmerPoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiaFarmerPoolStatus) DeepCopyInto(out *ChiaFarmerPoolStatus) {
+	*out = *in
+	if in.CurrentDifficulty != nil {
+		in, out := &in.CurrentDifficulty, &out.CurrentDifficulty
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaFarmerPoolStatus.
+func (in *ChiaFarmerPoolStatus) DeepCopy() *ChiaFarmerPoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiaFarmerPoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiaFarmerRewardTargetsStatus) DeepCopyInto(out *ChiaFarmerRewardTargetsStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaFarmerRewardTargetsStatus.
+func (in *ChiaFarmerRewardTargetsStatus) DeepCopy() *ChiaFarmerRewardTargetsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiaFarmerRewardTargetsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiaFarmerSignagePointStatus) DeepCopyInto(out *ChiaFarmerSignagePointStatus) {
+	*out = *in
+	in.ObservedTime.DeepCopyInto(&out.ObservedTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaFarmerSignagePointStatus.
+func (in *ChiaFarmerSignagePointStatus) DeepCopy() *ChiaFarmerSignagePointStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiaFarmerSignagePointStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChiaFarmerSpec) DeepCopyInto(out *ChiaFarmerSpec) {
 	*out = *in
@@ -292,6 +568,21 @@ func (in *ChiaFarmerSpec) DeepCopyInto(out *ChiaFarmerSpec) {
 		*out = new(StorageConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = new(ChiaServicesConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkPolicy != nil {
+		in, out := &in.NetworkPolicy, &out.NetworkPolicy
+		*out = new(NetworkPolicyConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Expose != nil {
+		in, out := &in.Expose, &out.Expose
+		*out = new(ExposeConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ImagePullPolicy != nil {
 		in, out := &in.ImagePullPolicy, &out.ImagePullPolicy
 		*out = new(corev1.PullPolicy)
@@ -324,6 +615,41 @@ func (in *ChiaFarmerSpec) DeepCopy() *ChiaFarmerSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChiaFarmerStatus) DeepCopyInto(out *ChiaFarmerStatus) {
 	*out = *in
+	if in.Harvesters != nil {
+		in, out := &in.Harvesters, &out.Harvesters
+		*out = make([]ChiaFarmerHarvesterStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Pools != nil {
+		in, out := &in.Pools, &out.Pools
+		*out = make([]ChiaFarmerPoolStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastSignagePoint != nil {
+		in, out := &in.LastSignagePoint, &out.LastSignagePoint
+		*out = new(ChiaFarmerSignagePointStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RewardTargets != nil {
+		in, out := &in.RewardTargets, &out.RewardTargets
+		*out = new(ChiaFarmerRewardTargetsStatus)
+		**out = **in
+	}
+	if in.LastStatusUpdateTime != nil {
+		in, out := &in.LastStatusUpdateTime, &out.LastStatusUpdateTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaFarmerStatus.
@@ -342,7 +668,7 @@ func (in *ChiaHarvester) DeepCopyInto(out *ChiaHarvester) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaHarvester.
@@ -364,30 +690,148 @@ func (in *ChiaHarvester) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChiaHarvesterConfigSpec) DeepCopyInto(out *ChiaHarvesterConfigSpec) {
+func (in *ChiaHarvesterBundle) DeepCopyInto(out *ChiaHarvesterBundle) {
 	*out = *in
-	if in.Testnet != nil {
-		in, out := &in.Testnet, &out.Testnet
-		*out = new(bool)
-		**out = **in
-	}
-	if in.LogLevel != nil {
-		in, out := &in.LogLevel, &out.LogLevel
-		*out = new(string)
-		**out = **in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaHarvesterBundle.
+func (in *ChiaHarvesterBundle) DeepCopy() *ChiaHarvesterBundle {
+	if in == nil {
+		return nil
 	}
-	if in.Timezone != nil {
-		in, out := &in.Timezone, &out.Timezone
-		*out = new(string)
-		**out = **in
+	out := new(ChiaHarvesterBundle)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ChiaHarvesterBundle) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
-	if in.LivenessProbe != nil {
-		in, out := &in.LivenessProbe, &out.LivenessProbe
-		*out = new(corev1.Probe)
-		(*in).DeepCopyInto(*out)
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiaHarvesterBundleFarmerPeerSpec) DeepCopyInto(out *ChiaHarvesterBundleFarmerPeerSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaHarvesterBundleFarmerPeerSpec.
+func (in *ChiaHarvesterBundleFarmerPeerSpec) DeepCopy() *ChiaHarvesterBundleFarmerPeerSpec {
+	if in == nil {
+		return nil
 	}
-	if in.ReadinessProbe != nil {
-		in, out := &in.ReadinessProbe, &out.ReadinessProbe
+	out := new(ChiaHarvesterBundleFarmerPeerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiaHarvesterBundleList) DeepCopyInto(out *ChiaHarvesterBundleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ChiaHarvesterBundle, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaHarvesterBundleList.
+func (in *ChiaHarvesterBundleList) DeepCopy() *ChiaHarvesterBundleList {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiaHarvesterBundleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ChiaHarvesterBundleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiaHarvesterBundleSpec) DeepCopyInto(out *ChiaHarvesterBundleSpec) {
+	*out = *in
+	in.AdditionalMetadata.DeepCopyInto(&out.AdditionalMetadata)
+	out.FarmerPeer = in.FarmerPeer
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaHarvesterBundleSpec.
+func (in *ChiaHarvesterBundleSpec) DeepCopy() *ChiaHarvesterBundleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiaHarvesterBundleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiaHarvesterBundleStatus) DeepCopyInto(out *ChiaHarvesterBundleStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaHarvesterBundleStatus.
+func (in *ChiaHarvesterBundleStatus) DeepCopy() *ChiaHarvesterBundleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiaHarvesterBundleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiaHarvesterConfigSpec) DeepCopyInto(out *ChiaHarvesterConfigSpec) {
+	*out = *in
+	if in.FarmerRPCAddress != nil {
+		in, out := &in.FarmerRPCAddress, &out.FarmerRPCAddress
+		*out = new(string)
+		**out = **in
+	}
+	if in.Testnet != nil {
+		in, out := &in.Testnet, &out.Testnet
+		*out = new(bool)
+		**out = **in
+	}
+	if in.LogLevel != nil {
+		in, out := &in.LogLevel, &out.LogLevel
+		*out = new(string)
+		**out = **in
+	}
+	if in.Timezone != nil {
+		in, out := &in.Timezone, &out.Timezone
+		*out = new(string)
+		**out = **in
+	}
+	if in.LivenessProbe != nil {
+		in, out := &in.LivenessProbe, &out.LivenessProbe
+		*out = new(corev1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReadinessProbe != nil {
+		in, out := &in.ReadinessProbe, &out.ReadinessProbe
 		*out = new(corev1.Probe)
 		(*in).DeepCopyInto(*out)
 	}
@@ -406,6 +850,11 @@ func (in *ChiaHarvesterConfigSpec) DeepCopyInto(out *ChiaHarvesterConfigSpec) {
 		*out = new(corev1.SecurityContext)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ConfigOverrides != nil {
+		in, out := &in.ConfigOverrides, &out.ConfigOverrides
+		*out = new(ChiaConfigOverridesSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaHarvesterConfigSpec.
@@ -450,6 +899,56 @@ func (in *ChiaHarvesterList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiaHarvesterPlotDirectoryStatus) DeepCopyInto(out *ChiaHarvesterPlotDirectoryStatus) {
+	*out = *in
+	out.RawSize = in.RawSize.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaHarvesterPlotDirectoryStatus.
+func (in *ChiaHarvesterPlotDirectoryStatus) DeepCopy() *ChiaHarvesterPlotDirectoryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiaHarvesterPlotDirectoryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiaHarvesterPlotsStatus) DeepCopyInto(out *ChiaHarvesterPlotsStatus) {
+	*out = *in
+	out.RawSize = in.RawSize.DeepCopy()
+	out.EffectiveSize = in.EffectiveSize.DeepCopy()
+	if in.Duplicates != nil {
+		in, out := &in.Duplicates, &out.Duplicates
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NoKey != nil {
+		in, out := &in.NoKey, &out.NoKey
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Directories != nil {
+		in, out := &in.Directories, &out.Directories
+		*out = make([]ChiaHarvesterPlotDirectoryStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaHarvesterPlotsStatus.
+func (in *ChiaHarvesterPlotsStatus) DeepCopy() *ChiaHarvesterPlotsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiaHarvesterPlotsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChiaHarvesterSpec) DeepCopyInto(out *ChiaHarvesterSpec) {
 	*out = *in
@@ -461,6 +960,21 @@ func (in *ChiaHarvesterSpec) DeepCopyInto(out *ChiaHarvesterSpec) {
 		*out = new(StorageConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = new(ChiaServicesConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkPolicy != nil {
+		in, out := &in.NetworkPolicy, &out.NetworkPolicy
+		*out = new(NetworkPolicyConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Expose != nil {
+		in, out := &in.Expose, &out.Expose
+		*out = new(ExposeConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ImagePullPolicy != nil {
 		in, out := &in.ImagePullPolicy, &out.ImagePullPolicy
 		*out = new(corev1.PullPolicy)
@@ -478,6 +992,11 @@ func (in *ChiaHarvesterSpec) DeepCopyInto(out *ChiaHarvesterSpec) {
 		*out = new(corev1.PodSecurityContext)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PlotDiscovery != nil {
+		in, out := &in.PlotDiscovery, &out.PlotDiscovery
+		*out = new(PlotDiscoveryConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaHarvesterSpec.
@@ -493,6 +1012,22 @@ func (in *ChiaHarvesterSpec) DeepCopy() *ChiaHarvesterSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChiaHarvesterStatus) DeepCopyInto(out *ChiaHarvesterStatus) {
 	*out = *in
+	if in.Plots != nil {
+		in, out := &in.Plots, &out.Plots
+		*out = new(ChiaHarvesterPlotsStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastStatusUpdateTime != nil {
+		in, out := &in.LastStatusUpdateTime, &out.LastStatusUpdateTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaHarvesterStatus.
@@ -508,6 +1043,11 @@ func (in *ChiaHarvesterStatus) DeepCopy() *ChiaHarvesterStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChiaKeysSpec) DeepCopyInto(out *ChiaKeysSpec) {
 	*out = *in
+	if in.Fingerprint != nil {
+		in, out := &in.Fingerprint, &out.Fingerprint
+		*out = new(int64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaKeysSpec.
@@ -526,7 +1066,7 @@ func (in *ChiaNode) DeepCopyInto(out *ChiaNode) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaNode.
@@ -590,6 +1130,11 @@ func (in *ChiaNodeConfigSpec) DeepCopyInto(out *ChiaNodeConfigSpec) {
 		*out = new(corev1.SecurityContext)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ConfigOverrides != nil {
+		in, out := &in.ConfigOverrides, &out.ConfigOverrides
+		*out = new(ChiaConfigOverridesSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaNodeConfigSpec.
@@ -602,6 +1147,51 @@ func (in *ChiaNodeConfigSpec) DeepCopy() *ChiaNodeConfigSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiaNodeDatabaseBootstrapSpec) DeepCopyInto(out *ChiaNodeDatabaseBootstrapSpec) {
+	*out = *in
+	if in.VolumeSnapshot != nil {
+		in, out := &in.VolumeSnapshot, &out.VolumeSnapshot
+		*out = new(DatabaseBootstrapVolumeSnapshotSource)
+		**out = **in
+	}
+	if in.PersistentVolumeClaim != nil {
+		in, out := &in.PersistentVolumeClaim, &out.PersistentVolumeClaim
+		*out = new(DatabaseBootstrapPersistentVolumeClaimSource)
+		**out = **in
+	}
+	if in.URL != nil {
+		in, out := &in.URL, &out.URL
+		*out = new(DatabaseBootstrapURLSource)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaNodeDatabaseBootstrapSpec.
+func (in *ChiaNodeDatabaseBootstrapSpec) DeepCopy() *ChiaNodeDatabaseBootstrapSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiaNodeDatabaseBootstrapSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiaNodeDatabaseBootstrapStatus) DeepCopyInto(out *ChiaNodeDatabaseBootstrapStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaNodeDatabaseBootstrapStatus.
+func (in *ChiaNodeDatabaseBootstrapStatus) DeepCopy() *ChiaNodeDatabaseBootstrapStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiaNodeDatabaseBootstrapStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChiaNodeList) DeepCopyInto(out *ChiaNodeList) {
 	*out = *in
@@ -634,6 +1224,21 @@ func (in *ChiaNodeList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiaNodeReplicaStatus) DeepCopyInto(out *ChiaNodeReplicaStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaNodeReplicaStatus.
+func (in *ChiaNodeReplicaStatus) DeepCopy() *ChiaNodeReplicaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiaNodeReplicaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChiaNodeSpec) DeepCopyInto(out *ChiaNodeSpec) {
 	*out = *in
@@ -645,6 +1250,26 @@ func (in *ChiaNodeSpec) DeepCopyInto(out *ChiaNodeSpec) {
 		*out = new(StorageConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.DatabaseBootstrap != nil {
+		in, out := &in.DatabaseBootstrap, &out.DatabaseBootstrap
+		*out = new(ChiaNodeDatabaseBootstrapSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = new(ChiaServicesConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkPolicy != nil {
+		in, out := &in.NetworkPolicy, &out.NetworkPolicy
+		*out = new(NetworkPolicyConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Expose != nil {
+		in, out := &in.Expose, &out.Expose
+		*out = new(ExposeConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ImagePullPolicy != nil {
 		in, out := &in.ImagePullPolicy, &out.ImagePullPolicy
 		*out = new(corev1.PullPolicy)
@@ -682,6 +1307,34 @@ func (in *ChiaNodeSpec) DeepCopy() *ChiaNodeSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChiaNodeStatus) DeepCopyInto(out *ChiaNodeStatus) {
 	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = make([]ChiaNodeReplicaStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastStatusUpdateTime != nil {
+		in, out := &in.LastStatusUpdateTime, &out.LastStatusUpdateTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]ChiaNodeVolumeStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DatabaseBootstrap != nil {
+		in, out := &in.DatabaseBootstrap, &out.DatabaseBootstrap
+		*out = make([]ChiaNodeDatabaseBootstrapStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaNodeStatus.
@@ -695,63 +1348,239 @@ func (in *ChiaNodeStatus) DeepCopy() *ChiaNodeStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChiaRootConfig) DeepCopyInto(out *ChiaRootConfig) {
+func (in *ChiaNodeVolumeStatus) DeepCopyInto(out *ChiaNodeVolumeStatus) {
 	*out = *in
-	if in.PersistentVolumeClaim != nil {
-		in, out := &in.PersistentVolumeClaim, &out.PersistentVolumeClaim
-		*out = new(PersistentVolumeClaimConfig)
-		**out = **in
+	if in.Requested != nil {
+		in, out := &in.Requested, &out.Requested
+		x := (*in).DeepCopy()
+		*out = &x
 	}
-	if in.HostPathVolume != nil {
-		in, out := &in.HostPathVolume, &out.HostPathVolume
-		*out = new(HostPathVolumeConfig)
-		**out = **in
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		x := (*in).DeepCopy()
+		*out = &x
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaRootConfig.
-func (in *ChiaRootConfig) DeepCopy() *ChiaRootConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaNodeVolumeStatus.
+func (in *ChiaNodeVolumeStatus) DeepCopy() *ChiaNodeVolumeStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ChiaRootConfig)
+	out := new(ChiaNodeVolumeStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChiaWallet) DeepCopyInto(out *ChiaWallet) {
+func (in *ChiaRootConfig) DeepCopyInto(out *ChiaRootConfig) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	if in.PersistentVolumeClaim != nil {
+		in, out := &in.PersistentVolumeClaim, &out.PersistentVolumeClaim
+		*out = new(PersistentVolumeClaimConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostPathVolume != nil {
+		in, out := &in.HostPathVolume, &out.HostPathVolume
+		*out = new(HostPathVolumeConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NFSVolume != nil {
+		in, out := &in.NFSVolume, &out.NFSVolume
+		*out = new(NFSVolumeConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CSIVolume != nil {
+		in, out := &in.CSIVolume, &out.CSIVolume
+		*out = new(CSIVolumeConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EphemeralVolume != nil {
+		in, out := &in.EphemeralVolume, &out.EphemeralVolume
+		*out = new(EphemeralVolumeConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ISCSIVolume != nil {
+		in, out := &in.ISCSIVolume, &out.ISCSIVolume
+		*out = new(ISCSIVolumeConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaWallet.
-func (in *ChiaWallet) DeepCopy() *ChiaWallet {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaRootConfig.
+func (in *ChiaRootConfig) DeepCopy() *ChiaRootConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(ChiaWallet)
+	out := new(ChiaRootConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ChiaWallet) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChiaWalletConfigSpec) DeepCopyInto(out *ChiaWalletConfigSpec) {
+func (in *ChiaServiceConfig) DeepCopyInto(out *ChiaServiceConfig) {
 	*out = *in
-	out.SecretKeySpec = in.SecretKeySpec
-	if in.Testnet != nil {
-		in, out := &in.Testnet, &out.Testnet
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ServiceType != nil {
+		in, out := &in.ServiceType, &out.ServiceType
+		*out = new(corev1.ServiceType)
+		**out = **in
+	}
+	if in.ExternalTrafficPolicy != nil {
+		in, out := &in.ExternalTrafficPolicy, &out.ExternalTrafficPolicy
+		*out = new(corev1.ServiceExternalTrafficPolicy)
+		**out = **in
+	}
+	if in.NodePort != nil {
+		in, out := &in.NodePort, &out.NodePort
+		*out = new(int32)
+		**out = **in
+	}
+	if in.LoadBalancerClass != nil {
+		in, out := &in.LoadBalancerClass, &out.LoadBalancerClass
+		*out = new(string)
+		**out = **in
+	}
+	if in.LoadBalancerSourceRanges != nil {
+		in, out := &in.LoadBalancerSourceRanges, &out.LoadBalancerSourceRanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaServiceConfig.
+func (in *ChiaServiceConfig) DeepCopy() *ChiaServiceConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiaServiceConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiaServicesConfig) DeepCopyInto(out *ChiaServicesConfig) {
+	*out = *in
+	if in.Peers != nil {
+		in, out := &in.Peers, &out.Peers
+		*out = new(ChiaServiceConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Daemon != nil {
+		in, out := &in.Daemon, &out.Daemon
+		*out = new(ChiaServiceConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RPC != nil {
+		in, out := &in.RPC, &out.RPC
+		*out = new(ChiaServiceConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IPFamilies != nil {
+		in, out := &in.IPFamilies, &out.IPFamilies
+		*out = make([]corev1.IPFamily, len(*in))
+		copy(*out, *in)
+	}
+	if in.IPFamilyPolicy != nil {
+		in, out := &in.IPFamilyPolicy, &out.IPFamilyPolicy
+		*out = new(corev1.IPFamilyPolicy)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaServicesConfig.
+func (in *ChiaServicesConfig) DeepCopy() *ChiaServicesConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiaServicesConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiaWallet) DeepCopyInto(out *ChiaWallet) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaWallet.
+func (in *ChiaWallet) DeepCopy() *ChiaWallet {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiaWallet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ChiaWallet) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiaWalletBalanceStatus) DeepCopyInto(out *ChiaWalletBalanceStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaWalletBalanceStatus.
+func (in *ChiaWalletBalanceStatus) DeepCopy() *ChiaWalletBalanceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiaWalletBalanceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiaWalletConfigSpec) DeepCopyInto(out *ChiaWalletConfigSpec) {
+	*out = *in
+	in.SecretKeySpec.DeepCopyInto(&out.SecretKeySpec)
+	if in.SecretKeys != nil {
+		in, out := &in.SecretKeys, &out.SecretKeys
+		*out = make([]ChiaKeysSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Fingerprint != nil {
+		in, out := &in.Fingerprint, &out.Fingerprint
+		*out = new(int64)
+		**out = **in
+	}
+	if in.TrustedNode != nil {
+		in, out := &in.TrustedNode, &out.TrustedNode
+		*out = new(ChiaWalletTrustedNodeSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Testnet != nil {
+		in, out := &in.Testnet, &out.Testnet
 		*out = new(bool)
 		**out = **in
 	}
@@ -790,6 +1619,11 @@ func (in *ChiaWalletConfigSpec) DeepCopyInto(out *ChiaWalletConfigSpec) {
 		*out = new(corev1.SecurityContext)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ConfigOverrides != nil {
+		in, out := &in.ConfigOverrides, &out.ConfigOverrides
+		*out = new(ChiaConfigOverridesSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaWalletConfigSpec.
@@ -845,6 +1679,21 @@ func (in *ChiaWalletSpec) DeepCopyInto(out *ChiaWalletSpec) {
 		*out = new(StorageConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = new(ChiaServicesConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkPolicy != nil {
+		in, out := &in.NetworkPolicy, &out.NetworkPolicy
+		*out = new(NetworkPolicyConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Expose != nil {
+		in, out := &in.Expose, &out.Expose
+		*out = new(ExposeConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ImagePullPolicy != nil {
 		in, out := &in.ImagePullPolicy, &out.ImagePullPolicy
 		*out = new(corev1.PullPolicy)
@@ -877,6 +1726,32 @@ func (in *ChiaWalletSpec) DeepCopy() *ChiaWalletSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChiaWalletStatus) DeepCopyInto(out *ChiaWalletStatus) {
 	*out = *in
+	if in.Fingerprint != nil {
+		in, out := &in.Fingerprint, &out.Fingerprint
+		*out = new(int64)
+		**out = **in
+	}
+	if in.TrustedPeers != nil {
+		in, out := &in.TrustedPeers, &out.TrustedPeers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Balances != nil {
+		in, out := &in.Balances, &out.Balances
+		*out = make([]ChiaWalletBalanceStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastStatusUpdateTime != nil {
+		in, out := &in.LastStatusUpdateTime, &out.LastStatusUpdateTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaWalletStatus.
@@ -889,9 +1764,193 @@ func (in *ChiaWalletStatus) DeepCopy() *ChiaWalletStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiaWalletTrustedNodeSpec) DeepCopyInto(out *ChiaWalletTrustedNodeSpec) {
+	*out = *in
+	if in.PeerIDs != nil {
+		in, out := &in.PeerIDs, &out.PeerIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiaWalletTrustedNodeSpec.
+func (in *ChiaWalletTrustedNodeSpec) DeepCopy() *ChiaWalletTrustedNodeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiaWalletTrustedNodeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseBootstrapPersistentVolumeClaimSource) DeepCopyInto(out *DatabaseBootstrapPersistentVolumeClaimSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseBootstrapPersistentVolumeClaimSource.
+func (in *DatabaseBootstrapPersistentVolumeClaimSource) DeepCopy() *DatabaseBootstrapPersistentVolumeClaimSource {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseBootstrapPersistentVolumeClaimSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseBootstrapURLSource) DeepCopyInto(out *DatabaseBootstrapURLSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseBootstrapURLSource.
+func (in *DatabaseBootstrapURLSource) DeepCopy() *DatabaseBootstrapURLSource {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseBootstrapURLSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseBootstrapVolumeSnapshotSource) DeepCopyInto(out *DatabaseBootstrapVolumeSnapshotSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseBootstrapVolumeSnapshotSource.
+func (in *DatabaseBootstrapVolumeSnapshotSource) DeepCopy() *DatabaseBootstrapVolumeSnapshotSource {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseBootstrapVolumeSnapshotSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EphemeralVolumeConfig) DeepCopyInto(out *EphemeralVolumeConfig) {
+	*out = *in
+	out.ResourceRequest = in.ResourceRequest.DeepCopy()
+	if in.AccessModes != nil {
+		in, out := &in.AccessModes, &out.AccessModes
+		*out = make([]corev1.PersistentVolumeAccessMode, len(*in))
+		copy(*out, *in)
+	}
+	in.PlotMountConfig.DeepCopyInto(&out.PlotMountConfig)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EphemeralVolumeConfig.
+func (in *EphemeralVolumeConfig) DeepCopy() *EphemeralVolumeConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(EphemeralVolumeConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExposeConfig) DeepCopyInto(out *ExposeConfig) {
+	*out = *in
+	if in.Peers != nil {
+		in, out := &in.Peers, &out.Peers
+		*out = new(PortExposureConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RPC != nil {
+		in, out := &in.RPC, &out.RPC
+		*out = new(PortExposureConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExposeConfig.
+func (in *ExposeConfig) DeepCopy() *ExposeConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ExposeConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayParentReference) DeepCopyInto(out *GatewayParentReference) {
+	*out = *in
+	if in.Namespace != nil {
+		in, out := &in.Namespace, &out.Namespace
+		*out = new(string)
+		**out = **in
+	}
+	if in.SectionName != nil {
+		in, out := &in.SectionName, &out.SectionName
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayParentReference.
+func (in *GatewayParentReference) DeepCopy() *GatewayParentReference {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayParentReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayRouteConfig) DeepCopyInto(out *GatewayRouteConfig) {
+	*out = *in
+	if in.ParentRefs != nil {
+		in, out := &in.ParentRefs, &out.ParentRefs
+		*out = make([]GatewayParentReference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Hostnames != nil {
+		in, out := &in.Hostnames, &out.Hostnames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayRouteConfig.
+func (in *GatewayRouteConfig) DeepCopy() *GatewayRouteConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayRouteConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostPathVolumeConfig) DeepCopyInto(out *HostPathVolumeConfig) {
 	*out = *in
+	if in.Type != nil {
+		in, out := &in.Type, &out.Type
+		*out = new(corev1.HostPathType)
+		**out = **in
+	}
+	in.PlotMountConfig.DeepCopyInto(&out.PlotMountConfig)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostPathVolumeConfig.
@@ -904,9 +1963,110 @@ func (in *HostPathVolumeConfig) DeepCopy() *HostPathVolumeConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ISCSIVolumeConfig) DeepCopyInto(out *ISCSIVolumeConfig) {
+	*out = *in
+	if in.Portals != nil {
+		in, out := &in.Portals, &out.Portals
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	in.PlotMountConfig.DeepCopyInto(&out.PlotMountConfig)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ISCSIVolumeConfig.
+func (in *ISCSIVolumeConfig) DeepCopy() *ISCSIVolumeConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ISCSIVolumeConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalVolumeConfig) DeepCopyInto(out *LocalVolumeConfig) {
+	*out = *in
+	out.Capacity = in.Capacity.DeepCopy()
+	in.PlotMountConfig.DeepCopyInto(&out.PlotMountConfig)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalVolumeConfig.
+func (in *LocalVolumeConfig) DeepCopy() *LocalVolumeConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalVolumeConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NFSVolumeConfig) DeepCopyInto(out *NFSVolumeConfig) {
+	*out = *in
+	in.PlotMountConfig.DeepCopyInto(&out.PlotMountConfig)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NFSVolumeConfig.
+func (in *NFSVolumeConfig) DeepCopy() *NFSVolumeConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NFSVolumeConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicyConfig) DeepCopyInto(out *NetworkPolicyConfig) {
+	*out = *in
+	if in.PeerFrom != nil {
+		in, out := &in.PeerFrom, &out.PeerFrom
+		*out = make([]networkingv1.NetworkPolicyPeer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RPCFrom != nil {
+		in, out := &in.RPCFrom, &out.RPCFrom
+		*out = make([]networkingv1.NetworkPolicyPeer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MetricsFrom != nil {
+		in, out := &in.MetricsFrom, &out.MetricsFrom
+		*out = make([]networkingv1.NetworkPolicyPeer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicyConfig.
+func (in *NetworkPolicyConfig) DeepCopy() *NetworkPolicyConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicyConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PersistentVolumeClaimConfig) DeepCopyInto(out *PersistentVolumeClaimConfig) {
 	*out = *in
+	if in.AccessModes != nil {
+		in, out := &in.AccessModes, &out.AccessModes
+		*out = make([]corev1.PersistentVolumeAccessMode, len(*in))
+		copy(*out, *in)
+	}
+	in.PlotMountConfig.DeepCopyInto(&out.PlotMountConfig)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersistentVolumeClaimConfig.
@@ -919,6 +2079,46 @@ func (in *PersistentVolumeClaimConfig) DeepCopy() *PersistentVolumeClaimConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlotDiscoveryConfig) DeepCopyInto(out *PlotDiscoveryConfig) {
+	*out = *in
+	if in.PathGlobs != nil {
+		in, out := &in.PathGlobs, &out.PathGlobs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlotDiscoveryConfig.
+func (in *PlotDiscoveryConfig) DeepCopy() *PlotDiscoveryConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PlotDiscoveryConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlotMountConfig) DeepCopyInto(out *PlotMountConfig) {
+	*out = *in
+	if in.ReadOnly != nil {
+		in, out := &in.ReadOnly, &out.ReadOnly
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlotMountConfig.
+func (in *PlotMountConfig) DeepCopy() *PlotMountConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PlotMountConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PlotsConfig) DeepCopyInto(out *PlotsConfig) {
 	*out = *in
@@ -929,7 +2129,7 @@ func (in *PlotsConfig) DeepCopyInto(out *PlotsConfig) {
 			if (*in)[i] != nil {
 				in, out := &(*in)[i], &(*out)[i]
 				*out = new(PersistentVolumeClaimConfig)
-				**out = **in
+				(*in).DeepCopyInto(*out)
 			}
 		}
 	}
@@ -940,10 +2140,75 @@ func (in *PlotsConfig) DeepCopyInto(out *PlotsConfig) {
 			if (*in)[i] != nil {
 				in, out := &(*in)[i], &(*out)[i]
 				*out = new(HostPathVolumeConfig)
-				**out = **in
+				(*in).DeepCopyInto(*out)
 			}
 		}
 	}
+	if in.NFSVolume != nil {
+		in, out := &in.NFSVolume, &out.NFSVolume
+		*out = make([]*NFSVolumeConfig, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(NFSVolumeConfig)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.CSIVolume != nil {
+		in, out := &in.CSIVolume, &out.CSIVolume
+		*out = make([]*CSIVolumeConfig, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(CSIVolumeConfig)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.EphemeralVolume != nil {
+		in, out := &in.EphemeralVolume, &out.EphemeralVolume
+		*out = make([]*EphemeralVolumeConfig, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(EphemeralVolumeConfig)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.ISCSIVolume != nil {
+		in, out := &in.ISCSIVolume, &out.ISCSIVolume
+		*out = make([]*ISCSIVolumeConfig, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(ISCSIVolumeConfig)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.LocalVolume != nil {
+		in, out := &in.LocalVolume, &out.LocalVolume
+		*out = make([]*LocalVolumeConfig, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(LocalVolumeConfig)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.PlotDirectories != nil {
+		in, out := &in.PlotDirectories, &out.PlotDirectories
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RecursiveScan != nil {
+		in, out := &in.RecursiveScan, &out.RecursiveScan
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlotsConfig.
@@ -956,6 +2221,26 @@ func (in *PlotsConfig) DeepCopy() *PlotsConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PortExposureConfig) DeepCopyInto(out *PortExposureConfig) {
+	*out = *in
+	if in.Gateway != nil {
+		in, out := &in.Gateway, &out.Gateway
+		*out = new(GatewayRouteConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PortExposureConfig.
+func (in *PortExposureConfig) DeepCopy() *PortExposureConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PortExposureConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageConfig) DeepCopyInto(out *StorageConfig) {
 	*out = *in
@@ -78,8 +78,9 @@ func main() {
 	}
 
 	if err = (&controller.ChiaNodeReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("chianode-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ChiaNode")
 		os.Exit(1)
@@ -112,6 +113,13 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "ChiaWallet")
 		os.Exit(1)
 	}
+	if err = (&controller.ChiaHarvesterBundleReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ChiaHarvesterBundle")
+		os.Exit(1)
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -0,0 +1,110 @@
+/*
+Copyright 2023 Chia Network Inc.
+*/
+
+package chiarpc
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// MainnetAddressPrefix is the human readable part of mainnet addresses
+	MainnetAddressPrefix = "xch"
+
+	// TestnetAddressPrefix is the human readable part of testnet addresses
+	TestnetAddressPrefix = "txch"
+
+	// bech32Charset is the alphabet the data part of a bech32m string is encoded with
+	bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+	// bech32mConst is the value a valid bech32m checksum leaves the polymod at
+	bech32mConst = 0x2bc830a3
+
+	// puzzleHashLength is the length in bytes of the puzzle hash an address encodes
+	puzzleHashLength = 32
+)
+
+// DecodeAddress decodes a bech32m encoded chia address into its prefix and puzzle hash
+func DecodeAddress(address string) (string, []byte, error) {
+	if strings.ToLower(address) != address && strings.ToUpper(address) != address {
+		return "", nil, fmt.Errorf("address %q mixes upper and lower case", address)
+	}
+	address = strings.ToLower(address)
+
+	sep := strings.LastIndex(address, "1")
+	if sep < 1 || sep+7 > len(address) {
+		return "", nil, fmt.Errorf("address %q is not bech32m encoded", address)
+	}
+	prefix := address[:sep]
+
+	var data []byte
+	for _, c := range address[sep+1:] {
+		i := strings.IndexRune(bech32Charset, c)
+		if i < 0 {
+			return "", nil, fmt.Errorf("address %q contains invalid character %q", address, c)
+		}
+		data = append(data, byte(i))
+	}
+	if bech32Polymod(append(bech32ExpandPrefix(prefix), data...)) != bech32mConst {
+		return "", nil, fmt.Errorf("address %q has an invalid bech32m checksum", address)
+	}
+
+	puzzleHash, err := convertBits(data[:len(data)-6], 5, 8)
+	if err != nil {
+		return "", nil, fmt.Errorf("address %q: %v", address, err)
+	}
+	if len(puzzleHash) != puzzleHashLength {
+		return "", nil, fmt.Errorf("address %q encodes %d bytes, expected a %d byte puzzle hash", address, len(puzzleHash), puzzleHashLength)
+	}
+	return prefix, puzzleHash, nil
+}
+
+// bech32Polymod computes the BCH checksum of the given 5-bit values
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>i)&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+// bech32ExpandPrefix expands the human readable part of a bech32m string for checksumming
+func bech32ExpandPrefix(prefix string) []byte {
+	var expanded []byte
+	for _, c := range []byte(prefix) {
+		expanded = append(expanded, c>>5)
+	}
+	expanded = append(expanded, 0)
+	for _, c := range []byte(prefix) {
+		expanded = append(expanded, c&31)
+	}
+	return expanded
+}
+
+// convertBits regroups a slice of fromBits-wide values into toBits-wide values, rejecting non-zero padding
+func convertBits(data []byte, fromBits, toBits uint) ([]byte, error) {
+	var acc, bits uint
+	var out []byte
+	maxv := uint(1)<<toBits - 1
+	for _, v := range data {
+		acc = acc<<fromBits | uint(v)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			out = append(out, byte(acc>>bits&maxv))
+		}
+	}
+	if bits >= fromBits || (acc<<(toBits-bits))&maxv != 0 {
+		return nil, fmt.Errorf("invalid padding")
+	}
+	return out, nil
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2023 Chia Network Inc.
+*/
+
+package chiarpc_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/chia-network/chia-operator/internal/chiarpc"
+)
+
+func TestDecodeAddress(t *testing.T) {
+	var puzzleHash []byte
+	for i := 0; i < 32; i++ {
+		puzzleHash = append(puzzleHash, byte(i))
+	}
+
+	for address, want := range map[string]string{
+		"xch1qqqsyqcyq5rqwzqfpg9scrgwpugpzysnzs23v9ccrydpk8qarc0srg6dkm":  chiarpc.MainnetAddressPrefix,
+		"txch1qqqsyqcyq5rqwzqfpg9scrgwpugpzysnzs23v9ccrydpk8qarc0sw0amhg": chiarpc.TestnetAddressPrefix,
+	} {
+		prefix, decoded, err := chiarpc.DecodeAddress(address)
+		if err != nil {
+			t.Fatalf("unexpected error decoding %s: %v", address, err)
+		}
+		if prefix != want {
+			t.Errorf("expected prefix %s, got %s", want, prefix)
+		}
+		if !bytes.Equal(decoded, puzzleHash) {
+			t.Errorf("expected puzzle hash %x, got %x", puzzleHash, decoded)
+		}
+	}
+
+	for _, address := range []string{
+		"xch1qqqsyqcyq5rqwzqfpg9scrgwpugpzysnzs23v9ccrydpk8qarc0srg6dkn", // bad checksum
+		"xch1qqqsyqcyq5rqwzqfpg9scrgwpugpzysnzs23v9ccrydpk8qarc0sRG6DKM", // mixed case
+		"xch1qqqsyqcyq5rqwzqfpg9scrgwpugpzysnzs23v9ccrydpk8qarc0srg6dkb", // invalid character
+		"abcdef1l7aum6echk45nj3s0wdvt2fg8x9yrzpqzd3ryx",                  // valid bech32m, not a puzzle hash
+		"xch1qqqsyqcyq5rqwzqfpg9scrgwpugpzysnzs23v9ccrydpk8qarc0s",       // missing checksum
+	} {
+		if _, _, err := chiarpc.DecodeAddress(address); err == nil {
+			t.Errorf("expected an error decoding %s", address)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2023 Chia Network Inc.
+*/
+
+package chiarpc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+const (
+	// PrivateCACertKey is the CA Secret data key containing the private CA certificate
+	PrivateCACertKey = "private_ca.crt"
+
+	// PrivateCAKeyKey is the CA Secret data key containing the private CA private key
+	PrivateCAKeyKey = "private_ca.key"
+
+	// serverName is the DNS name chia puts in every certificate it signs
+	serverName = "chia.net"
+)
+
+// GenerateCertificate creates a PEM encoded certificate and RSA private key signed by the given CA, in the same form chia generates them for its own services
+func GenerateCertificate(caCertPEM, caKeyPEM []byte) ([]byte, []byte, error) {
+	caCert, caKey, err := parseCA(caCertPEM, caKeyPEM)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error generating private key: %v", err)
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error generating certificate serial number: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName:         "Chia",
+			Organization:       []string{"Chia"},
+			OrganizationalUnit: []string{"Organic Farming Division"},
+		},
+		DNSNames:    []string{serverName},
+		NotBefore:   time.Now().Add(-48 * time.Hour),
+		NotAfter:    time.Date(2100, 8, 2, 0, 0, 0, 0, time.UTC),
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error signing certificate: %v", err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return certPEM, keyPEM, nil
+}
+
+// NewTLSConfig creates a TLS config for talking to chia RPC servers whose certificates are signed by the given private CA.
+// A client certificate is generated and signed by the same CA, since chia RPC servers require one.
+func NewTLSConfig(caCertPEM, caKeyPEM []byte) (*tls.Config, error) {
+	certPEM, keyPEM, err := GenerateCertificate(caCertPEM, caKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("error loading client certificate: %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caCertPEM) {
+		return nil, fmt.Errorf("no certificates found in CA certificate PEM")
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      pool,
+		ServerName:   serverName,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// NewTLSConfigFromSecretData creates a TLS config from the data of a CA Secret, as made by a ChiaCA or by hand
+func NewTLSConfigFromSecretData(data map[string][]byte) (*tls.Config, error) {
+	caCert, ok := data[PrivateCACertKey]
+	if !ok {
+		return nil, fmt.Errorf("CA Secret is missing the %s key", PrivateCACertKey)
+	}
+	caKey, ok := data[PrivateCAKeyKey]
+	if !ok {
+		return nil, fmt.Errorf("CA Secret is missing the %s key", PrivateCAKeyKey)
+	}
+	return NewTLSConfig(caCert, caKey)
+}
+
+// parseCA parses a PEM encoded CA certificate and its PKCS1 or PKCS8 encoded private key
+func parseCA(caCertPEM, caKeyPEM []byte) (*x509.Certificate, interface{}, error) {
+	certBlock, _ := pem.Decode(caCertPEM)
+	if certBlock == nil {
+		return nil, nil, fmt.Errorf("no PEM data found in CA certificate")
+	}
+	caCert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing CA certificate: %v", err)
+	}
+
+	keyBlock, _ := pem.Decode(caKeyPEM)
+	if keyBlock == nil {
+		return nil, nil, fmt.Errorf("no PEM data found in CA private key")
+	}
+	caKey, err := x509.ParsePKCS1PrivateKey(keyBlock.Bytes)
+	if err == nil {
+		return caCert, caKey, nil
+	}
+	pkcs8Key, err := x509.ParsePKCS8PrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing CA private key: %v", err)
+	}
+	return caCert, pkcs8Key, nil
+}
@@ -0,0 +1,254 @@
+/*
+Copyright 2023 Chia Network Inc.
+*/
+
+// Package chiarpctest provides a fake chia RPC server for testing code that uses chiarpc without running chia
+package chiarpctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"github.com/chia-network/chia-operator/internal/chiarpc"
+)
+
+// Server is a fake chia RPC server. It serves HTTPS with a certificate signed by its own private CA,
+// requires client certificates signed by that CA, and answers each endpoint with a canned response.
+// Websocket connections are served like the chia daemon's, answering each command with the canned response of the endpoint of the same name.
+type Server struct {
+	*httptest.Server
+
+	caCertPEM []byte
+	caKeyPEM  []byte
+
+	mu          sync.Mutex
+	responses   map[string]interface{}
+	requests    map[string][]map[string]interface{}
+	connections int
+}
+
+// NewServer starts a new fake chia RPC server. Callers should call Close when done with it.
+func NewServer() (*Server, error) {
+	caCertPEM, caKeyPEM, err := generateCA()
+	if err != nil {
+		return nil, err
+	}
+
+	certPEM, keyPEM, err := chiarpc.GenerateCertificate(caCertPEM, caKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(caCertPEM)
+
+	s := &Server{
+		caCertPEM: caCertPEM,
+		caKeyPEM:  caKeyPEM,
+		responses: make(map[string]interface{}),
+		requests:  make(map[string][]map[string]interface{}),
+	}
+	s.Server = httptest.NewUnstartedServer(http.HandlerFunc(s.handle))
+	s.Server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pool,
+	}
+	s.Server.Config.ConnState = s.trackConnection
+	s.Server.StartTLS()
+
+	return s, nil
+}
+
+// SetResponse sets the response for an endpoint or daemon command. The response is encoded to JSON and gets "success": true added to it.
+func (s *Server) SetResponse(endpoint string, resp interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responses[endpoint] = resp
+}
+
+// Requests returns the request bodies received for an endpoint, or the data of the messages received for a daemon command, in order
+func (s *Server) Requests(endpoint string) []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]map[string]interface{}(nil), s.requests[endpoint]...)
+}
+
+// Connections returns the number of connections the server has accepted
+func (s *Server) Connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.connections
+}
+
+// trackConnection counts new connections
+func (s *Server) trackConnection(_ net.Conn, state http.ConnState) {
+	if state != http.StateNew {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.connections++
+}
+
+// CASecretData returns CA Secret data for the server's private CA, for use with chiarpc.NewTLSConfigFromSecretData
+func (s *Server) CASecretData() map[string][]byte {
+	return map[string][]byte{
+		chiarpc.PrivateCACertKey: s.caCertPEM,
+		chiarpc.PrivateCAKeyKey:  s.caKeyPEM,
+	}
+}
+
+// HostPort returns the host and port the server is listening on
+func (s *Server) HostPort() (string, int) {
+	host, port, _ := net.SplitHostPort(s.Listener.Addr().String())
+	p, _ := strconv.Atoi(port)
+	return host, p
+}
+
+// handle records a request and writes the response configured for its endpoint, or serves a daemon websocket
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		websocket.Handler(s.handleDaemon).ServeHTTP(w, r)
+		return
+	}
+
+	endpoint := strings.TrimPrefix(r.URL.Path, "/")
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req map[string]interface{}
+	body, err := io.ReadAll(r.Body)
+	if err == nil {
+		err = json.Unmarshal(body, &req)
+	}
+	if err != nil {
+		writeJSON(w, map[string]interface{}{"success": false, "error": fmt.Sprintf("invalid request body: %v", err)})
+		return
+	}
+
+	resp, ok := s.record(endpoint, req)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	writeJSON(w, resp)
+}
+
+// handleDaemon answers every message on a daemon websocket with the response configured for its command,
+// acknowledging it with the message's request_id like the chia daemon does
+func (s *Server) handleDaemon(ws *websocket.Conn) {
+	for {
+		var msg map[string]interface{}
+		err := websocket.JSON.Receive(ws, &msg)
+		if err != nil {
+			return
+		}
+
+		command, _ := msg["command"].(string)
+		data, _ := msg["data"].(map[string]interface{})
+		resp, ok := s.record(command, data)
+		if !ok {
+			resp = map[string]interface{}{"success": false, "error": fmt.Sprintf("unknown command %s", command)}
+		}
+
+		err = websocket.JSON.Send(ws, map[string]interface{}{
+			"command":     command,
+			"ack":         true,
+			"origin":      "daemon",
+			"destination": msg["origin"],
+			"request_id":  msg["request_id"],
+			"data":        resp,
+		})
+		if err != nil {
+			return
+		}
+	}
+}
+
+// record records a request for an endpoint and returns the response configured for it, or false if there is none
+func (s *Server) record(endpoint string, req map[string]interface{}) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	s.requests[endpoint] = append(s.requests[endpoint], req)
+	resp, ok := s.responses[endpoint]
+	s.mu.Unlock()
+
+	if !ok {
+		return nil, false
+	}
+
+	// Round trip through JSON so any struct or map can be used as a response
+	var out map[string]interface{}
+	raw, err := json.Marshal(resp)
+	if err == nil {
+		err = json.Unmarshal(raw, &out)
+	}
+	if err != nil {
+		return map[string]interface{}{"success": false, "error": fmt.Sprintf("invalid canned response: %v", err)}, true
+	}
+	if out == nil {
+		out = make(map[string]interface{})
+	}
+	if _, ok := out["success"]; !ok {
+		out["success"] = true
+	}
+	return out, true
+}
+
+// writeJSON writes v to w as JSON
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// generateCA creates a self-signed private CA like the one chia creates in CHIA_ROOT/config/ssl/ca
+func generateCA() ([]byte, []byte, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject: pkix.Name{
+			CommonName:         "Chia CA",
+			Organization:       []string{"Chia"},
+			OrganizationalUnit: []string{"Organic Farming Division"},
+		},
+		NotBefore:             time.Now().Add(-48 * time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return certPEM, keyPEM, nil
+}
@@ -0,0 +1,280 @@
+/*
+Copyright 2023 Chia Network Inc.
+*/
+
+// Package chiarpc is a small client for the HTTPS RPC servers run by chia services, and the chia daemon's websocket.
+// Every chia RPC endpoint is a POST of a JSON object that returns a JSON object with a "success" key.
+// Daemon commands are websocket messages whose data is such an object.
+package chiarpc
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// defaultTimeout is the timeout for a single RPC request
+	defaultTimeout = 10 * time.Second
+
+	// idleConnTimeout is how long a keep-alive connection to an RPC server stays open unused.
+	// It outlasts the operator's status refresh interval, so periodic status checks reuse their connections
+	idleConnTimeout = 90 * time.Second
+)
+
+// transports holds one http.Transport per TLS config, so clients for the same CA share a connection pool instead of each leaking their own
+var transports sync.Map
+
+// Client is an RPC client for a single chia service's RPC server
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// response is the envelope shared by every chia RPC response
+type response struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// DaemonClient is a client for a chia daemon. The daemon only serves a websocket,
+// so each command dials it, sends a message and waits for the response with the same request_id
+type DaemonClient struct {
+	address   string
+	tlsConfig *tls.Config
+}
+
+// daemonMessage is a message sent to or received from the chia daemon's websocket
+type daemonMessage struct {
+	Command     string          `json:"command"`
+	Ack         bool            `json:"ack"`
+	Origin      string          `json:"origin"`
+	Destination string          `json:"destination"`
+	RequestID   string          `json:"request_id"`
+	Data        json.RawMessage `json:"data"`
+}
+
+// daemonOrigin is the service name the operator's daemon messages are sent from
+const daemonOrigin = "chia_operator"
+
+// NewClient creates a client for the chia RPC server at host and port, authenticating with the given TLS config.
+// Clients created with the same TLS config share a transport and its idle connections
+func NewClient(host string, port int, tlsConfig *tls.Config) *Client {
+	return &Client{
+		baseURL: fmt.Sprintf("https://%s", net.JoinHostPort(host, strconv.Itoa(port))),
+		httpClient: &http.Client{
+			Timeout:   defaultTimeout,
+			Transport: getTransport(tlsConfig),
+		},
+	}
+}
+
+// CloseIdleConnections closes the idle connections of the transport shared by clients using the given TLS config and forgets it.
+// Call it when a TLS config is replaced, clients created with the config afterwards get a new transport
+func CloseIdleConnections(tlsConfig *tls.Config) {
+	if transport, ok := transports.LoadAndDelete(tlsConfig); ok {
+		transport.(*http.Transport).CloseIdleConnections()
+	}
+}
+
+// getTransport returns the transport shared by clients using the given TLS config, creating it if needed
+func getTransport(tlsConfig *tls.Config) *http.Transport {
+	if transport, ok := transports.Load(tlsConfig); ok {
+		return transport.(*http.Transport)
+	}
+	transport, _ := transports.LoadOrStore(tlsConfig, &http.Transport{
+		TLSClientConfig: tlsConfig,
+		IdleConnTimeout: idleConnTimeout,
+	})
+	return transport.(*http.Transport)
+}
+
+// Do calls an RPC endpoint with the given request body and decodes its response into resp.
+// A nil request sends an empty JSON object, and a nil resp discards the response body.
+func (c *Client) Do(ctx context.Context, endpoint string, req interface{}, resp interface{}) error {
+	if req == nil {
+		req = struct{}{}
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("error encoding %s request: %v", endpoint, err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/%s", c.baseURL, endpoint), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating %s request: %v", endpoint, err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("error calling %s: %v", endpoint, err)
+	}
+	defer httpResp.Body.Close()
+
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading %s response: %v", endpoint, err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned HTTP status %d", endpoint, httpResp.StatusCode)
+	}
+
+	var envelope response
+	err = json.Unmarshal(respBody, &envelope)
+	if err != nil {
+		return fmt.Errorf("error decoding %s response: %v", endpoint, err)
+	}
+	if !envelope.Success {
+		return fmt.Errorf("%s was unsuccessful: %s", endpoint, envelope.Error)
+	}
+
+	if resp == nil {
+		return nil
+	}
+	err = json.Unmarshal(respBody, resp)
+	if err != nil {
+		return fmt.Errorf("error decoding %s response: %v", endpoint, err)
+	}
+	return nil
+}
+
+// Healthz checks that the RPC server is up and responding
+func (c *Client) Healthz(ctx context.Context) error {
+	return c.Do(ctx, "healthz", nil, nil)
+}
+
+// GetConnections returns the service's peer connections
+func (c *Client) GetConnections(ctx context.Context) ([]Connection, error) {
+	var resp struct {
+		Connections []Connection `json:"connections"`
+	}
+	err := c.Do(ctx, "get_connections", nil, &resp)
+	return resp.Connections, err
+}
+
+// GetNetworkInfo returns the name and address prefix of the network the service is running on
+func (c *Client) GetNetworkInfo(ctx context.Context) (NetworkInfo, error) {
+	var resp NetworkInfo
+	err := c.Do(ctx, "get_network_info", nil, &resp)
+	return resp, err
+}
+
+// NewDaemonClient creates a client for the chia daemon at host and port, authenticating with the given TLS config
+func NewDaemonClient(host string, port int, tlsConfig *tls.Config) *DaemonClient {
+	return &DaemonClient{
+		address:   net.JoinHostPort(host, strconv.Itoa(port)),
+		tlsConfig: tlsConfig,
+	}
+}
+
+// Do sends a command to the daemon with the given data and decodes the data of its response into resp.
+// Nil data sends an empty JSON object, and a nil resp discards the response data.
+func (c *DaemonClient) Do(ctx context.Context, command string, data interface{}, resp interface{}) error {
+	if data == nil {
+		data = struct{}{}
+	}
+	body, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("error encoding %s request: %v", command, err)
+	}
+	requestID, err := newDaemonRequestID()
+	if err != nil {
+		return fmt.Errorf("error creating %s request: %v", command, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	ws, err := c.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("error calling %s: %v", command, err)
+	}
+	defer ws.Close()
+	deadline, _ := ctx.Deadline()
+	err = ws.SetDeadline(deadline)
+	if err != nil {
+		return fmt.Errorf("error calling %s: %v", command, err)
+	}
+
+	err = websocket.JSON.Send(ws, daemonMessage{
+		Command:     command,
+		Origin:      daemonOrigin,
+		Destination: "daemon",
+		RequestID:   requestID,
+		Data:        body,
+	})
+	if err != nil {
+		return fmt.Errorf("error calling %s: %v", command, err)
+	}
+
+	// The daemon may send messages for other requests on the same websocket, only the response to this one is read
+	var msg daemonMessage
+	for msg.RequestID != requestID {
+		msg = daemonMessage{}
+		err = websocket.JSON.Receive(ws, &msg)
+		if err != nil {
+			return fmt.Errorf("error reading %s response: %v", command, err)
+		}
+	}
+
+	var envelope response
+	err = json.Unmarshal(msg.Data, &envelope)
+	if err != nil {
+		return fmt.Errorf("error decoding %s response: %v", command, err)
+	}
+	if !envelope.Success {
+		return fmt.Errorf("%s was unsuccessful: %s", command, envelope.Error)
+	}
+
+	if resp == nil {
+		return nil
+	}
+	err = json.Unmarshal(msg.Data, resp)
+	if err != nil {
+		return fmt.Errorf("error decoding %s response: %v", command, err)
+	}
+	return nil
+}
+
+// dial opens a websocket to the daemon over TLS
+func (c *DaemonClient) dial(ctx context.Context) (*websocket.Conn, error) {
+	config, err := websocket.NewConfig(fmt.Sprintf("wss://%s/", c.address), fmt.Sprintf("https://%s/", c.address))
+	if err != nil {
+		return nil, err
+	}
+	config.TlsConfig = c.tlsConfig
+
+	dialer := &tls.Dialer{Config: c.tlsConfig}
+	conn, err := dialer.DialContext(ctx, "tcp", c.address)
+	if err != nil {
+		return nil, err
+	}
+	ws, err := websocket.NewClient(config, conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return ws, nil
+}
+
+// newDaemonRequestID returns a random ID matching a daemon response to its request
+func newDaemonRequestID() (string, error) {
+	id := make([]byte, 16)
+	_, err := rand.Read(id)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2023 Chia Network Inc.
+*/
+
+package chiarpc_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chia-network/chia-operator/internal/chiarpc"
+	"github.com/chia-network/chia-operator/internal/chiarpc/chiarpctest"
+)
+
+func newTestServer(t *testing.T) (*chiarpctest.Server, string, int, *chiarpc.Client) {
+	t.Helper()
+	server, err := chiarpctest.NewServer()
+	if err != nil {
+		t.Fatalf("error starting fake RPC server: %v", err)
+	}
+	t.Cleanup(server.Close)
+
+	tlsConfig, err := chiarpc.NewTLSConfigFromSecretData(server.CASecretData())
+	if err != nil {
+		t.Fatalf("error creating TLS config: %v", err)
+	}
+	host, port := server.HostPort()
+	return server, host, port, chiarpc.NewClient(host, port, tlsConfig)
+}
+
+func TestGetBlockchainState(t *testing.T) {
+	server, host, port, client := newTestServer(t)
+	server.SetResponse("get_blockchain_state", map[string]interface{}{
+		"blockchain_state": map[string]interface{}{
+			"peak": map[string]interface{}{
+				"height":      4000000,
+				"header_hash": "0xabc",
+				"weight":      "123456789012345678901234567890",
+			},
+			"sync": map[string]interface{}{
+				"sync_mode":            false,
+				"synced":               true,
+				"sync_progress_height": 0,
+				"sync_tip_height":      0,
+			},
+			"space":   "30000000000000000000",
+			"node_id": "f00",
+		},
+	})
+
+	node := &chiarpc.FullNodeClient{Client: client}
+	state, err := node.GetBlockchainState(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if state.Peak == nil || state.Peak.Height != 4000000 {
+		t.Errorf("expected peak height 4000000, got %+v", state.Peak)
+	}
+	if !state.Sync.Synced {
+		t.Errorf("expected synced to be true")
+	}
+	if state.NodeID != "f00" {
+		t.Errorf("expected node ID f00, got %q", state.NodeID)
+	}
+
+	// Clients that don't trust the private CA or lack a client certificate are rejected
+	_, err = chiarpc.NewFullNodeClient(host, port, nil).GetBlockchainState(context.Background())
+	if err == nil {
+		t.Errorf("expected an error connecting without the private CA")
+	}
+}
+
+func TestGetConnections(t *testing.T) {
+	server, _, _, client := newTestServer(t)
+	server.SetResponse("get_connections", map[string]interface{}{
+		"connections": []map[string]interface{}{
+			{"node_id": "a", "type": 1, "peer_host": "10.0.0.1", "peer_port": 8444, "peak_height": 10},
+			{"node_id": "b", "type": 6, "peer_host": "10.0.0.2", "peer_port": 50000},
+		},
+	})
+
+	conns, err := client.GetConnections(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(conns) != 2 {
+		t.Fatalf("expected 2 connections, got %d", len(conns))
+	}
+	if conns[0].Type != chiarpc.NodeTypeFullNode || conns[0].PeakHeight == nil || *conns[0].PeakHeight != 10 {
+		t.Errorf("unexpected first connection %+v", conns[0])
+	}
+	if conns[1].Type != chiarpc.NodeTypeWallet || conns[1].PeakHeight != nil {
+		t.Errorf("unexpected second connection %+v", conns[1])
+	}
+}
+
+func TestGetPlots(t *testing.T) {
+	server, _, _, client := newTestServer(t)
+	server.SetResponse("get_plots", chiarpc.Plots{
+		Plots: []chiarpc.Plot{
+			{Filename: "/plots/a.plot", Size: 32, FileSize: 108000000000},
+		},
+		FailedToOpenFilenames: []string{"/plots/b.plot"},
+	})
+
+	harvester := &chiarpc.HarvesterClient{Client: client}
+	plots, err := harvester.GetPlots(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(plots.Plots) != 1 || plots.Plots[0].Size != 32 {
+		t.Errorf("unexpected plots %+v", plots.Plots)
+	}
+	if len(plots.FailedToOpenFilenames) != 1 {
+		t.Errorf("expected 1 failed plot, got %v", plots.FailedToOpenFilenames)
+	}
+}
+
+func TestWalletSync(t *testing.T) {
+	server, _, _, client := newTestServer(t)
+	server.SetResponse("get_sync_status", chiarpc.WalletSyncStatus{Synced: true})
+	server.SetResponse("get_height_info", map[string]interface{}{"height": 123})
+	server.SetResponse("log_in", map[string]interface{}{"fingerprint": 42})
+
+	wallet := &chiarpc.WalletClient{Client: client}
+	status, err := wallet.GetSyncStatus(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !status.Synced {
+		t.Errorf("expected wallet to be synced")
+	}
+
+	height, err := wallet.GetHeightInfo(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if height != 123 {
+		t.Errorf("expected height 123, got %d", height)
+	}
+
+	err = wallet.LogIn(context.Background(), 42)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reqs := server.Requests("log_in")
+	if len(reqs) != 1 || reqs[0]["fingerprint"] != float64(42) {
+		t.Errorf("unexpected log_in requests %v", reqs)
+	}
+}
+
+func TestUnsuccessfulResponse(t *testing.T) {
+	server, _, _, client := newTestServer(t)
+	server.SetResponse("get_wallet_balance", map[string]interface{}{"success": false, "error": "wallet not found"})
+
+	wallet := &chiarpc.WalletClient{Client: client}
+	_, err := wallet.GetWalletBalance(context.Background(), 5)
+	if err == nil {
+		t.Fatalf("expected an error for an unsuccessful response")
+	}
+
+	_, err = wallet.GetWallets(context.Background())
+	if err == nil {
+		t.Fatalf("expected an error for an endpoint with no response")
+	}
+}
+
+func TestClientsShareConnections(t *testing.T) {
+	server, host, port, client := newTestServer(t)
+	server.SetResponse("healthz", map[string]interface{}{})
+	tlsConfig, err := chiarpc.NewTLSConfigFromSecretData(server.CASecretData())
+	if err != nil {
+		t.Fatalf("error creating TLS config: %v", err)
+	}
+
+	// Clients with the same TLS config reuse one keep-alive connection
+	for i := 0; i < 3; i++ {
+		err = chiarpc.NewClient(host, port, tlsConfig).Healthz(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if got := server.Connections(); got != 1 {
+		t.Fatalf("expected 1 connection for clients sharing a TLS config, got %d", got)
+	}
+
+	// A client with another TLS config has its own transport
+	err = client.Healthz(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := server.Connections(); got != 2 {
+		t.Fatalf("expected 2 connections for clients with different TLS configs, got %d", got)
+	}
+
+	// A replaced TLS config's idle connections are closed, and new clients get a new transport
+	chiarpc.CloseIdleConnections(tlsConfig)
+	err = chiarpc.NewClient(host, port, tlsConfig).Healthz(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := server.Connections(); got != 3 {
+		t.Fatalf("expected a new connection after closing idle connections, got %d connections", got)
+	}
+}
+
+func TestDaemon(t *testing.T) {
+	server, host, port, _ := newTestServer(t)
+	server.SetResponse("get_version", map[string]interface{}{"version": "2.1.4"})
+	server.SetResponse("running_services", map[string]interface{}{"running_services": []string{"chia_full_node"}})
+	server.SetResponse("is_running", map[string]interface{}{"service_name": "chia_wallet", "is_running": false})
+	tlsConfig, err := chiarpc.NewTLSConfigFromSecretData(server.CASecretData())
+	if err != nil {
+		t.Fatalf("error creating TLS config: %v", err)
+	}
+
+	daemon := chiarpc.NewDaemonClient(host, port, tlsConfig)
+	version, err := daemon.GetVersion(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if version != "2.1.4" {
+		t.Errorf("expected version 2.1.4, got %s", version)
+	}
+
+	services, err := daemon.RunningServices(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(services) != 1 || services[0] != "chia_full_node" {
+		t.Errorf("unexpected running services %v", services)
+	}
+
+	running, err := daemon.IsRunning(context.Background(), "chia_wallet")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if running {
+		t.Errorf("expected chia_wallet not to be running")
+	}
+	reqs := server.Requests("is_running")
+	if len(reqs) != 1 || reqs[0]["service"] != "chia_wallet" {
+		t.Errorf("unexpected is_running requests %v", reqs)
+	}
+
+	_, err = daemon.GetStatus(context.Background())
+	if err == nil {
+		t.Fatalf("expected an error for a command with no response")
+	}
+}
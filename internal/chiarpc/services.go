@@ -0,0 +1,200 @@
+/*
+Copyright 2023 Chia Network Inc.
+*/
+
+package chiarpc
+
+import (
+	"context"
+	"crypto/tls"
+)
+
+// FullNodeClient is an RPC client for a chia full_node
+type FullNodeClient struct {
+	*Client
+}
+
+// NewFullNodeClient creates a client for the full_node RPC server at host and port
+func NewFullNodeClient(host string, port int, tlsConfig *tls.Config) *FullNodeClient {
+	return &FullNodeClient{Client: NewClient(host, port, tlsConfig)}
+}
+
+// GetBlockchainState returns the full_node's view of the blockchain peak and its sync status
+func (c *FullNodeClient) GetBlockchainState(ctx context.Context) (BlockchainState, error) {
+	var resp struct {
+		BlockchainState BlockchainState `json:"blockchain_state"`
+	}
+	err := c.Do(ctx, "get_blockchain_state", nil, &resp)
+	return resp.BlockchainState, err
+}
+
+// FarmerClient is an RPC client for a chia farmer
+type FarmerClient struct {
+	*Client
+}
+
+// NewFarmerClient creates a client for the farmer RPC server at host and port
+func NewFarmerClient(host string, port int, tlsConfig *tls.Config) *FarmerClient {
+	return &FarmerClient{Client: NewClient(host, port, tlsConfig)}
+}
+
+// GetHarvesters returns a summary of every harvester connected to the farmer
+func (c *FarmerClient) GetHarvesters(ctx context.Context) ([]HarvesterSummary, error) {
+	var resp struct {
+		Harvesters []HarvesterSummary `json:"harvesters"`
+	}
+	err := c.Do(ctx, "get_harvesters_summary", nil, &resp)
+	return resp.Harvesters, err
+}
+
+// GetPoolState returns the state of every pool the farmer is farming to
+func (c *FarmerClient) GetPoolState(ctx context.Context) ([]PoolState, error) {
+	var resp struct {
+		PoolState []PoolState `json:"pool_state"`
+	}
+	err := c.Do(ctx, "get_pool_state", nil, &resp)
+	return resp.PoolState, err
+}
+
+// GetSignagePoints returns the signage points the farmer has recently received and the proofs found for them
+func (c *FarmerClient) GetSignagePoints(ctx context.Context) ([]SignagePointWithProofs, error) {
+	var resp struct {
+		SignagePoints []SignagePointWithProofs `json:"signage_points"`
+	}
+	err := c.Do(ctx, "get_signage_points", nil, &resp)
+	return resp.SignagePoints, err
+}
+
+// GetRewardTargets returns the farmer's farmer and pool reward addresses
+func (c *FarmerClient) GetRewardTargets(ctx context.Context) (RewardTargets, error) {
+	var resp RewardTargets
+	err := c.Do(ctx, "get_reward_targets", map[string]interface{}{"search_for_private_key": false}, &resp)
+	return resp, err
+}
+
+// HarvesterClient is an RPC client for a chia harvester
+type HarvesterClient struct {
+	*Client
+}
+
+// NewHarvesterClient creates a client for the harvester RPC server at host and port
+func NewHarvesterClient(host string, port int, tlsConfig *tls.Config) *HarvesterClient {
+	return &HarvesterClient{Client: NewClient(host, port, tlsConfig)}
+}
+
+// GetPlots returns the plots the harvester has loaded and the plot files it could not load
+func (c *HarvesterClient) GetPlots(ctx context.Context) (Plots, error) {
+	var resp Plots
+	err := c.Do(ctx, "get_plots", nil, &resp)
+	return resp, err
+}
+
+// GetPlotDirectories returns the directories the harvester is configured to look for plots in
+func (c *HarvesterClient) GetPlotDirectories(ctx context.Context) ([]string, error) {
+	var resp struct {
+		Directories []string `json:"directories"`
+	}
+	err := c.Do(ctx, "get_plot_directories", nil, &resp)
+	return resp.Directories, err
+}
+
+// WalletClient is an RPC client for a chia wallet
+type WalletClient struct {
+	*Client
+}
+
+// NewWalletClient creates a client for the wallet RPC server at host and port
+func NewWalletClient(host string, port int, tlsConfig *tls.Config) *WalletClient {
+	return &WalletClient{Client: NewClient(host, port, tlsConfig)}
+}
+
+// GetSyncStatus returns whether the wallet is synced
+func (c *WalletClient) GetSyncStatus(ctx context.Context) (WalletSyncStatus, error) {
+	var resp WalletSyncStatus
+	err := c.Do(ctx, "get_sync_status", nil, &resp)
+	return resp, err
+}
+
+// GetHeightInfo returns the height the wallet is synced to
+func (c *WalletClient) GetHeightInfo(ctx context.Context) (uint32, error) {
+	var resp struct {
+		Height uint32 `json:"height"`
+	}
+	err := c.Do(ctx, "get_height_info", nil, &resp)
+	return resp.Height, err
+}
+
+// GetLoggedInFingerprint returns the fingerprint of the key the wallet is logged in with, or nil if it isn't logged in
+func (c *WalletClient) GetLoggedInFingerprint(ctx context.Context) (*uint32, error) {
+	var resp struct {
+		Fingerprint *uint32 `json:"fingerprint"`
+	}
+	err := c.Do(ctx, "get_logged_in_fingerprint", nil, &resp)
+	return resp.Fingerprint, err
+}
+
+// GetPublicKeys returns the fingerprints of every key in the wallet's keychain
+func (c *WalletClient) GetPublicKeys(ctx context.Context) ([]uint32, error) {
+	var resp struct {
+		Fingerprints []uint32 `json:"public_key_fingerprints"`
+	}
+	err := c.Do(ctx, "get_public_keys", nil, &resp)
+	return resp.Fingerprints, err
+}
+
+// LogIn switches the wallet to the key with the given fingerprint
+func (c *WalletClient) LogIn(ctx context.Context, fingerprint uint32) error {
+	return c.Do(ctx, "log_in", map[string]interface{}{"fingerprint": fingerprint}, nil)
+}
+
+// GetWallets returns every wallet belonging to the logged in key
+func (c *WalletClient) GetWallets(ctx context.Context) ([]WalletInfo, error) {
+	var resp struct {
+		Wallets []WalletInfo `json:"wallets"`
+	}
+	err := c.Do(ctx, "get_wallets", nil, &resp)
+	return resp.Wallets, err
+}
+
+// GetWalletBalance returns the balance of the wallet with the given ID
+func (c *WalletClient) GetWalletBalance(ctx context.Context, walletID uint32) (WalletBalance, error) {
+	var resp struct {
+		WalletBalance WalletBalance `json:"wallet_balance"`
+	}
+	err := c.Do(ctx, "get_wallet_balance", map[string]interface{}{"wallet_id": walletID}, &resp)
+	return resp.WalletBalance, err
+}
+
+// GetVersion returns the chia version the daemon is running
+func (c *DaemonClient) GetVersion(ctx context.Context) (string, error) {
+	var resp struct {
+		Version string `json:"version"`
+	}
+	err := c.Do(ctx, "get_version", nil, &resp)
+	return resp.Version, err
+}
+
+// GetStatus returns whether the daemon's keychain has been initialized with a key
+func (c *DaemonClient) GetStatus(ctx context.Context) (DaemonStatus, error) {
+	var resp DaemonStatus
+	err := c.Do(ctx, "get_status", nil, &resp)
+	return resp, err
+}
+
+// RunningServices returns the names of the chia services the daemon is running, eg. chia_full_node
+func (c *DaemonClient) RunningServices(ctx context.Context) ([]string, error) {
+	var resp struct {
+		RunningServices []string `json:"running_services"`
+	}
+	err := c.Do(ctx, "running_services", nil, &resp)
+	return resp.RunningServices, err
+}
+
+// IsRunning returns whether the daemon is running the chia service with the given name, eg. chia_wallet
+func (c *DaemonClient) IsRunning(ctx context.Context, service string) (bool, error) {
+	var resp struct {
+		IsRunning bool `json:"is_running"`
+	}
+	err := c.Do(ctx, "is_running", map[string]interface{}{"service": service}, &resp)
+	return resp.IsRunning, err
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2023 Chia Network Inc.
+*/
+
+package chiarpc
+
+import (
+	"encoding/json"
+)
+
+// NodeType is the type of a chia service, as reported in peer connections
+type NodeType int
+
+const (
+	NodeTypeFullNode   NodeType = 1
+	NodeTypeHarvester  NodeType = 2
+	NodeTypeFarmer     NodeType = 3
+	NodeTypeTimelord   NodeType = 4
+	NodeTypeIntroducer NodeType = 5
+	NodeTypeWallet     NodeType = 6
+	NodeTypeDataLayer  NodeType = 7
+)
+
+// Connection is a peer connection of a chia service
+type Connection struct {
+	NodeID          string   `json:"node_id"`
+	Type            NodeType `json:"type"`
+	PeerHost        string   `json:"peer_host"`
+	PeerPort        uint16   `json:"peer_port"`
+	PeerServerPort  uint16   `json:"peer_server_port"`
+	BytesRead       uint64   `json:"bytes_read"`
+	BytesWritten    uint64   `json:"bytes_written"`
+	CreationTime    float64  `json:"creation_time"`
+	LastMessageTime float64  `json:"last_message_time"`
+	PeakHeight      *uint32  `json:"peak_height"`
+}
+
+// NetworkInfo is the network a chia service is running on
+type NetworkInfo struct {
+	NetworkName   string `json:"network_name"`
+	NetworkPrefix string `json:"network_prefix"`
+}
+
+// BlockchainState is a full_node's view of the blockchain
+type BlockchainState struct {
+	Peak        *BlockRecord `json:"peak"`
+	Sync        SyncState    `json:"sync"`
+	Difficulty  uint64       `json:"difficulty"`
+	Space       json.Number  `json:"space"`
+	MempoolSize uint64       `json:"mempool_size"`
+	NodeID      string       `json:"node_id"`
+}
+
+// BlockRecord is a summary of a block in the blockchain
+type BlockRecord struct {
+	HeaderHash string      `json:"header_hash"`
+	Height     uint32      `json:"height"`
+	Weight     json.Number `json:"weight"`
+	Timestamp  *uint64     `json:"timestamp"`
+}
+
+// SyncState is the sync status of a full_node
+type SyncState struct {
+	SyncMode           bool   `json:"sync_mode"`
+	Synced             bool   `json:"synced"`
+	SyncProgressHeight uint32 `json:"sync_progress_height"`
+	SyncTipHeight      uint32 `json:"sync_tip_height"`
+}
+
+// HarvesterConnection identifies a harvester connected to a farmer
+type HarvesterConnection struct {
+	NodeID string `json:"node_id"`
+	Host   string `json:"host"`
+	Port   uint16 `json:"port"`
+}
+
+// HarvesterSummary is a farmer's summary of one of its harvesters
+type HarvesterSummary struct {
+	Connection             HarvesterConnection `json:"connection"`
+	Plots                  int                 `json:"plots"`
+	FailedToOpenFilenames  int                 `json:"failed_to_open_filenames"`
+	NoKeyFilenames         int                 `json:"no_key_filenames"`
+	Duplicates             int                 `json:"duplicates"`
+	TotalPlotSize          uint64              `json:"total_plot_size"`
+	TotalEffectivePlotSize uint64              `json:"total_effective_plot_size"`
+	Syncing                *HarvesterSync      `json:"syncing"`
+	LastSyncTime           *float64            `json:"last_sync_time"`
+}
+
+// HarvesterSync is the progress of a harvester's initial plot sync with a farmer
+type HarvesterSync struct {
+	Initial            bool `json:"initial"`
+	PlotFilesProcessed int  `json:"plot_files_processed"`
+	PlotFilesTotal     int  `json:"plot_files_total"`
+}
+
+// PoolState is a farmer's state for a single pool it is farming to
+type PoolState struct {
+	P2SingletonPuzzleHash        string      `json:"p2_singleton_puzzle_hash"`
+	PoolConfig                   PoolConfig  `json:"pool_config"`
+	PointsFoundSinceStart        uint64      `json:"points_found_since_start"`
+	PointsAcknowledgedSinceStart uint64      `json:"points_acknowledged_since_start"`
+	PointsFound24h               [][]float64 `json:"points_found_24h"`
+	PointsAcknowledged24h        [][]float64 `json:"points_acknowledged_24h"`
+	CurrentPoints                uint64      `json:"current_points"`
+	CurrentDifficulty            *uint64     `json:"current_difficulty"`
+	PlotCount                    int         `json:"plot_count"`
+	PoolErrors24h                []PoolError `json:"pool_errors_24h"`
+	AuthenticationTokenTimeout   *uint8      `json:"authentication_token_timeout"`
+}
+
+// PoolConfig is the configuration of a pool a farmer is farming to
+type PoolConfig struct {
+	LauncherID            string `json:"launcher_id"`
+	PoolURL               string `json:"pool_url"`
+	PayoutInstructions    string `json:"payout_instructions"`
+	TargetPuzzleHash      string `json:"target_puzzle_hash"`
+	P2SingletonPuzzleHash string `json:"p2_singleton_puzzle_hash"`
+	OwnerPublicKey        string `json:"owner_public_key"`
+}
+
+// PoolError is an error returned by a pool to a farmer
+type PoolError struct {
+	ErrorCode    int    `json:"error_code"`
+	ErrorMessage string `json:"error_message"`
+}
+
+// SignagePointWithProofs is a signage point a farmer received and the proofs it found for it
+type SignagePointWithProofs struct {
+	SignagePoint SignagePoint      `json:"signage_point"`
+	Proofs       []json.RawMessage `json:"proofs"`
+}
+
+// SignagePoint is a signage point broadcast to farmers
+type SignagePoint struct {
+	ChallengeHash     string `json:"challenge_hash"`
+	ChallengeChainSP  string `json:"challenge_chain_sp"`
+	RewardChainSP     string `json:"reward_chain_sp"`
+	Difficulty        uint64 `json:"difficulty"`
+	SubSlotIters      uint64 `json:"sub_slot_iters"`
+	SignagePointIndex uint8  `json:"signage_point_index"`
+	PeakHeight        uint32 `json:"peak_height"`
+}
+
+// RewardTargets are the addresses a farmer sends farmer and pool rewards to
+type RewardTargets struct {
+	FarmerTarget string `json:"farmer_target"`
+	PoolTarget   string `json:"pool_target"`
+}
+
+// Plots is a harvester's plot inventory
+type Plots struct {
+	Plots                 []Plot   `json:"plots"`
+	FailedToOpenFilenames []string `json:"failed_to_open_filenames"`
+	NotFoundFilenames     []string `json:"not_found_filenames"`
+}
+
+// Plot is a plot loaded by a harvester
+type Plot struct {
+	Filename               string  `json:"filename"`
+	Size                   uint8   `json:"size"`
+	PlotID                 string  `json:"plot_id"`
+	PoolPublicKey          *string `json:"pool_public_key"`
+	PoolContractPuzzleHash *string `json:"pool_contract_puzzle_hash"`
+	PlotPublicKey          string  `json:"plot_public_key"`
+	FileSize               uint64  `json:"file_size"`
+	TimeModified           float64 `json:"time_modified"`
+	CompressionLevel       *uint8  `json:"compression_level"`
+}
+
+// WalletSyncStatus is the sync status of a wallet
+type WalletSyncStatus struct {
+	Synced             bool `json:"synced"`
+	Syncing            bool `json:"syncing"`
+	GenesisInitialized bool `json:"genesis_initialized"`
+}
+
+// WalletInfo describes a single wallet belonging to a key
+type WalletInfo struct {
+	ID   uint32 `json:"id"`
+	Name string `json:"name"`
+	Type int    `json:"type"`
+	Data string `json:"data"`
+}
+
+// WalletBalance is the balance of a single wallet, in mojos
+type WalletBalance struct {
+	WalletID                 uint32 `json:"wallet_id"`
+	ConfirmedWalletBalance   uint64 `json:"confirmed_wallet_balance"`
+	UnconfirmedWalletBalance uint64 `json:"unconfirmed_wallet_balance"`
+	SpendableBalance         uint64 `json:"spendable_balance"`
+	MaxSendAmount            uint64 `json:"max_send_amount"`
+	PendingChange            uint64 `json:"pending_change"`
+	UnspentCoinCount         int    `json:"unspent_coin_count"`
+	Fingerprint              uint32 `json:"fingerprint"`
+}
+
+// DaemonStatus is the status of a chia daemon
+type DaemonStatus struct {
+	GenesisInitialized bool `json:"genesis_initialized"`
+}
@@ -7,19 +7,28 @@ package controller
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/yaml"
 
 	k8schianetv1 "github.com/chia-network/chia-operator/api/v1"
+	"github.com/chia-network/chia-operator/internal/chiarpc"
 	"github.com/cisco-open/operator-tools/pkg/reconciler"
 )
 
@@ -31,6 +40,20 @@ const (
 	farmerRPCPort = 8559
 )
 
+const (
+	// chiaFarmerPoolListKey is the config overrides ConfigMap key the farmer's pool_list is rendered to, merged after any user overrides
+	chiaFarmerPoolListKey = "30-pool-list.yaml"
+
+	// poolListChecksumAnnotation is set on farmer pods to restart them when their pool_list changes
+	poolListChecksumAnnotation = "k8s.chia.net/pool-list-checksum"
+
+	// chiaFarmerRewardAddressesKey is the config overrides ConfigMap key the farmer's reward addresses are rendered to, merged after any user overrides
+	chiaFarmerRewardAddressesKey = "30-reward-addresses.yaml"
+
+	// rewardAddressesChecksumAnnotation is set on farmer pods to restart them when their reward addresses change
+	rewardAddressesChecksumAnnotation = "k8s.chia.net/reward-addresses-checksum"
+)
+
 // ChiaFarmerReconciler reconciles a ChiaFarmer object
 type ChiaFarmerReconciler struct {
 	client.Client
@@ -42,6 +65,13 @@ type ChiaFarmerReconciler struct {
 //+kubebuilder:rbac:groups=k8s.chia.net,resources=chiafarmers/finalizers,verbs=update
 //+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch
 //+kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=tcproutes;tlsroutes,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create
+//+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors;prometheusrules,verbs=get;list;watch;create;update;patch;delete
 
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.14.4/pkg/reconcile
@@ -63,8 +93,9 @@ func (r *ChiaFarmerReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	}
 
 	// Reconcile ChiaFarmer owned objects
+	peersConfig, daemonConfig, rpcConfig := getServiceConfigs(farmer.Spec.Services)
 	srv := r.assembleBaseService(ctx, farmer)
-	res, err := reconcileService(ctx, resourceReconciler, srv)
+	res, err := reconcileService(ctx, resourceReconciler, srv, serviceEnabled(peersConfig))
 	if err != nil {
 		if res == nil {
 			res = &reconcile.Result{}
@@ -72,8 +103,26 @@ func (r *ChiaFarmerReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return *res, fmt.Errorf("ChiaFarmerReconciler ChiaFarmer=%s encountered error reconciling farmer Service: %v", req.NamespacedName, err)
 	}
 
+	srv = r.assembleDaemonService(ctx, farmer)
+	res, err = reconcileService(ctx, resourceReconciler, srv, serviceEnabled(daemonConfig))
+	if err != nil {
+		if res == nil {
+			res = &reconcile.Result{}
+		}
+		return *res, fmt.Errorf("ChiaFarmerReconciler ChiaFarmer=%s encountered error reconciling farmer daemon Service: %v", req.NamespacedName, err)
+	}
+
+	srv = r.assembleRPCService(ctx, farmer)
+	res, err = reconcileService(ctx, resourceReconciler, srv, serviceEnabled(rpcConfig))
+	if err != nil {
+		if res == nil {
+			res = &reconcile.Result{}
+		}
+		return *res, fmt.Errorf("ChiaFarmerReconciler ChiaFarmer=%s encountered error reconciling farmer RPC Service: %v", req.NamespacedName, err)
+	}
+
 	srv = r.assembleChiaExporterService(ctx, farmer)
-	res, err = reconcileService(ctx, resourceReconciler, srv)
+	res, err = reconcileService(ctx, resourceReconciler, srv, chiaExporterEnabled(farmer.Spec.ChiaExporterConfig))
 	if err != nil {
 		if res == nil {
 			res = &reconcile.Result{}
@@ -81,8 +130,104 @@ func (r *ChiaFarmerReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return *res, fmt.Errorf("ChiaFarmerReconciler ChiaFarmer=%s encountered error reconciling farmer chia-exporter Service: %v", req.NamespacedName, err)
 	}
 
-	deploy := r.assembleDeployment(ctx, farmer)
-	res, err = reconcileDeployment(ctx, resourceReconciler, deploy)
+	networkPolicy := r.assembleNetworkPolicy(ctx, farmer)
+	res, err = reconcileNetworkPolicy(ctx, resourceReconciler, networkPolicy, networkPolicyEnabled(farmer.Spec.NetworkPolicy))
+	if err != nil {
+		if res == nil {
+			res = &reconcile.Result{}
+		}
+		return *res, fmt.Errorf("ChiaFarmerReconciler ChiaFarmer=%s encountered error reconciling farmer NetworkPolicy: %v", req.NamespacedName, err)
+	}
+
+	for _, exposure := range r.getPortExposures(ctx, farmer) {
+		res, err = reconcilePortExposure(ctx, r.Client, resourceReconciler, exposure)
+		if err != nil {
+			if res == nil {
+				res = &reconcile.Result{}
+			}
+			return *res, fmt.Errorf("ChiaFarmerReconciler ChiaFarmer=%s encountered error reconciling farmer %s routes: %v", req.NamespacedName, exposure.objMeta.Name, err)
+		}
+	}
+
+	monitor := r.assembleChiaExporterServiceMonitor(ctx, farmer)
+	res, err = reconcileOptionalResource(ctx, r.Client, resourceReconciler, monitor, serviceMonitorEnabled(farmer.Spec.ChiaExporterConfig))
+	if err != nil {
+		if res == nil {
+			res = &reconcile.Result{}
+		}
+		return *res, fmt.Errorf("ChiaFarmerReconciler ChiaFarmer=%s encountered error reconciling farmer chia-exporter ServiceMonitor: %v", req.NamespacedName, err)
+	}
+
+	rule := r.assembleChiaExporterPrometheusRule(ctx, farmer)
+	res, err = reconcileOptionalResource(ctx, r.Client, resourceReconciler, rule, prometheusRuleEnabled(farmer.Spec.ChiaExporterConfig))
+	if err != nil {
+		if res == nil {
+			res = &reconcile.Result{}
+		}
+		return *res, fmt.Errorf("ChiaFarmerReconciler ChiaFarmer=%s encountered error reconciling farmer chia-exporter PrometheusRule: %v", req.NamespacedName, err)
+	}
+
+	overrides, err := getChiaConfigOverridesData(ctx, farmer.Spec.ChiaConfig.ConfigOverrides)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("ChiaFarmerReconciler ChiaFarmer=%s encountered error assembling config overrides: %v", req.NamespacedName, err)
+	}
+	overridesRef, err := getChiaConfigOverridesRefData(ctx, r.Client, farmer.Namespace, farmer.Spec.ChiaConfig.ConfigOverrides)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("ChiaFarmerReconciler ChiaFarmer=%s encountered error reading referenced config overrides: %v", req.NamespacedName, err)
+	}
+	if len(farmer.Spec.ChiaConfig.Pools) != 0 {
+		overrides[chiaFarmerPoolListKey], err = getPoolListOverride(farmer.Spec.ChiaConfig.Pools)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("ChiaFarmerReconciler ChiaFarmer=%s encountered error assembling pool_list config: %v", req.NamespacedName, err)
+		}
+	}
+	if farmer.Spec.ChiaConfig.FarmerRewardAddress != nil || farmer.Spec.ChiaConfig.PoolRewardAddress != nil {
+		overrides[chiaFarmerRewardAddressesKey], err = getRewardAddressesOverride(farmer.Spec.ChiaConfig)
+		meta.SetStatusCondition(&farmer.Status.Conditions, getRewardAddressesValidCondition(farmer.Generation, err))
+		if err != nil {
+			// The spec has to change before the farmer can be deployed, which triggers another reconcile
+			farmer.Status.Ready = false
+			err = r.Status().Update(ctx, &farmer)
+			if err != nil {
+				log.Error(err, fmt.Sprintf("ChiaFarmerReconciler ChiaFarmer=%s unable to update ChiaFarmer status", req.NamespacedName))
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{}, nil
+		}
+	} else {
+		meta.RemoveStatusCondition(&farmer.Status.Conditions, k8schianetv1.ChiaFarmerConditionRewardAddressesValid)
+	}
+	if ipFamilies, _ := getServiceIPFamilies(farmer.Spec.Services); len(ipFamilies) != 0 {
+		overrides[chiaIPFamiliesKey], err = getIPFamiliesOverride("farmer", ipFamilies)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("ChiaFarmerReconciler ChiaFarmer=%s encountered error assembling IP family config: %v", req.NamespacedName, err)
+		}
+	}
+	configMap := r.assembleConfigOverridesConfigMap(ctx, farmer, overrides)
+	res, err = reconcileConfigMap(ctx, resourceReconciler, configMap, len(overrides) != 0)
+	if err != nil {
+		if res == nil {
+			res = &reconcile.Result{}
+		}
+		return *res, fmt.Errorf("ChiaFarmerReconciler ChiaFarmer=%s encountered error reconciling farmer config overrides ConfigMap: %v", req.NamespacedName, err)
+	}
+
+	if chiaRootClaimCreated(farmer.Spec.Storage) {
+		pvc, err := r.assembleChiaRootPersistentVolumeClaim(ctx, farmer)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("ChiaFarmerReconciler ChiaFarmer=%s encountered error assembling farmer CHIA_ROOT PersistentVolumeClaim: %v", req.NamespacedName, err)
+		}
+		res, err = reconcilePersistentVolumeClaim(ctx, resourceReconciler, pvc)
+		if err != nil {
+			if res == nil {
+				res = &reconcile.Result{}
+			}
+			return *res, fmt.Errorf("ChiaFarmerReconciler ChiaFarmer=%s encountered error reconciling farmer CHIA_ROOT PersistentVolumeClaim: %v", req.NamespacedName, err)
+		}
+	}
+
+	deploy := r.assembleDeployment(ctx, farmer, overrides, overridesRef)
+	res, err = reconcileDeployment(ctx, resourceReconciler, deploy, true)
 	if err != nil {
 		if res == nil {
 			res = &reconcile.Result{}
@@ -92,86 +237,206 @@ func (r *ChiaFarmerReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 	// Update CR status
 	farmer.Status.Ready = true
+	r.updateFarmStatus(ctx, &farmer)
 	err = r.Status().Update(ctx, &farmer)
 	if err != nil {
 		log.Error(err, fmt.Sprintf("ChiaFarmerReconciler ChiaFarmer=%s unable to update ChiaFarmer status", req.NamespacedName))
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: statusRefreshInterval}, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *ChiaFarmerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&k8schianetv1.ChiaFarmer{}).
+		// Only spec changes trigger reconciles, status updates would otherwise requeue the farmer immediately instead of after statusRefreshInterval
+		For(&k8schianetv1.ChiaFarmer{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Complete(r)
 }
 
-// assembleBaseService assembles the main Service resource for a Chiafarmer CR
+// assembleBaseService assembles the main peer port Service resource for a ChiaFarmer CR
 func (r *ChiaFarmerReconciler) assembleBaseService(ctx context.Context, farmer k8schianetv1.ChiaFarmer) corev1.Service {
+	peers, _, _ := getServiceConfigs(farmer.Spec.Services)
+	objMeta := metav1.ObjectMeta{
+		Name:            fmt.Sprintf("%s-farmer", farmer.Name),
+		Namespace:       farmer.Namespace,
+		Labels:          r.getCommonLabels(ctx, farmer, farmer.Spec.AdditionalMetadata.Labels),
+		Annotations:     farmer.Spec.AdditionalMetadata.Annotations,
+		OwnerReferences: r.getOwnerReference(ctx, farmer),
+	}
+	srv := getPortService(objMeta, r.getCommonLabels(ctx, farmer, farmer.Spec.AdditionalMetadata.Labels), peers, corev1.ServiceType(farmer.Spec.ServiceType), corev1.ServicePort{
+		Port:       farmerPort,
+		TargetPort: intstr.FromString("peers"),
+		Protocol:   "TCP",
+		Name:       "peers",
+	})
+	srv.Spec.IPFamilies, srv.Spec.IPFamilyPolicy = getServiceIPFamilies(farmer.Spec.Services)
+	return srv
+}
+
+// assembleDaemonService assembles the daemon port Service resource for a ChiaFarmer CR
+func (r *ChiaFarmerReconciler) assembleDaemonService(ctx context.Context, farmer k8schianetv1.ChiaFarmer) corev1.Service {
+	_, daemon, _ := getServiceConfigs(farmer.Spec.Services)
+	objMeta := metav1.ObjectMeta{
+		Name:            fmt.Sprintf("%s-farmer-daemon", farmer.Name),
+		Namespace:       farmer.Namespace,
+		Labels:          r.getCommonLabels(ctx, farmer, farmer.Spec.AdditionalMetadata.Labels),
+		Annotations:     farmer.Spec.AdditionalMetadata.Annotations,
+		OwnerReferences: r.getOwnerReference(ctx, farmer),
+	}
+	srv := getPortService(objMeta, r.getCommonLabels(ctx, farmer, farmer.Spec.AdditionalMetadata.Labels), daemon, corev1.ServiceTypeClusterIP, corev1.ServicePort{
+		Port:       daemonPort,
+		TargetPort: intstr.FromString("daemon"),
+		Protocol:   "TCP",
+		Name:       "daemon",
+	})
+	srv.Spec.IPFamilies, srv.Spec.IPFamilyPolicy = getServiceIPFamilies(farmer.Spec.Services)
+	return srv
+}
+
+// assembleRPCService assembles the RPC port Service resource for a ChiaFarmer CR
+func (r *ChiaFarmerReconciler) assembleRPCService(ctx context.Context, farmer k8schianetv1.ChiaFarmer) corev1.Service {
+	_, _, rpc := getServiceConfigs(farmer.Spec.Services)
+	objMeta := metav1.ObjectMeta{
+		Name:            fmt.Sprintf("%s-farmer-rpc", farmer.Name),
+		Namespace:       farmer.Namespace,
+		Labels:          r.getCommonLabels(ctx, farmer, farmer.Spec.AdditionalMetadata.Labels),
+		Annotations:     farmer.Spec.AdditionalMetadata.Annotations,
+		OwnerReferences: r.getOwnerReference(ctx, farmer),
+	}
+	srv := getPortService(objMeta, r.getCommonLabels(ctx, farmer, farmer.Spec.AdditionalMetadata.Labels), rpc, corev1.ServiceTypeClusterIP, corev1.ServicePort{
+		Port:       farmerRPCPort,
+		TargetPort: intstr.FromString("rpc"),
+		Protocol:   "TCP",
+		Name:       "rpc",
+	})
+	srv.Spec.IPFamilies, srv.Spec.IPFamilyPolicy = getServiceIPFamilies(farmer.Spec.Services)
+	return srv
+}
+
+// assembleChiaExporterService assembles the chia-exporter Service resource for a ChiaFarmer CR
+func (r *ChiaFarmerReconciler) assembleChiaExporterService(ctx context.Context, farmer k8schianetv1.ChiaFarmer) corev1.Service {
+	ipFamilies, ipFamilyPolicy := getServiceIPFamilies(farmer.Spec.Services)
 	return corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:            fmt.Sprintf("%s-farmer", farmer.Name),
+			Name:            fmt.Sprintf("%s-farmer-metrics", farmer.Name),
 			Namespace:       farmer.Namespace,
-			Labels:          r.getCommonLabels(ctx, farmer, farmer.Spec.AdditionalMetadata.Labels),
+			Labels:          r.getCommonLabels(ctx, farmer, farmer.Spec.AdditionalMetadata.Labels, farmer.Spec.ChiaExporterConfig.ServiceLabels, chiaExporterServiceLabels),
 			Annotations:     farmer.Spec.AdditionalMetadata.Annotations,
 			OwnerReferences: r.getOwnerReference(ctx, farmer),
 		},
 		Spec: corev1.ServiceSpec{
-			Type: corev1.ServiceType(farmer.Spec.ServiceType),
+			Type:           corev1.ServiceType("ClusterIP"),
+			IPFamilies:     ipFamilies,
+			IPFamilyPolicy: ipFamilyPolicy,
 			Ports: []corev1.ServicePort{
 				{
-					Port:       daemonPort,
-					TargetPort: intstr.FromString("daemon"),
-					Protocol:   "TCP",
-					Name:       "daemon",
-				},
-				{
-					Port:       farmerPort,
-					TargetPort: intstr.FromString("peers"),
+					Port:       getChiaExporterPort(farmer.Spec.ChiaExporterConfig),
+					TargetPort: intstr.FromString("metrics"),
 					Protocol:   "TCP",
-					Name:       "peers",
+					Name:       "metrics",
 				},
+			},
+			Selector: r.getCommonLabels(ctx, farmer, farmer.Spec.AdditionalMetadata.Labels),
+		},
+	}
+}
+
+// assembleNetworkPolicy assembles the NetworkPolicy resource for a ChiaFarmer CR. The peer port is open to harvesters in the same namespace and any sources in peerFrom
+func (r *ChiaFarmerReconciler) assembleNetworkPolicy(ctx context.Context, farmer k8schianetv1.ChiaFarmer) networkingv1.NetworkPolicy {
+	harvesters := networkingv1.NetworkPolicyPeer{
+		PodSelector: &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{
 				{
-					Port:       farmerRPCPort,
-					TargetPort: intstr.FromString("rpc"),
-					Protocol:   "TCP",
-					Name:       "rpc",
+					Key:      chiaHarvesterOwnerLabel,
+					Operator: metav1.LabelSelectorOpExists,
 				},
 			},
-			Selector: r.getCommonLabels(ctx, farmer, farmer.Spec.AdditionalMetadata.Labels),
 		},
 	}
+	var peerFrom []networkingv1.NetworkPolicyPeer
+	if farmer.Spec.NetworkPolicy != nil {
+		peerFrom = farmer.Spec.NetworkPolicy.PeerFrom
+	}
+	peerRules := []networkingv1.NetworkPolicyIngressRule{
+		getNetworkPolicyIngressRule(farmerPort, append([]networkingv1.NetworkPolicyPeer{harvesters}, peerFrom...)),
+	}
+
+	objMeta := metav1.ObjectMeta{
+		Name:            fmt.Sprintf("%s-farmer", farmer.Name),
+		Namespace:       farmer.Namespace,
+		Labels:          r.getCommonLabels(ctx, farmer, farmer.Spec.AdditionalMetadata.Labels),
+		Annotations:     farmer.Spec.AdditionalMetadata.Annotations,
+		OwnerReferences: r.getOwnerReference(ctx, farmer),
+	}
+	return getNetworkPolicy(objMeta, r.getCommonLabels(ctx, farmer, farmer.Spec.AdditionalMetadata.Labels), farmer.Spec.NetworkPolicy, peerRules, farmerRPCPort, farmer.Spec.ChiaExporterConfig)
 }
 
-// assembleChiaExporterService assembles the chia-exporter Service resource for a ChiaFarmer CR
-func (r *ChiaFarmerReconciler) assembleChiaExporterService(ctx context.Context, farmer k8schianetv1.ChiaFarmer) corev1.Service {
-	return corev1.Service{
+// getPortExposures gives the Gateway API routes exposing the peer and RPC ports of a ChiaFarmer
+func (r *ChiaFarmerReconciler) getPortExposures(ctx context.Context, farmer k8schianetv1.ChiaFarmer) []portExposure {
+	objMeta := metav1.ObjectMeta{
+		Name:            fmt.Sprintf("%s-farmer", farmer.Name),
+		Namespace:       farmer.Namespace,
+		Labels:          r.getCommonLabels(ctx, farmer, farmer.Spec.AdditionalMetadata.Labels),
+		Annotations:     farmer.Spec.AdditionalMetadata.Annotations,
+		OwnerReferences: r.getOwnerReference(ctx, farmer),
+	}
+	return getPortExposures(objMeta, farmer.Spec.Expose, farmerPort, farmerRPCPort)
+}
+
+// assembleChiaExporterServiceMonitor assembles the chia-exporter ServiceMonitor resource for a ChiaFarmer CR
+func (r *ChiaFarmerReconciler) assembleChiaExporterServiceMonitor(ctx context.Context, farmer k8schianetv1.ChiaFarmer) *unstructured.Unstructured {
+	objMeta := metav1.ObjectMeta{
+		Name:            fmt.Sprintf("%s-farmer-metrics", farmer.Name),
+		Namespace:       farmer.Namespace,
+		Labels:          r.getCommonLabels(ctx, farmer, farmer.Spec.AdditionalMetadata.Labels),
+		Annotations:     farmer.Spec.AdditionalMetadata.Annotations,
+		OwnerReferences: r.getOwnerReference(ctx, farmer),
+	}
+	return getChiaExporterServiceMonitor(ctx, objMeta, farmer.Spec.ChiaExporterConfig, r.getCommonLabels(ctx, farmer, chiaExporterServiceLabels))
+}
+
+// assembleChiaExporterPrometheusRule assembles the chia-exporter PrometheusRule resource for a ChiaFarmer CR
+func (r *ChiaFarmerReconciler) assembleChiaExporterPrometheusRule(ctx context.Context, farmer k8schianetv1.ChiaFarmer) *unstructured.Unstructured {
+	objMeta := metav1.ObjectMeta{
+		Name:            fmt.Sprintf("%s-farmer", farmer.Name),
+		Namespace:       farmer.Namespace,
+		Labels:          r.getCommonLabels(ctx, farmer, farmer.Spec.AdditionalMetadata.Labels),
+		Annotations:     farmer.Spec.AdditionalMetadata.Annotations,
+		OwnerReferences: r.getOwnerReference(ctx, farmer),
+	}
+	service := fmt.Sprintf("%s-farmer-metrics", farmer.Name)
+	selector := getChiaExporterAlertSelector(farmer.Namespace, service)
+	alerts := []chiaExporterAlert{
+		getChiaExporterDownAlert(farmer.Name, farmer.Namespace, service),
+		{
+			Name:        "ChiaFarmerNoProofs",
+			Expr:        fmt.Sprintf("increase(chia_farmer_proofs_found%s[24h]) == 0", selector),
+			For:         "1h",
+			Summary:     fmt.Sprintf("Chia farmer %s/%s is not finding proofs", farmer.Namespace, farmer.Name),
+			Description: fmt.Sprintf("The farmer %s/%s has not found a proof in the last 24 hours.", farmer.Namespace, farmer.Name),
+		},
+	}
+	return getChiaExporterPrometheusRule(ctx, objMeta, farmer.Spec.ChiaExporterConfig, objMeta.Name, alerts)
+}
+
+// assembleConfigOverridesConfigMap assembles the config.yaml overrides ConfigMap resource for a ChiaFarmer CR
+func (r *ChiaFarmerReconciler) assembleConfigOverridesConfigMap(ctx context.Context, farmer k8schianetv1.ChiaFarmer, data map[string]string) corev1.ConfigMap {
+	return corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:            fmt.Sprintf("%s-farmer-metrics", farmer.Name),
+			Name:            fmt.Sprintf("%s-farmer-config-overrides", farmer.Name),
 			Namespace:       farmer.Namespace,
-			Labels:          r.getCommonLabels(ctx, farmer, farmer.Spec.AdditionalMetadata.Labels, farmer.Spec.ChiaExporterConfig.ServiceLabels),
+			Labels:          r.getCommonLabels(ctx, farmer, farmer.Spec.AdditionalMetadata.Labels),
 			Annotations:     farmer.Spec.AdditionalMetadata.Annotations,
 			OwnerReferences: r.getOwnerReference(ctx, farmer),
 		},
-		Spec: corev1.ServiceSpec{
-			Type: corev1.ServiceType("ClusterIP"),
-			Ports: []corev1.ServicePort{
-				{
-					Port:       chiaExporterPort,
-					TargetPort: intstr.FromString("metrics"),
-					Protocol:   "TCP",
-					Name:       "metrics",
-				},
-			},
-			Selector: r.getCommonLabels(ctx, farmer, farmer.Spec.AdditionalMetadata.Labels),
-		},
+		Data: data,
 	}
 }
 
 // assembleDeployment assembles the farmer Deployment resource for a ChiaFarmer CR
-func (r *ChiaFarmerReconciler) assembleDeployment(ctx context.Context, farmer k8schianetv1.ChiaFarmer) appsv1.Deployment {
+func (r *ChiaFarmerReconciler) assembleDeployment(ctx context.Context, farmer k8schianetv1.ChiaFarmer, overrides map[string]string, overridesRef string) appsv1.Deployment {
 	var chiaSecContext *corev1.SecurityContext
 	if farmer.Spec.ChiaConfig.SecurityContext != nil {
 		chiaSecContext = farmer.Spec.ChiaConfig.SecurityContext
@@ -202,10 +467,9 @@ func (r *ChiaFarmerReconciler) assembleDeployment(ctx context.Context, farmer k8
 		imagePullPolicy = *farmer.Spec.ImagePullPolicy
 	}
 
-	var chiaExporterImage = farmer.Spec.ChiaExporterConfig.Image
-	if chiaExporterImage == "" {
-		chiaExporterImage = defaultChiaExporterImage
-	}
+	podAnnotations := getConfigChecksumPodAnnotations(getConfigOverridesPodAnnotations(farmer.Spec.AdditionalMetadata.Annotations, overrides, overridesRef), overrides, chiaFarmerPoolListKey, poolListChecksumAnnotation)
+	podAnnotations = getConfigChecksumPodAnnotations(podAnnotations, overrides, chiaFarmerRewardAddressesKey, rewardAddressesChecksumAnnotation)
+	podAnnotations = getConfigChecksumPodAnnotations(podAnnotations, overrides, chiaIPFamiliesKey, ipFamiliesChecksumAnnotation)
 
 	var deploy appsv1.Deployment = appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -222,7 +486,7 @@ func (r *ChiaFarmerReconciler) assembleDeployment(ctx context.Context, farmer k8
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels:      r.getCommonLabels(ctx, farmer, farmer.Spec.AdditionalMetadata.Labels),
-					Annotations: farmer.Spec.AdditionalMetadata.Annotations,
+					Annotations: podAnnotations,
 				},
 				Spec: corev1.PodSpec{
 					// TODO add: imagePullSecret, serviceAccountName config
@@ -277,8 +541,23 @@ func (r *ChiaFarmerReconciler) assembleDeployment(ctx context.Context, farmer k8
 		},
 	}
 
-	exporterContainer := getChiaExporterContainer(ctx, chiaExporterImage, chiaSecContext, imagePullPolicy, chiaResources)
-	deploy.Spec.Template.Spec.Containers = append(deploy.Spec.Template.Spec.Containers, exporterContainer)
+	deploy.Spec.Template.Spec.Containers[0].VolumeMounts = append(deploy.Spec.Template.Spec.Containers[0].VolumeMounts, getAdditionalKeyVolumeMounts(farmer.Spec.ChiaConfig.SecretKeys)...)
+
+	verifyKeysContainer, ok := getChiaKeysVerifyInitContainer(ctx, farmer.Spec.ChiaConfig.Image, farmer.Spec.ChiaConfig.SecretKeySpec, farmer.Spec.ChiaConfig.SecretKeys, chiaSecContext, imagePullPolicy)
+	if ok {
+		deploy.Spec.Template.Spec.InitContainers = append(deploy.Spec.Template.Spec.InitContainers, verifyKeysContainer)
+	}
+
+	if chiaExporterEnabled(farmer.Spec.ChiaExporterConfig) {
+		exporterContainer := getChiaExporterContainer(ctx, farmer.Spec.ChiaExporterConfig, chiaSecContext, imagePullPolicy)
+		deploy.Spec.Template.Spec.Containers = append(deploy.Spec.Template.Spec.Containers, exporterContainer)
+	}
+
+	overridesVolume, ok := getChiaConfigOverridesVolume(ctx, farmer.Spec.ChiaConfig.ConfigOverrides, fmt.Sprintf("%s-farmer-config-overrides", farmer.Name), overrides)
+	if ok {
+		deploy.Spec.Template.Spec.Volumes = append(deploy.Spec.Template.Spec.Volumes, overridesVolume)
+		deploy.Spec.Template.Spec.InitContainers = append(deploy.Spec.Template.Spec.InitContainers, getChiaConfigOverridesInitContainer(ctx, farmer.Spec.ChiaConfig.Image, chiaSecContext, imagePullPolicy))
+	}
 
 	if farmer.Spec.PodSecurityContext != nil {
 		deploy.Spec.Template.Spec.SecurityContext = farmer.Spec.PodSecurityContext
@@ -289,6 +568,25 @@ func (r *ChiaFarmerReconciler) assembleDeployment(ctx context.Context, farmer k8
 	return deploy
 }
 
+// assembleChiaRootPersistentVolumeClaim assembles the operator-created CHIA_ROOT PersistentVolumeClaim resource for a ChiaFarmer CR
+func (r *ChiaFarmerReconciler) assembleChiaRootPersistentVolumeClaim(ctx context.Context, farmer k8schianetv1.ChiaFarmer) (corev1.PersistentVolumeClaim, error) {
+	spec, err := getPersistentVolumeClaimSpec(farmer.Spec.Storage.ChiaRoot.PersistentVolumeClaim)
+	if err != nil {
+		return corev1.PersistentVolumeClaim{}, err
+	}
+
+	return corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            fmt.Sprintf("%s-farmer-chiaroot", farmer.Name),
+			Namespace:       farmer.Namespace,
+			Labels:          r.getCommonLabels(ctx, farmer, farmer.Spec.AdditionalMetadata.Labels),
+			Annotations:     farmer.Spec.AdditionalMetadata.Annotations,
+			OwnerReferences: r.getOwnerReference(ctx, farmer),
+		},
+		Spec: spec,
+	}, nil
+}
+
 // getChiaVolumes retrieves the requisite volumes from the Chia config struct
 func (r *ChiaFarmerReconciler) getChiaVolumes(ctx context.Context, farmer k8schianetv1.ChiaFarmer) []corev1.Volume {
 	var v []corev1.Volume
@@ -313,8 +611,11 @@ func (r *ChiaFarmerReconciler) getChiaVolumes(ctx context.Context, farmer k8schi
 		},
 	})
 
-	// CHIA_ROOT volume -- PVC is respected first if both it and hostpath are specified, falls back to hostPath if specified
-	// If both are empty, fall back to emptyDir so chia-exporter can mount CHIA_ROOT
+	// additional mnemonic key volumes
+	v = append(v, getAdditionalKeyVolumes(farmer.Spec.ChiaConfig.SecretKeys)...)
+
+	// CHIA_ROOT volume -- PVC is respected first, then hostPath, then NFS, CSI, ephemeral and iSCSI volumes
+	// If none are specified, fall back to emptyDir so chia-exporter can mount CHIA_ROOT
 	var chiaRootAdded bool = false
 	if farmer.Spec.Storage != nil && farmer.Spec.Storage.ChiaRoot != nil {
 		if farmer.Spec.Storage.ChiaRoot.PersistentVolumeClaim != nil {
@@ -322,19 +623,21 @@ func (r *ChiaFarmerReconciler) getChiaVolumes(ctx context.Context, farmer k8schi
 				Name: "chiaroot",
 				VolumeSource: corev1.VolumeSource{
 					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
-						ClaimName: farmer.Spec.Storage.ChiaRoot.HostPathVolume.Path,
+						ClaimName: getChiaRootClaimName(farmer.Spec.Storage.ChiaRoot.PersistentVolumeClaim, fmt.Sprintf("%s-farmer-chiaroot", farmer.Name)),
 					},
 				},
 			})
 			chiaRootAdded = true
 		} else if farmer.Spec.Storage.ChiaRoot.HostPathVolume != nil {
 			v = append(v, corev1.Volume{
-				Name: "chiaroot",
-				VolumeSource: corev1.VolumeSource{
-					HostPath: &corev1.HostPathVolumeSource{
-						Path: farmer.Spec.Storage.ChiaRoot.HostPathVolume.Path,
-					},
-				},
+				Name:         "chiaroot",
+				VolumeSource: getHostPathVolumeSource(farmer.Spec.Storage.ChiaRoot.HostPathVolume),
+			})
+			chiaRootAdded = true
+		} else if source, ok := getChiaRootVolumeSource(farmer.Spec.Storage.ChiaRoot); ok {
+			v = append(v, corev1.Volume{
+				Name:         "chiaroot",
+				VolumeSource: source,
 			})
 			chiaRootAdded = true
 		}
@@ -400,7 +703,7 @@ func (r *ChiaFarmerReconciler) getChiaEnv(ctx context.Context, farmer k8schianet
 	// keys env var
 	env = append(env, corev1.EnvVar{
 		Name:  "keys",
-		Value: fmt.Sprintf("/key/%s", farmer.Spec.ChiaConfig.SecretKeySpec.Key),
+		Value: getChiaKeysEnv(farmer.Spec.ChiaConfig.SecretKeySpec, farmer.Spec.ChiaConfig.SecretKeys),
 	})
 
 	// node peer env var
@@ -438,3 +741,238 @@ func (r *ChiaFarmerReconciler) getOwnerReference(ctx context.Context, farmer k8s
 		},
 	}
 }
+
+// updateFarmStatus gathers the state of the farm through the farmer's RPC server and sets it in the ChiaFarmer's status
+func (r *ChiaFarmerReconciler) updateFarmStatus(ctx context.Context, farmer *k8schianetv1.ChiaFarmer) {
+	log := log.FromContext(ctx)
+	now := metav1.Now()
+	farmer.Status.LastStatusUpdateTime = &now
+
+	pods, err := listRunningPods(ctx, r.Client, farmer.Namespace, r.getCommonLabels(ctx, *farmer))
+	if err != nil {
+		log.Info(fmt.Sprintf("ChiaFarmerReconciler ChiaFarmer=%s/%s unable to list farmer pods: %v", farmer.Namespace, farmer.Name, err))
+		return
+	}
+	if len(pods) == 0 {
+		return
+	}
+
+	tlsConfig, err := getChiaRPCTLSConfig(ctx, r.Client, farmer.Namespace, farmer.Spec.ChiaConfig.CASecretName)
+	if err != nil {
+		log.Info(fmt.Sprintf("ChiaFarmerReconciler ChiaFarmer=%s/%s unable to create RPC client from CA Secret: %v", farmer.Namespace, farmer.Name, err))
+		return
+	}
+
+	host, port := getChiaRPCHostPort(pods[0].Status.PodIP, farmerRPCPort)
+	rpc := chiarpc.NewFarmerClient(host, port, tlsConfig)
+
+	harvesters, err := rpc.GetHarvesters(ctx)
+	if err != nil {
+		log.Info(fmt.Sprintf("ChiaFarmerReconciler unable to get harvesters from pod %s/%s: %v", pods[0].Namespace, pods[0].Name, err))
+	} else {
+		setHarvestersStatus(&farmer.Status, harvesters)
+	}
+
+	pools, err := rpc.GetPoolState(ctx)
+	if err != nil {
+		log.Info(fmt.Sprintf("ChiaFarmerReconciler unable to get pool state from pod %s/%s: %v", pods[0].Namespace, pods[0].Name, err))
+	} else {
+		farmer.Status.Pools = getPoolsStatus(pools)
+	}
+
+	signagePoints, err := rpc.GetSignagePoints(ctx)
+	if err != nil {
+		log.Info(fmt.Sprintf("ChiaFarmerReconciler unable to get signage points from pod %s/%s: %v", pods[0].Namespace, pods[0].Name, err))
+	} else {
+		setSignagePointsStatus(&farmer.Status, signagePoints, now)
+	}
+
+	targets, err := rpc.GetRewardTargets(ctx)
+	if err != nil {
+		log.Info(fmt.Sprintf("ChiaFarmerReconciler unable to get reward targets from pod %s/%s: %v", pods[0].Namespace, pods[0].Name, err))
+	} else {
+		farmer.Status.RewardTargets = &k8schianetv1.ChiaFarmerRewardTargetsStatus{
+			FarmerRewardAddress: targets.FarmerTarget,
+			PoolRewardAddress:   targets.PoolTarget,
+		}
+	}
+}
+
+// setHarvestersStatus sets the connected harvesters and their plot totals in a ChiaFarmer's status
+func setHarvestersStatus(status *k8schianetv1.ChiaFarmerStatus, harvesters []chiarpc.HarvesterSummary) {
+	status.Harvesters = nil
+	status.TotalPlots = 0
+	for _, harvester := range harvesters {
+		h := k8schianetv1.ChiaFarmerHarvesterStatus{
+			NodeID:        harvester.Connection.NodeID,
+			Host:          harvester.Connection.Host,
+			Plots:         int32(harvester.Plots),
+			EffectiveSize: resource.NewQuantity(int64(harvester.TotalEffectivePlotSize), resource.BinarySI),
+			Syncing:       harvester.Syncing != nil && harvester.Syncing.Initial,
+		}
+		status.Harvesters = append(status.Harvesters, h)
+		status.TotalPlots += h.Plots
+	}
+	status.ConnectedHarvesters = int32(len(status.Harvesters))
+}
+
+// getPoolsStatus converts a farmer's pool state to ChiaFarmer pool statuses
+func getPoolsStatus(pools []chiarpc.PoolState) []k8schianetv1.ChiaFarmerPoolStatus {
+	var statuses []k8schianetv1.ChiaFarmerPoolStatus
+	for _, pool := range pools {
+		status := k8schianetv1.ChiaFarmerPoolStatus{
+			LauncherID:                   pool.PoolConfig.LauncherID,
+			PoolURL:                      pool.PoolConfig.PoolURL,
+			PlotCount:                    int32(pool.PlotCount),
+			CurrentPoints:                int64(pool.CurrentPoints),
+			PointsFoundSinceStart:        int64(pool.PointsFoundSinceStart),
+			PointsAcknowledgedSinceStart: int64(pool.PointsAcknowledgedSinceStart),
+			PointsFound24h:               sumPoints(pool.PointsFound24h),
+			PointsAcknowledged24h:        sumPoints(pool.PointsAcknowledged24h),
+			PoolErrors24h:                int32(len(pool.PoolErrors24h)),
+		}
+		if pool.CurrentDifficulty != nil {
+			difficulty := int64(*pool.CurrentDifficulty)
+			status.CurrentDifficulty = &difficulty
+		}
+		if len(pool.PoolErrors24h) != 0 {
+			status.LastPoolError = pool.PoolErrors24h[len(pool.PoolErrors24h)-1].ErrorMessage
+		}
+		switch {
+		case pool.PoolConfig.PoolURL == "":
+			status.State = k8schianetv1.ChiaFarmerPoolSelfPooling
+		case pool.AuthenticationTokenTimeout != nil:
+			status.State = k8schianetv1.ChiaFarmerPoolConnected
+		default:
+			status.State = k8schianetv1.ChiaFarmerPoolDisconnected
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// sumPoints totals a pool's 24 hour points history, a list of [timestamp, points] pairs
+func sumPoints(history [][]float64) int64 {
+	var total int64
+	for _, entry := range history {
+		if len(entry) == 2 {
+			total += int64(entry[1])
+		}
+	}
+	return total
+}
+
+// setSignagePointsStatus sets the latest signage point and the number of recent proofs in a ChiaFarmer's status.
+// The farmer doesn't report when it received a signage point, so the observed time only moves forward when a new one is seen.
+func setSignagePointsStatus(status *k8schianetv1.ChiaFarmerStatus, signagePoints []chiarpc.SignagePointWithProofs, now metav1.Time) {
+	var proofs int32
+	var latest *chiarpc.SignagePoint
+	for i, sp := range signagePoints {
+		proofs += int32(len(sp.Proofs))
+		if latest == nil || sp.SignagePoint.PeakHeight > latest.PeakHeight ||
+			(sp.SignagePoint.PeakHeight == latest.PeakHeight && sp.SignagePoint.SignagePointIndex > latest.SignagePointIndex) {
+			latest = &signagePoints[i].SignagePoint
+		}
+	}
+	status.RecentProofs = proofs
+
+	if latest == nil {
+		return
+	}
+	if status.LastSignagePoint != nil && status.LastSignagePoint.ChallengeChainSP == latest.ChallengeChainSP {
+		return
+	}
+	status.LastSignagePoint = &k8schianetv1.ChiaFarmerSignagePointStatus{
+		ChallengeChainSP: latest.ChallengeChainSP,
+		Index:            int32(latest.SignagePointIndex),
+		PeakHeight:       int64(latest.PeakHeight),
+		ObservedTime:     now,
+	}
+}
+
+// getPoolListOverride renders a config.yaml override document setting the farmer's pool.pool_list to its configured plotNFTs
+func getPoolListOverride(pools []k8schianetv1.ChiaFarmerPoolSpec) (string, error) {
+	var poolList []map[string]string
+	for _, pool := range pools {
+		poolList = append(poolList, map[string]string{
+			"launcher_id":              withHexPrefix(pool.LauncherID),
+			"pool_url":                 pool.PoolURL,
+			"payout_instructions":      pool.PayoutInstructions,
+			"target_puzzle_hash":       withHexPrefix(pool.TargetPuzzleHash),
+			"p2_singleton_puzzle_hash": withHexPrefix(pool.P2SingletonPuzzleHash),
+			"owner_public_key":         withHexPrefix(pool.OwnerPublicKey),
+		})
+	}
+
+	out, err := yaml.Marshal(map[string]interface{}{
+		"pool": map[string]interface{}{
+			"pool_list": poolList,
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// getRewardAddressesOverride renders a config.yaml override document setting the farmer and pool xch_target_address, after checking each is a valid address for the farmer's network
+func getRewardAddressesOverride(config k8schianetv1.ChiaFarmerConfigSpec) (string, error) {
+	prefix := chiarpc.MainnetAddressPrefix
+	if config.Testnet != nil && *config.Testnet {
+		prefix = chiarpc.TestnetAddressPrefix
+	}
+
+	override := make(map[string]interface{})
+	for section, address := range map[string]*string{
+		"farmer": config.FarmerRewardAddress,
+		"pool":   config.PoolRewardAddress,
+	} {
+		if address == nil {
+			continue
+		}
+		addressPrefix, _, err := chiarpc.DecodeAddress(*address)
+		if err != nil {
+			return "", fmt.Errorf("invalid %s reward address: %v", section, err)
+		}
+		if addressPrefix != prefix {
+			return "", fmt.Errorf("%s reward address %s is not a %s address", section, *address, prefix)
+		}
+		override[section] = map[string]string{
+			"xch_target_address": *address,
+		}
+	}
+
+	out, err := yaml.Marshal(override)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// getRewardAddressesValidCondition returns the RewardAddressesValid condition for the error from rendering a farmer's reward addresses
+func getRewardAddressesValidCondition(generation int64, err error) metav1.Condition {
+	if err != nil {
+		return metav1.Condition{
+			Type:               k8schianetv1.ChiaFarmerConditionRewardAddressesValid,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: generation,
+			Reason:             "InvalidRewardAddress",
+			Message:            err.Error(),
+		}
+	}
+	return metav1.Condition{
+		Type:               k8schianetv1.ChiaFarmerConditionRewardAddressesValid,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             "Valid",
+		Message:            "reward addresses are valid for the farmer's network",
+	}
+}
+
+// withHexPrefix adds the 0x prefix chia uses for hex values in config.yaml, if it's missing
+func withHexPrefix(hex string) string {
+	if strings.HasPrefix(hex, "0x") {
+		return hex
+	}
+	return "0x" + hex
+}
@@ -6,11 +6,15 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	apiv1 "github.com/chia-network/chia-operator/api/v1"
+	"github.com/chia-network/chia-operator/internal/chiarpc"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -84,4 +88,204 @@ var _ = Describe("ChiaFarmer controller", func() {
 			Expect(*createdChiaFarmer.Spec.ChiaConfig.Timezone).Should(Equal(timezone))
 		})
 	})
+
+	Context("When summarizing ChiaFarmer signage points", func() {
+		It("Should only move the observed time forward for a new signage point", func() {
+			status := &apiv1.ChiaFarmerStatus{}
+			first := metav1.NewTime(time.Unix(1000, 0))
+			second := metav1.NewTime(time.Unix(2000, 0))
+			signagePoints := []chiarpc.SignagePointWithProofs{
+				{SignagePoint: chiarpc.SignagePoint{ChallengeChainSP: "0xa", SignagePointIndex: 5, PeakHeight: 10}},
+				{SignagePoint: chiarpc.SignagePoint{ChallengeChainSP: "0xb", SignagePointIndex: 6, PeakHeight: 10}, Proofs: []json.RawMessage{[]byte("{}")}},
+			}
+
+			setSignagePointsStatus(status, signagePoints, first)
+			Expect(status.RecentProofs).Should(Equal(int32(1)))
+			Expect(status.LastSignagePoint.ChallengeChainSP).Should(Equal("0xb"))
+			Expect(status.LastSignagePoint.ObservedTime).Should(Equal(first))
+
+			setSignagePointsStatus(status, signagePoints, second)
+			Expect(status.LastSignagePoint.ObservedTime).Should(Equal(first))
+
+			signagePoints = append(signagePoints, chiarpc.SignagePointWithProofs{SignagePoint: chiarpc.SignagePoint{ChallengeChainSP: "0xc", SignagePointIndex: 0, PeakHeight: 11}})
+			setSignagePointsStatus(status, signagePoints, second)
+			Expect(status.LastSignagePoint.ChallengeChainSP).Should(Equal("0xc"))
+			Expect(status.LastSignagePoint.ObservedTime).Should(Equal(second))
+		})
+	})
+
+	Context("When configuring ChiaFarmer pools", func() {
+		It("Should render pool_list and report each pool's connection state", func() {
+			hash := "4bc6435b409bcbabe53870dae0f03755f6aabb4594c5915ec983acf12a5d1fba"
+			override, err := getPoolListOverride([]apiv1.ChiaFarmerPoolSpec{
+				{
+					LauncherID:            hash,
+					PoolURL:               "https://pool.example.com",
+					PayoutInstructions:    hash,
+					TargetPuzzleHash:      "0x" + hash,
+					P2SingletonPuzzleHash: hash,
+					OwnerPublicKey:        "a5b6c7d8e9f0a5b6c7d8e9f0a5b6c7d8e9f0a5b6c7d8e9f0a5b6c7d8e9f0a5b6c7d8e9f0a5b6c7d8e9f0a5b6c7d8e9f0",
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(override).Should(ContainSubstring("pool:\n  pool_list:\n  - launcher_id: 0x" + hash))
+			Expect(override).Should(ContainSubstring("target_puzzle_hash: 0x" + hash))
+			Expect(override).Should(ContainSubstring("payout_instructions: " + hash))
+
+			timeout := uint8(5)
+			pools := getPoolsStatus([]chiarpc.PoolState{
+				{
+					PoolConfig:                 chiarpc.PoolConfig{LauncherID: "0x" + hash, PoolURL: "https://pool.example.com"},
+					PointsFound24h:             [][]float64{{1700000000, 10}, {1700000100, 20}},
+					PoolErrors24h:              []chiarpc.PoolError{{ErrorCode: 2, ErrorMessage: "Too late"}},
+					AuthenticationTokenTimeout: &timeout,
+				},
+				{
+					PoolConfig: chiarpc.PoolConfig{LauncherID: "0x" + hash},
+				},
+			})
+			Expect(pools[0].State).Should(Equal(apiv1.ChiaFarmerPoolConnected))
+			Expect(pools[0].PointsFound24h).Should(Equal(int64(30)))
+			Expect(pools[0].LastPoolError).Should(Equal("Too late"))
+			Expect(pools[1].State).Should(Equal(apiv1.ChiaFarmerPoolSelfPooling))
+		})
+	})
+
+	Context("When configuring ChiaFarmer reward addresses", func() {
+		It("Should render xch_target_address and reject addresses for the wrong network", func() {
+			mainnetAddress := "xch1qqqsyqcyq5rqwzqfpg9scrgwpugpzysnzs23v9ccrydpk8qarc0srg6dkm"
+			testnetAddress := "txch1qqqsyqcyq5rqwzqfpg9scrgwpugpzysnzs23v9ccrydpk8qarc0sw0amhg"
+			override, err := getRewardAddressesOverride(apiv1.ChiaFarmerConfigSpec{
+				FarmerRewardAddress: &mainnetAddress,
+				PoolRewardAddress:   &mainnetAddress,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(override).Should(Equal("farmer:\n  xch_target_address: " + mainnetAddress + "\npool:\n  xch_target_address: " + mainnetAddress + "\n"))
+
+			isTestnet := true
+			_, err = getRewardAddressesOverride(apiv1.ChiaFarmerConfigSpec{
+				Testnet:             &isTestnet,
+				FarmerRewardAddress: &mainnetAddress,
+			})
+			Expect(err).To(HaveOccurred())
+
+			override, err = getRewardAddressesOverride(apiv1.ChiaFarmerConfigSpec{
+				Testnet:           &isTestnet,
+				PoolRewardAddress: &testnetAddress,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(override).Should(Equal("pool:\n  xch_target_address: " + testnetAddress + "\n"))
+
+			corrupted := mainnetAddress[:len(mainnetAddress)-1] + "n"
+			_, err = getRewardAddressesOverride(apiv1.ChiaFarmerConfigSpec{FarmerRewardAddress: &corrupted})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should report an invalid reward address in the RewardAddressesValid condition", func() {
+			mainnetAddress := "xch1qqqsyqcyq5rqwzqfpg9scrgwpugpzysnzs23v9ccrydpk8qarc0srg6dkm"
+			isTestnet := true
+			_, err := getRewardAddressesOverride(apiv1.ChiaFarmerConfigSpec{
+				Testnet:             &isTestnet,
+				FarmerRewardAddress: &mainnetAddress,
+			})
+			condition := getRewardAddressesValidCondition(3, err)
+			Expect(condition.Status).Should(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).Should(Equal("InvalidRewardAddress"))
+			Expect(condition.Message).Should(ContainSubstring("not a txch address"))
+			Expect(condition.ObservedGeneration).Should(Equal(int64(3)))
+
+			_, err = getRewardAddressesOverride(apiv1.ChiaFarmerConfigSpec{FarmerRewardAddress: &mainnetAddress})
+			Expect(getRewardAddressesValidCondition(3, err).Status).Should(Equal(metav1.ConditionTrue))
+		})
+	})
+
+	Context("When enabling a ChiaFarmer NetworkPolicy", func() {
+		It("Should only allow harvesters to the peer port and the operator to the RPC port", func() {
+			ctx := context.Background()
+			exporterEnabled := false
+			farmer := apiv1.ChiaFarmer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      chiaFarmerName,
+					Namespace: chiaFarmerNamespace,
+				},
+				Spec: apiv1.ChiaFarmerSpec{
+					ChiaExporterConfig: apiv1.ChiaExporterConfigSpec{
+						Enabled: &exporterEnabled,
+					},
+					NetworkPolicy: &apiv1.NetworkPolicyConfig{
+						Enabled: true,
+						PeerFrom: []networkingv1.NetworkPolicyPeer{
+							{IPBlock: &networkingv1.IPBlock{CIDR: "192.168.1.0/24"}},
+						},
+					},
+				},
+			}
+
+			r := &ChiaFarmerReconciler{}
+			policy := r.assembleNetworkPolicy(ctx, farmer)
+			Expect(policy.Name).Should(Equal("test-chiafarmer-farmer"))
+			Expect(policy.Spec.PodSelector.MatchLabels).Should(HaveKeyWithValue("chiafarmer-owner", chiaFarmerName))
+			Expect(policy.Spec.Ingress).Should(HaveLen(2))
+
+			peers := policy.Spec.Ingress[0]
+			Expect(peers.Ports[0].Port.IntValue()).Should(Equal(farmerPort))
+			Expect(peers.From).Should(HaveLen(2))
+			Expect(peers.From[0].PodSelector.MatchExpressions[0].Key).Should(Equal(chiaHarvesterOwnerLabel))
+			Expect(peers.From[1].IPBlock.CIDR).Should(Equal("192.168.1.0/24"))
+
+			rpc := policy.Spec.Ingress[1]
+			Expect(rpc.Ports[0].Port.IntValue()).Should(Equal(farmerRPCPort))
+			Expect(rpc.From).Should(Equal([]networkingv1.NetworkPolicyPeer{getOperatorNetworkPolicyPeer()}))
+		})
+	})
+
+	Context("When assembling ChiaFarmer Services", func() {
+		It("Should put each port on its own Service, with only the peer Service using serviceType", func() {
+			ctx := context.Background()
+			nodePort := corev1.ServiceTypeNodePort
+			disabled := false
+			farmer := apiv1.ChiaFarmer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      chiaFarmerName,
+					Namespace: chiaFarmerNamespace,
+				},
+				Spec: apiv1.ChiaFarmerSpec{
+					ServiceType: "LoadBalancer",
+					Services: &apiv1.ChiaServicesConfig{
+						Peers: &apiv1.ChiaServiceConfig{
+							Annotations: map[string]string{"metallb.universe.tf/address-pool": "public"},
+						},
+						RPC: &apiv1.ChiaServiceConfig{
+							ServiceType: &nodePort,
+						},
+						Daemon: &apiv1.ChiaServiceConfig{
+							Enabled: &disabled,
+						},
+					},
+				},
+			}
+
+			r := &ChiaFarmerReconciler{}
+			peers := r.assembleBaseService(ctx, farmer)
+			Expect(peers.Name).Should(Equal("test-chiafarmer-farmer"))
+			Expect(peers.Spec.Type).Should(Equal(corev1.ServiceTypeLoadBalancer))
+			Expect(peers.Annotations).Should(HaveKeyWithValue("metallb.universe.tf/address-pool", "public"))
+			Expect(peers.Spec.Ports).Should(HaveLen(1))
+			Expect(peers.Spec.Ports[0].Port).Should(Equal(int32(farmerPort)))
+
+			rpc := r.assembleRPCService(ctx, farmer)
+			Expect(rpc.Name).Should(Equal("test-chiafarmer-farmer-rpc"))
+			Expect(rpc.Spec.Type).Should(Equal(corev1.ServiceTypeNodePort))
+			Expect(rpc.Spec.Ports[0].Port).Should(Equal(int32(farmerRPCPort)))
+			Expect(rpc.Annotations).ShouldNot(HaveKey("metallb.universe.tf/address-pool"))
+
+			daemon := r.assembleDaemonService(ctx, farmer)
+			Expect(daemon.Spec.Type).Should(Equal(corev1.ServiceTypeClusterIP))
+			Expect(daemon.Spec.Ports[0].Port).Should(Equal(int32(daemonPort)))
+
+			_, daemonConfig, rpcConfig := getServiceConfigs(farmer.Spec.Services)
+			Expect(serviceEnabled(daemonConfig)).Should(BeFalse())
+			Expect(serviceEnabled(rpcConfig)).Should(BeTrue())
+		})
+	})
 })
@@ -6,21 +6,36 @@ package controller
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
+	"net"
+	"path"
+	"path/filepath"
+	"sort"
 	"strconv"
+	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	k8schianetv1 "github.com/chia-network/chia-operator/api/v1"
+	"github.com/chia-network/chia-operator/internal/chiarpc"
 	"github.com/cisco-open/operator-tools/pkg/reconciler"
 )
 
@@ -30,6 +45,52 @@ const (
 
 	// harvesterRPCPort defines the port for the harvester RPC
 	harvesterRPCPort = 8560
+
+	// chiaHarvesterOwnerLabel is the label on ChiaHarvester objects naming the ChiaHarvester that owns them
+	chiaHarvesterOwnerLabel = "ChiaHarvester-owner"
+)
+
+const (
+	// plotDiscoveryPath is the directory the plot discovery ConfigMap is mounted to in the plot-discovery init container
+	plotDiscoveryPath = "/plot-discovery"
+
+	// defaultPlotDiscoveryHostRoot is the default directory on each node that plot disks are mounted under
+	defaultPlotDiscoveryHostRoot = "/mnt"
+
+	// defaultPlotDiscoveryNodeAnnotation is the default Node annotation listing a node's plot directories
+	defaultPlotDiscoveryNodeAnnotation = "k8s.chia.net/plot-directories"
+
+	// plotDiscoveryChecksumAnnotation is set on DaemonSet pods to restart them when a node's annotated plot directories change,
+	// since plot directories are only discovered on start
+	plotDiscoveryChecksumAnnotation = "k8s.chia.net/plot-discovery-checksum"
+
+	// plotDiscoveryScript finds plot directories on the node from the PLOT_GLOBS patterns and the node's annotated directories,
+	// and replaces harvester.plot_directories in config.yaml with them
+	plotDiscoveryScript = `set -e
+dirs=""
+for pattern in ${PLOT_GLOBS}; do
+  for d in ${pattern}; do
+    if [ -d "${d}" ]; then
+      dirs="${dirs} ${d}"
+    fi
+  done
+done
+if [ -f "` + plotDiscoveryPath + `/${NODE_NAME}" ]; then
+  for d in $(tr ',' ' ' < "` + plotDiscoveryPath + `/${NODE_NAME}"); do
+    if [ -d "${d}" ]; then
+      dirs="${dirs} ${d}"
+    fi
+  done
+fi
+if [ ! -f "${CHIA_ROOT}/config/config.yaml" ]; then
+  chia init
+fi
+yq -i '.harvester.plot_directories = []' "${CHIA_ROOT}/config/config.yaml"
+for d in ${dirs}; do
+  echo "Discovered plot directory ${d}"
+  PLOT_DIRECTORY="${d}" yq -i '.harvester.plot_directories += [strenv(PLOT_DIRECTORY)]' "${CHIA_ROOT}/config/config.yaml"
+done
+`
 )
 
 // ChiaHarvesterReconciler reconciles a ChiaHarvester object
@@ -41,8 +102,18 @@ type ChiaHarvesterReconciler struct {
 //+kubebuilder:rbac:groups=k8s.chia.net,resources=chiaharvesters,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=k8s.chia.net,resources=chiaharvesters/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=k8s.chia.net,resources=chiaharvesters/finalizers,verbs=update
-//+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch
+//+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=tcproutes;tlsroutes,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create
+//+kubebuilder:rbac:groups="",resources=persistentvolumes,verbs=get;list;watch;create
+//+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+//+kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors;prometheusrules,verbs=get;list;watch;create;update;patch;delete
 
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.14.4/pkg/reconcile
@@ -64,8 +135,9 @@ func (r *ChiaHarvesterReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	}
 
 	// Reconcile ChiaHarvester owned objects
+	peersConfig, daemonConfig, rpcConfig := getServiceConfigs(harvester.Spec.Services)
 	srv := r.assembleBaseService(ctx, harvester)
-	res, err := reconcileService(ctx, resourceReconciler, srv)
+	res, err := reconcileService(ctx, resourceReconciler, srv, serviceEnabled(peersConfig))
 	if err != nil {
 		if res == nil {
 			res = &reconcile.Result{}
@@ -73,8 +145,26 @@ func (r *ChiaHarvesterReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return *res, fmt.Errorf("ChiaHarvesterReconciler ChiaHarvester=%s encountered error reconciling harvester Service: %v", req.NamespacedName, err)
 	}
 
+	srv = r.assembleDaemonService(ctx, harvester)
+	res, err = reconcileService(ctx, resourceReconciler, srv, serviceEnabled(daemonConfig))
+	if err != nil {
+		if res == nil {
+			res = &reconcile.Result{}
+		}
+		return *res, fmt.Errorf("ChiaHarvesterReconciler ChiaHarvester=%s encountered error reconciling harvester daemon Service: %v", req.NamespacedName, err)
+	}
+
+	srv = r.assembleRPCService(ctx, harvester)
+	res, err = reconcileService(ctx, resourceReconciler, srv, serviceEnabled(rpcConfig))
+	if err != nil {
+		if res == nil {
+			res = &reconcile.Result{}
+		}
+		return *res, fmt.Errorf("ChiaHarvesterReconciler ChiaHarvester=%s encountered error reconciling harvester RPC Service: %v", req.NamespacedName, err)
+	}
+
 	srv = r.assembleChiaExporterService(ctx, harvester)
-	res, err = reconcileService(ctx, resourceReconciler, srv)
+	res, err = reconcileService(ctx, resourceReconciler, srv, chiaExporterEnabled(harvester.Spec.ChiaExporterConfig))
 	if err != nil {
 		if res == nil {
 			res = &reconcile.Result{}
@@ -82,8 +172,137 @@ func (r *ChiaHarvesterReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return *res, fmt.Errorf("ChiaHarvesterReconciler ChiaHarvester=%s encountered error reconciling harvester chia-exporter Service: %v", req.NamespacedName, err)
 	}
 
-	deploy := r.assembleDeployment(ctx, harvester)
-	res, err = reconcileDeployment(ctx, resourceReconciler, deploy)
+	networkPolicy := r.assembleNetworkPolicy(ctx, harvester)
+	res, err = reconcileNetworkPolicy(ctx, resourceReconciler, networkPolicy, networkPolicyEnabled(harvester.Spec.NetworkPolicy))
+	if err != nil {
+		if res == nil {
+			res = &reconcile.Result{}
+		}
+		return *res, fmt.Errorf("ChiaHarvesterReconciler ChiaHarvester=%s encountered error reconciling harvester NetworkPolicy: %v", req.NamespacedName, err)
+	}
+
+	for _, exposure := range r.getPortExposures(ctx, harvester) {
+		res, err = reconcilePortExposure(ctx, r.Client, resourceReconciler, exposure)
+		if err != nil {
+			if res == nil {
+				res = &reconcile.Result{}
+			}
+			return *res, fmt.Errorf("ChiaHarvesterReconciler ChiaHarvester=%s encountered error reconciling harvester %s routes: %v", req.NamespacedName, exposure.objMeta.Name, err)
+		}
+	}
+
+	monitor := r.assembleChiaExporterServiceMonitor(ctx, harvester)
+	res, err = reconcileOptionalResource(ctx, r.Client, resourceReconciler, monitor, serviceMonitorEnabled(harvester.Spec.ChiaExporterConfig))
+	if err != nil {
+		if res == nil {
+			res = &reconcile.Result{}
+		}
+		return *res, fmt.Errorf("ChiaHarvesterReconciler ChiaHarvester=%s encountered error reconciling harvester chia-exporter ServiceMonitor: %v", req.NamespacedName, err)
+	}
+
+	rule := r.assembleChiaExporterPrometheusRule(ctx, harvester)
+	res, err = reconcileOptionalResource(ctx, r.Client, resourceReconciler, rule, prometheusRuleEnabled(harvester.Spec.ChiaExporterConfig))
+	if err != nil {
+		if res == nil {
+			res = &reconcile.Result{}
+		}
+		return *res, fmt.Errorf("ChiaHarvesterReconciler ChiaHarvester=%s encountered error reconciling harvester chia-exporter PrometheusRule: %v", req.NamespacedName, err)
+	}
+
+	overrides, err := getChiaConfigOverridesData(ctx, harvester.Spec.ChiaConfig.ConfigOverrides)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("ChiaHarvesterReconciler ChiaHarvester=%s encountered error assembling config overrides: %v", req.NamespacedName, err)
+	}
+	overridesRef, err := getChiaConfigOverridesRefData(ctx, r.Client, harvester.Namespace, harvester.Spec.ChiaConfig.ConfigOverrides)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("ChiaHarvesterReconciler ChiaHarvester=%s encountered error reading referenced config overrides: %v", req.NamespacedName, err)
+	}
+	if ipFamilies, _ := getServiceIPFamilies(harvester.Spec.Services); len(ipFamilies) != 0 {
+		overrides[chiaIPFamiliesKey], err = getIPFamiliesOverride("harvester", ipFamilies)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("ChiaHarvesterReconciler ChiaHarvester=%s encountered error assembling IP family config: %v", req.NamespacedName, err)
+		}
+	}
+	configMap := r.assembleConfigOverridesConfigMap(ctx, harvester, overrides)
+	res, err = reconcileConfigMap(ctx, resourceReconciler, configMap, len(overrides) != 0)
+	if err != nil {
+		if res == nil {
+			res = &reconcile.Result{}
+		}
+		return *res, fmt.Errorf("ChiaHarvesterReconciler ChiaHarvester=%s encountered error reconciling harvester config overrides ConfigMap: %v", req.NamespacedName, err)
+	}
+
+	daemonSetMode := harvester.Spec.Mode == k8schianetv1.ChiaHarvesterModeDaemonSet
+	var discovery map[string]string
+	if daemonSetMode {
+		condition := getDaemonSetStorageValidCondition(harvester)
+		meta.SetStatusCondition(&harvester.Status.Conditions, condition)
+		if condition.Status == metav1.ConditionFalse {
+			// The spec has to change before the harvester can be deployed, which triggers another reconcile
+			harvester.Status.Ready = false
+			err = r.Status().Update(ctx, &harvester)
+			if err != nil {
+				log.Error(err, fmt.Sprintf("ChiaHarvesterReconciler ChiaHarvester=%s unable to update ChiaHarvester status", req.NamespacedName))
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{}, nil
+		}
+
+		var skipped []string
+		discovery, skipped, err = r.getPlotDiscoveryData(ctx, harvester)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("ChiaHarvesterReconciler ChiaHarvester=%s encountered error discovering node plot directories: %v", req.NamespacedName, err)
+		}
+		meta.SetStatusCondition(&harvester.Status.Conditions, getPlotDirectoriesSkippedCondition(harvester, skipped))
+	} else {
+		meta.RemoveStatusCondition(&harvester.Status.Conditions, k8schianetv1.ChiaHarvesterConditionPlotDirectoriesSkipped)
+		meta.RemoveStatusCondition(&harvester.Status.Conditions, k8schianetv1.ChiaHarvesterConditionDaemonSetStorageValid)
+	}
+	configMap = r.assemblePlotDiscoveryConfigMap(ctx, harvester, discovery)
+	res, err = reconcileConfigMap(ctx, resourceReconciler, configMap, daemonSetMode)
+	if err != nil {
+		if res == nil {
+			res = &reconcile.Result{}
+		}
+		return *res, fmt.Errorf("ChiaHarvesterReconciler ChiaHarvester=%s encountered error reconciling harvester plot discovery ConfigMap: %v", req.NamespacedName, err)
+	}
+
+	if chiaRootClaimCreated(harvester.Spec.Storage) {
+		pvc, err := r.assembleChiaRootPersistentVolumeClaim(ctx, harvester)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("ChiaHarvesterReconciler ChiaHarvester=%s encountered error assembling harvester CHIA_ROOT PersistentVolumeClaim: %v", req.NamespacedName, err)
+		}
+		res, err = reconcilePersistentVolumeClaim(ctx, resourceReconciler, pvc)
+		if err != nil {
+			if res == nil {
+				res = &reconcile.Result{}
+			}
+			return *res, fmt.Errorf("ChiaHarvesterReconciler ChiaHarvester=%s encountered error reconciling harvester CHIA_ROOT PersistentVolumeClaim: %v", req.NamespacedName, err)
+		}
+	}
+
+	pvs, pvcs := r.assembleLocalPlotVolumes(ctx, harvester)
+	for _, pv := range pvs {
+		res, err = reconcilePersistentVolume(ctx, resourceReconciler, pv)
+		if err != nil {
+			if res == nil {
+				res = &reconcile.Result{}
+			}
+			return *res, fmt.Errorf("ChiaHarvesterReconciler ChiaHarvester=%s encountered error reconciling harvester local plot PersistentVolume: %v", req.NamespacedName, err)
+		}
+	}
+	for _, pvc := range pvcs {
+		res, err = reconcilePersistentVolumeClaim(ctx, resourceReconciler, pvc)
+		if err != nil {
+			if res == nil {
+				res = &reconcile.Result{}
+			}
+			return *res, fmt.Errorf("ChiaHarvesterReconciler ChiaHarvester=%s encountered error reconciling harvester local plot PersistentVolumeClaim: %v", req.NamespacedName, err)
+		}
+	}
+
+	deploy := r.assembleDeployment(ctx, harvester, overrides, overridesRef)
+	res, err = reconcileDeployment(ctx, resourceReconciler, deploy, !daemonSetMode)
 	if err != nil {
 		if res == nil {
 			res = &reconcile.Result{}
@@ -91,54 +310,141 @@ func (r *ChiaHarvesterReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return *res, fmt.Errorf("ChiaHarvesterReconciler ChiaHarvester=%s encountered error reconciling harvester Deployment: %v", req.NamespacedName, err)
 	}
 
+	ds := r.assembleDaemonSet(ctx, harvester, overrides, overridesRef, discovery)
+	res, err = reconcileDaemonSet(ctx, resourceReconciler, ds, daemonSetMode)
+	if err != nil {
+		if res == nil {
+			res = &reconcile.Result{}
+		}
+		return *res, fmt.Errorf("ChiaHarvesterReconciler ChiaHarvester=%s encountered error reconciling harvester DaemonSet: %v", req.NamespacedName, err)
+	}
+
 	// Update CR status
 	harvester.Status.Ready = true
+	r.updatePlotStatus(ctx, &harvester)
 	err = r.Status().Update(ctx, &harvester)
 	if err != nil {
 		log.Error(err, fmt.Sprintf("ChiaHarvesterReconciler ChiaHarvester=%s unable to update ChiaHarvester status", req.NamespacedName))
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: statusRefreshInterval}, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *ChiaHarvesterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&k8schianetv1.ChiaHarvester{}).
+		// Only spec changes trigger reconciles, status updates would otherwise requeue the harvester immediately instead of after statusRefreshInterval
+		For(&k8schianetv1.ChiaHarvester{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(
+			&corev1.Node{},
+			handler.EnqueueRequestsFromMapFunc(r.daemonSetHarvestersForNode),
+			builder.WithPredicates(predicate.Or(predicate.LabelChangedPredicate{}, predicate.AnnotationChangedPredicate{})),
+		).
 		Complete(r)
 }
 
-// assembleBaseService reconciles the main Service resource for a ChiaHarvester CR
+// daemonSetHarvestersForNode returns a reconcile request for every ChiaHarvester in DaemonSet mode, so plot discovery is refreshed when a Node is added, removed, or relabeled
+func (r *ChiaHarvesterReconciler) daemonSetHarvestersForNode(ctx context.Context, node client.Object) []reconcile.Request {
+	var harvesters k8schianetv1.ChiaHarvesterList
+	err := r.List(ctx, &harvesters)
+	if err != nil {
+		log.FromContext(ctx).Error(err, fmt.Sprintf("ChiaHarvesterReconciler unable to list ChiaHarvesters for Node %s", node.GetName()))
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, harvester := range harvesters.Items {
+		if harvester.Spec.Mode == k8schianetv1.ChiaHarvesterModeDaemonSet {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: harvester.Namespace, Name: harvester.Name},
+			})
+		}
+	}
+	return requests
+}
+
+// assembleBaseService assembles the main peer port Service resource for a ChiaHarvester CR
 func (r *ChiaHarvesterReconciler) assembleBaseService(ctx context.Context, harvester k8schianetv1.ChiaHarvester) corev1.Service {
+	peers, _, _ := getServiceConfigs(harvester.Spec.Services)
+	objMeta := metav1.ObjectMeta{
+		Name:            fmt.Sprintf("%s-harvester", harvester.Name),
+		Namespace:       harvester.Namespace,
+		Labels:          r.getCommonLabels(ctx, harvester, harvester.Spec.AdditionalMetadata.Labels),
+		Annotations:     harvester.Spec.AdditionalMetadata.Annotations,
+		OwnerReferences: r.getOwnerReference(ctx, harvester),
+	}
+	srv := getPortService(objMeta, r.getCommonLabels(ctx, harvester, harvester.Spec.AdditionalMetadata.Labels), peers, corev1.ServiceType(harvester.Spec.ServiceType), corev1.ServicePort{
+		Port:       harvesterPort,
+		TargetPort: intstr.FromString("peers"),
+		Protocol:   "TCP",
+		Name:       "peers",
+	})
+	srv.Spec.IPFamilies, srv.Spec.IPFamilyPolicy = getServiceIPFamilies(harvester.Spec.Services)
+	return srv
+}
+
+// assembleDaemonService assembles the daemon port Service resource for a ChiaHarvester CR
+func (r *ChiaHarvesterReconciler) assembleDaemonService(ctx context.Context, harvester k8schianetv1.ChiaHarvester) corev1.Service {
+	_, daemon, _ := getServiceConfigs(harvester.Spec.Services)
+	objMeta := metav1.ObjectMeta{
+		Name:            fmt.Sprintf("%s-harvester-daemon", harvester.Name),
+		Namespace:       harvester.Namespace,
+		Labels:          r.getCommonLabels(ctx, harvester, harvester.Spec.AdditionalMetadata.Labels),
+		Annotations:     harvester.Spec.AdditionalMetadata.Annotations,
+		OwnerReferences: r.getOwnerReference(ctx, harvester),
+	}
+	srv := getPortService(objMeta, r.getCommonLabels(ctx, harvester, harvester.Spec.AdditionalMetadata.Labels), daemon, corev1.ServiceTypeClusterIP, corev1.ServicePort{
+		Port:       daemonPort,
+		TargetPort: intstr.FromString("daemon"),
+		Protocol:   "TCP",
+		Name:       "daemon",
+	})
+	srv.Spec.IPFamilies, srv.Spec.IPFamilyPolicy = getServiceIPFamilies(harvester.Spec.Services)
+	return srv
+}
+
+// assembleRPCService assembles the RPC port Service resource for a ChiaHarvester CR
+func (r *ChiaHarvesterReconciler) assembleRPCService(ctx context.Context, harvester k8schianetv1.ChiaHarvester) corev1.Service {
+	_, _, rpc := getServiceConfigs(harvester.Spec.Services)
+	objMeta := metav1.ObjectMeta{
+		Name:            fmt.Sprintf("%s-harvester-rpc", harvester.Name),
+		Namespace:       harvester.Namespace,
+		Labels:          r.getCommonLabels(ctx, harvester, harvester.Spec.AdditionalMetadata.Labels),
+		Annotations:     harvester.Spec.AdditionalMetadata.Annotations,
+		OwnerReferences: r.getOwnerReference(ctx, harvester),
+	}
+	srv := getPortService(objMeta, r.getCommonLabels(ctx, harvester, harvester.Spec.AdditionalMetadata.Labels), rpc, corev1.ServiceTypeClusterIP, corev1.ServicePort{
+		Port:       harvesterRPCPort,
+		TargetPort: intstr.FromString("rpc"),
+		Protocol:   "TCP",
+		Name:       "rpc",
+	})
+	srv.Spec.IPFamilies, srv.Spec.IPFamilyPolicy = getServiceIPFamilies(harvester.Spec.Services)
+	return srv
+}
+
+// assembleChiaExporterService assembles the chia-exporter Service resource for a ChiaHarvester CR
+func (r *ChiaHarvesterReconciler) assembleChiaExporterService(ctx context.Context, harvester k8schianetv1.ChiaHarvester) corev1.Service {
+	ipFamilies, ipFamilyPolicy := getServiceIPFamilies(harvester.Spec.Services)
 	return corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:            fmt.Sprintf("%s-harvester", harvester.Name),
+			Name:            fmt.Sprintf("%s-harvester-metrics", harvester.Name),
 			Namespace:       harvester.Namespace,
-			Labels:          r.getCommonLabels(ctx, harvester, harvester.Spec.AdditionalMetadata.Labels),
+			Labels:          r.getCommonLabels(ctx, harvester, harvester.Spec.AdditionalMetadata.Labels, harvester.Spec.ChiaExporterConfig.ServiceLabels, chiaExporterServiceLabels),
 			Annotations:     harvester.Spec.AdditionalMetadata.Annotations,
 			OwnerReferences: r.getOwnerReference(ctx, harvester),
 		},
 		Spec: corev1.ServiceSpec{
-			Type: corev1.ServiceType(harvester.Spec.ServiceType),
+			Type:           corev1.ServiceType("ClusterIP"),
+			IPFamilies:     ipFamilies,
+			IPFamilyPolicy: ipFamilyPolicy,
 			Ports: []corev1.ServicePort{
 				{
-					Port:       daemonPort,
-					TargetPort: intstr.FromString("daemon"),
-					Protocol:   "TCP",
-					Name:       "daemon",
-				},
-				{
-					Port:       harvesterPort,
-					TargetPort: intstr.FromString("peers"),
-					Protocol:   "TCP",
-					Name:       "peers",
-				},
-				{
-					Port:       harvesterRPCPort,
-					TargetPort: intstr.FromString("rpc"),
+					Port:       getChiaExporterPort(harvester.Spec.ChiaExporterConfig),
+					TargetPort: intstr.FromString("metrics"),
 					Protocol:   "TCP",
-					Name:       "rpc",
+					Name:       "metrics",
 				},
 			},
 			Selector: r.getCommonLabels(ctx, harvester, harvester.Spec.AdditionalMetadata.Labels),
@@ -146,33 +452,211 @@ func (r *ChiaHarvesterReconciler) assembleBaseService(ctx context.Context, harve
 	}
 }
 
-// assembleChiaExporterService assembles the chia-exporter Service resource for a ChiaHarvester CR
-func (r *ChiaHarvesterReconciler) assembleChiaExporterService(ctx context.Context, harvester k8schianetv1.ChiaHarvester) corev1.Service {
-	return corev1.Service{
+// assembleNetworkPolicy assembles the NetworkPolicy resource for a ChiaHarvester CR. The peer port is closed unless sources are listed in peerFrom, since harvesters only make outbound peer connections
+func (r *ChiaHarvesterReconciler) assembleNetworkPolicy(ctx context.Context, harvester k8schianetv1.ChiaHarvester) networkingv1.NetworkPolicy {
+	var peerRules []networkingv1.NetworkPolicyIngressRule
+	if harvester.Spec.NetworkPolicy != nil && len(harvester.Spec.NetworkPolicy.PeerFrom) != 0 {
+		peerRules = append(peerRules, getNetworkPolicyIngressRule(harvesterPort, harvester.Spec.NetworkPolicy.PeerFrom))
+	}
+
+	objMeta := metav1.ObjectMeta{
+		Name:            fmt.Sprintf("%s-harvester", harvester.Name),
+		Namespace:       harvester.Namespace,
+		Labels:          r.getCommonLabels(ctx, harvester, harvester.Spec.AdditionalMetadata.Labels),
+		Annotations:     harvester.Spec.AdditionalMetadata.Annotations,
+		OwnerReferences: r.getOwnerReference(ctx, harvester),
+	}
+	return getNetworkPolicy(objMeta, r.getCommonLabels(ctx, harvester, harvester.Spec.AdditionalMetadata.Labels), harvester.Spec.NetworkPolicy, peerRules, harvesterRPCPort, harvester.Spec.ChiaExporterConfig)
+}
+
+// getPortExposures gives the Gateway API routes exposing the peer and RPC ports of a ChiaHarvester
+func (r *ChiaHarvesterReconciler) getPortExposures(ctx context.Context, harvester k8schianetv1.ChiaHarvester) []portExposure {
+	objMeta := metav1.ObjectMeta{
+		Name:            fmt.Sprintf("%s-harvester", harvester.Name),
+		Namespace:       harvester.Namespace,
+		Labels:          r.getCommonLabels(ctx, harvester, harvester.Spec.AdditionalMetadata.Labels),
+		Annotations:     harvester.Spec.AdditionalMetadata.Annotations,
+		OwnerReferences: r.getOwnerReference(ctx, harvester),
+	}
+	return getPortExposures(objMeta, harvester.Spec.Expose, harvesterPort, harvesterRPCPort)
+}
+
+// assembleChiaExporterServiceMonitor assembles the chia-exporter ServiceMonitor resource for a ChiaHarvester CR
+func (r *ChiaHarvesterReconciler) assembleChiaExporterServiceMonitor(ctx context.Context, harvester k8schianetv1.ChiaHarvester) *unstructured.Unstructured {
+	objMeta := metav1.ObjectMeta{
+		Name:            fmt.Sprintf("%s-harvester-metrics", harvester.Name),
+		Namespace:       harvester.Namespace,
+		Labels:          r.getCommonLabels(ctx, harvester, harvester.Spec.AdditionalMetadata.Labels),
+		Annotations:     harvester.Spec.AdditionalMetadata.Annotations,
+		OwnerReferences: r.getOwnerReference(ctx, harvester),
+	}
+	return getChiaExporterServiceMonitor(ctx, objMeta, harvester.Spec.ChiaExporterConfig, r.getCommonLabels(ctx, harvester, chiaExporterServiceLabels))
+}
+
+// assembleChiaExporterPrometheusRule assembles the chia-exporter PrometheusRule resource for a ChiaHarvester CR
+func (r *ChiaHarvesterReconciler) assembleChiaExporterPrometheusRule(ctx context.Context, harvester k8schianetv1.ChiaHarvester) *unstructured.Unstructured {
+	objMeta := metav1.ObjectMeta{
+		Name:            fmt.Sprintf("%s-harvester", harvester.Name),
+		Namespace:       harvester.Namespace,
+		Labels:          r.getCommonLabels(ctx, harvester, harvester.Spec.AdditionalMetadata.Labels),
+		Annotations:     harvester.Spec.AdditionalMetadata.Annotations,
+		OwnerReferences: r.getOwnerReference(ctx, harvester),
+	}
+	service := fmt.Sprintf("%s-harvester-metrics", harvester.Name)
+	selector := getChiaExporterAlertSelector(harvester.Namespace, service)
+	alerts := []chiaExporterAlert{
+		getChiaExporterDownAlert(harvester.Name, harvester.Namespace, service),
+		{
+			Name:        "ChiaHarvesterPlotsDropped",
+			Expr:        fmt.Sprintf("sum(chia_harvester_total_plots%s) < sum(chia_harvester_total_plots%s offset 1h)", selector, selector),
+			For:         "15m",
+			Summary:     fmt.Sprintf("Chia harvester %s/%s plot count dropped", harvester.Namespace, harvester.Name),
+			Description: fmt.Sprintf("The harvester %s/%s has fewer plots loaded than it did an hour ago. A plot drive may have failed or been unmounted.", harvester.Namespace, harvester.Name),
+		},
+	}
+	return getChiaExporterPrometheusRule(ctx, objMeta, harvester.Spec.ChiaExporterConfig, objMeta.Name, alerts)
+}
+
+// assembleConfigOverridesConfigMap assembles the config.yaml overrides ConfigMap resource for a ChiaHarvester CR
+func (r *ChiaHarvesterReconciler) assembleConfigOverridesConfigMap(ctx context.Context, harvester k8schianetv1.ChiaHarvester, data map[string]string) corev1.ConfigMap {
+	return corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:            fmt.Sprintf("%s-harvester-metrics", harvester.Name),
+			Name:            fmt.Sprintf("%s-harvester-config-overrides", harvester.Name),
 			Namespace:       harvester.Namespace,
-			Labels:          r.getCommonLabels(ctx, harvester, harvester.Spec.AdditionalMetadata.Labels, harvester.Spec.ChiaExporterConfig.ServiceLabels),
+			Labels:          r.getCommonLabels(ctx, harvester, harvester.Spec.AdditionalMetadata.Labels),
 			Annotations:     harvester.Spec.AdditionalMetadata.Annotations,
 			OwnerReferences: r.getOwnerReference(ctx, harvester),
 		},
-		Spec: corev1.ServiceSpec{
-			Type: corev1.ServiceType("ClusterIP"),
-			Ports: []corev1.ServicePort{
-				{
-					Port:       chiaExporterPort,
-					TargetPort: intstr.FromString("metrics"),
-					Protocol:   "TCP",
-					Name:       "metrics",
+		Data: data,
+	}
+}
+
+// assembleDeployment assembles the harvester Deployment resource for a ChiaHarvester CR
+func (r *ChiaHarvesterReconciler) assembleDeployment(ctx context.Context, harvester k8schianetv1.ChiaHarvester, overrides map[string]string, overridesRef string) appsv1.Deployment {
+	return appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            fmt.Sprintf("%s-harvester", harvester.Name),
+			Namespace:       harvester.Namespace,
+			Labels:          r.getCommonLabels(ctx, harvester, harvester.Spec.AdditionalMetadata.Labels),
+			Annotations:     harvester.Spec.AdditionalMetadata.Annotations,
+			OwnerReferences: r.getOwnerReference(ctx, harvester),
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: r.getCommonLabels(ctx, harvester),
+			},
+			Template: r.assemblePodTemplate(ctx, harvester, overrides, overridesRef),
+		},
+	}
+}
+
+// assembleDaemonSet assembles the harvester DaemonSet resource for a ChiaHarvester CR in DaemonSet mode.
+// discoveryData is the plot discovery ConfigMap data, which the pod template carries a checksum of
+func (r *ChiaHarvesterReconciler) assembleDaemonSet(ctx context.Context, harvester k8schianetv1.ChiaHarvester, overrides map[string]string, overridesRef string, discoveryData map[string]string) appsv1.DaemonSet {
+	template := r.assemblePodTemplate(ctx, harvester, overrides, overridesRef)
+	template.Annotations = getConfigChecksumPodAnnotations(template.Annotations, map[string]string{plotDiscoveryPath: getPlotDiscoveryDocument(discoveryData)}, plotDiscoveryPath, plotDiscoveryChecksumAnnotation)
+	discovery := getPlotDiscoveryConfig(harvester)
+	globs, _ := filterPlotDiscoveryPaths(discovery.HostRoot, discovery.PathGlobs)
+
+	var imagePullPolicy corev1.PullPolicy
+	if harvester.Spec.ImagePullPolicy != nil {
+		imagePullPolicy = *harvester.Spec.ImagePullPolicy
+	}
+
+	hostRootPropagation := corev1.MountPropagationHostToContainer
+	hostRootMount := corev1.VolumeMount{
+		Name:             "plot-discovery-host",
+		MountPath:        discovery.HostRoot,
+		ReadOnly:         true,
+		MountPropagation: &hostRootPropagation,
+	}
+	optional := true
+	template.Spec.Volumes = append(template.Spec.Volumes,
+		corev1.Volume{
+			Name: "plot-discovery-host",
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{
+					Path: discovery.HostRoot,
+				},
+			},
+		},
+		corev1.Volume{
+			Name: "plot-discovery",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: fmt.Sprintf("%s-harvester-plot-discovery", harvester.Name),
+					},
+					Optional: &optional,
 				},
 			},
-			Selector: r.getCommonLabels(ctx, harvester, harvester.Spec.AdditionalMetadata.Labels),
+		},
+	)
+	for i := range template.Spec.Containers {
+		if template.Spec.Containers[i].Name == "chia" {
+			template.Spec.Containers[i].VolumeMounts = append(template.Spec.Containers[i].VolumeMounts, hostRootMount)
+		}
+	}
+
+	// Discover plots before merging config overrides, so overrides can still replace the discovered plot directories
+	discoveryContainer := corev1.Container{
+		Name:            "plot-discovery",
+		SecurityContext: harvester.Spec.ChiaConfig.SecurityContext,
+		Image:           harvester.Spec.ChiaConfig.Image,
+		ImagePullPolicy: imagePullPolicy,
+		Command:         []string{"/bin/sh", "-c", plotDiscoveryScript},
+		Env: []corev1.EnvVar{
+			{
+				Name:  "CHIA_ROOT",
+				Value: "/chia-data",
+			},
+			{
+				Name:  "PLOT_GLOBS",
+				Value: strings.Join(globs, " "),
+			},
+			{
+				Name: "NODE_NAME",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{
+						FieldPath: "spec.nodeName",
+					},
+				},
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      "chiaroot",
+				MountPath: "/chia-data",
+			},
+			{
+				Name:      "plot-discovery",
+				MountPath: plotDiscoveryPath,
+				ReadOnly:  true,
+			},
+			hostRootMount,
+		},
+	}
+	template.Spec.InitContainers = append([]corev1.Container{discoveryContainer}, template.Spec.InitContainers...)
+
+	return appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            fmt.Sprintf("%s-harvester", harvester.Name),
+			Namespace:       harvester.Namespace,
+			Labels:          r.getCommonLabels(ctx, harvester, harvester.Spec.AdditionalMetadata.Labels),
+			Annotations:     harvester.Spec.AdditionalMetadata.Annotations,
+			OwnerReferences: r.getOwnerReference(ctx, harvester),
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: r.getCommonLabels(ctx, harvester),
+			},
+			Template: template,
 		},
 	}
 }
 
-// assembleDeployment assembles the harvester Deployment resource for a ChiaHarvester CR
-func (r *ChiaHarvesterReconciler) assembleDeployment(ctx context.Context, harvester k8schianetv1.ChiaHarvester) appsv1.Deployment {
+// assemblePodTemplate assembles the harvester pod template shared by the harvester Deployment and DaemonSet
+func (r *ChiaHarvesterReconciler) assemblePodTemplate(ctx context.Context, harvester k8schianetv1.ChiaHarvester, overrides map[string]string, overridesRef string) corev1.PodTemplateSpec {
 	var chiaSecContext *corev1.SecurityContext
 	if harvester.Spec.ChiaConfig.SecurityContext != nil {
 		chiaSecContext = harvester.Spec.ChiaConfig.SecurityContext
@@ -203,78 +687,218 @@ func (r *ChiaHarvesterReconciler) assembleDeployment(ctx context.Context, harves
 		imagePullPolicy = *harvester.Spec.ImagePullPolicy
 	}
 
-	var chiaExporterImage = harvester.Spec.ChiaExporterConfig.Image
-	if chiaExporterImage == "" {
-		chiaExporterImage = defaultChiaExporterImage
+	var template = corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:      r.getCommonLabels(ctx, harvester, harvester.Spec.AdditionalMetadata.Labels),
+			Annotations: getConfigChecksumPodAnnotations(getConfigOverridesPodAnnotations(harvester.Spec.AdditionalMetadata.Annotations, overrides, overridesRef), overrides, chiaIPFamiliesKey, ipFamiliesChecksumAnnotation),
+		},
+		Spec: corev1.PodSpec{
+			// TODO add: imagePullSecret, serviceAccountName config
+			Containers: []corev1.Container{
+				{
+					Name:            "chia",
+					SecurityContext: chiaSecContext,
+					Image:           harvester.Spec.ChiaConfig.Image,
+					ImagePullPolicy: imagePullPolicy,
+					Env:             r.getChiaEnv(ctx, harvester),
+					Ports: []corev1.ContainerPort{
+						{
+							Name:          "daemon",
+							ContainerPort: daemonPort,
+							Protocol:      "TCP",
+						},
+						{
+							Name:          "peers",
+							ContainerPort: harvesterPort,
+							Protocol:      "TCP",
+						},
+						{
+							Name:          "rpc",
+							ContainerPort: harvesterRPCPort,
+							Protocol:      "TCP",
+						},
+					},
+					LivenessProbe:  chiaLivenessProbe,
+					ReadinessProbe: chiaReadinessProbe,
+					StartupProbe:   chiaStartupProbe,
+					Resources:      chiaResources,
+					VolumeMounts:   r.getChiaVolumeMounts(ctx, harvester),
+				},
+			},
+			NodeSelector: harvester.Spec.NodeSelector,
+			Volumes:      r.getChiaVolumes(ctx, harvester),
+		},
+	}
+
+	if chiaExporterEnabled(harvester.Spec.ChiaExporterConfig) {
+		exporterContainer := getChiaExporterContainer(ctx, harvester.Spec.ChiaExporterConfig, chiaSecContext, imagePullPolicy)
+		template.Spec.Containers = append(template.Spec.Containers, exporterContainer)
 	}
 
-	var deploy appsv1.Deployment = appsv1.Deployment{
+	overridesVolume, ok := getChiaConfigOverridesVolume(ctx, harvester.Spec.ChiaConfig.ConfigOverrides, fmt.Sprintf("%s-harvester-config-overrides", harvester.Name), overrides)
+	if ok {
+		template.Spec.Volumes = append(template.Spec.Volumes, overridesVolume)
+		template.Spec.InitContainers = append(template.Spec.InitContainers, getChiaConfigOverridesInitContainer(ctx, harvester.Spec.ChiaConfig.Image, chiaSecContext, imagePullPolicy))
+	}
+
+	if harvester.Spec.PodSecurityContext != nil {
+		template.Spec.SecurityContext = harvester.Spec.PodSecurityContext
+	}
+
+	// TODO add pod affinity, tolerations
+
+	return template
+}
+
+// assemblePlotDiscoveryConfigMap assembles the ConfigMap listing each node's annotated plot directories for a ChiaHarvester CR in DaemonSet mode
+func (r *ChiaHarvesterReconciler) assemblePlotDiscoveryConfigMap(ctx context.Context, harvester k8schianetv1.ChiaHarvester, data map[string]string) corev1.ConfigMap {
+	return corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:            fmt.Sprintf("%s-harvester", harvester.Name),
+			Name:            fmt.Sprintf("%s-harvester-plot-discovery", harvester.Name),
 			Namespace:       harvester.Namespace,
 			Labels:          r.getCommonLabels(ctx, harvester, harvester.Spec.AdditionalMetadata.Labels),
 			Annotations:     harvester.Spec.AdditionalMetadata.Annotations,
 			OwnerReferences: r.getOwnerReference(ctx, harvester),
 		},
-		Spec: appsv1.DeploymentSpec{
-			Selector: &metav1.LabelSelector{
-				MatchLabels: r.getCommonLabels(ctx, harvester),
+		Data: data,
+	}
+}
+
+// getPlotDiscoveryData reads the plot directory annotation of every node the harvester DaemonSet runs on, keyed by node name.
+// It also returns the annotated directories and path globs that were skipped because they aren't under the plot discovery hostRoot
+func (r *ChiaHarvesterReconciler) getPlotDiscoveryData(ctx context.Context, harvester k8schianetv1.ChiaHarvester) (map[string]string, []string, error) {
+	discovery := getPlotDiscoveryConfig(harvester)
+
+	var nodes corev1.NodeList
+	err := r.List(ctx, &nodes, client.MatchingLabels(harvester.Spec.NodeSelector))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, skipped := filterPlotDiscoveryPaths(discovery.HostRoot, discovery.PathGlobs)
+	var data = make(map[string]string)
+	for _, node := range nodes.Items {
+		annotation, ok := node.Annotations[discovery.NodeAnnotation]
+		if !ok || annotation == "" {
+			continue
+		}
+		var dirs []string
+		for _, dir := range strings.Split(annotation, ",") {
+			if dir = strings.TrimSpace(dir); dir != "" {
+				dirs = append(dirs, dir)
+			}
+		}
+		allowed, skippedDirs := filterPlotDiscoveryPaths(discovery.HostRoot, dirs)
+		for _, dir := range skippedDirs {
+			skipped = append(skipped, fmt.Sprintf("%s:%s", node.Name, dir))
+		}
+		if len(allowed) != 0 {
+			data[node.Name] = strings.Join(allowed, ",")
+		}
+	}
+	return data, skipped, nil
+}
+
+// assembleChiaRootPersistentVolumeClaim assembles the operator-created CHIA_ROOT PersistentVolumeClaim resource for a ChiaHarvester CR
+func (r *ChiaHarvesterReconciler) assembleChiaRootPersistentVolumeClaim(ctx context.Context, harvester k8schianetv1.ChiaHarvester) (corev1.PersistentVolumeClaim, error) {
+	spec, err := getPersistentVolumeClaimSpec(harvester.Spec.Storage.ChiaRoot.PersistentVolumeClaim)
+	if err != nil {
+		return corev1.PersistentVolumeClaim{}, err
+	}
+
+	return corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            fmt.Sprintf("%s-harvester-chiaroot", harvester.Name),
+			Namespace:       harvester.Namespace,
+			Labels:          r.getCommonLabels(ctx, harvester, harvester.Spec.AdditionalMetadata.Labels),
+			Annotations:     harvester.Spec.AdditionalMetadata.Annotations,
+			OwnerReferences: r.getOwnerReference(ctx, harvester),
+		},
+		Spec: spec,
+	}, nil
+}
+
+// getLocalPlotClaimName returns the name of the PersistentVolumeClaim for a ChiaHarvester's local plot volume at the given index
+func getLocalPlotClaimName(harvester k8schianetv1.ChiaHarvester, index int) string {
+	return fmt.Sprintf("%s-harvester-local-plots-%d", harvester.Name, index)
+}
+
+// assembleLocalPlotVolumes assembles a local PersistentVolume for each of a ChiaHarvester's local plot volumes, and a claim bound to it.
+// PersistentVolumes are cluster scoped so their names include the namespace, and they can't be owned by the ChiaHarvester.
+// They keep their data with the Retain reclaim policy when the claim is deleted along with the ChiaHarvester
+func (r *ChiaHarvesterReconciler) assembleLocalPlotVolumes(ctx context.Context, harvester k8schianetv1.ChiaHarvester) ([]corev1.PersistentVolume, []corev1.PersistentVolumeClaim) {
+	if harvester.Spec.Storage == nil || harvester.Spec.Storage.Plots == nil {
+		return nil, nil
+	}
+
+	var pvs []corev1.PersistentVolume
+	var pvcs []corev1.PersistentVolumeClaim
+	for i, vol := range harvester.Spec.Storage.Plots.LocalVolume {
+		if vol == nil {
+			continue
+		}
+		claimName := getLocalPlotClaimName(harvester, i)
+		pvName := fmt.Sprintf("%s-%s", harvester.Namespace, claimName)
+		storageClass := vol.StorageClass
+		accessModes := []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
+
+		pvs = append(pvs, corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        pvName,
+				Labels:      r.getCommonLabels(ctx, harvester, harvester.Spec.AdditionalMetadata.Labels),
+				Annotations: harvester.Spec.AdditionalMetadata.Annotations,
 			},
-			Template: corev1.PodTemplateSpec{
-				ObjectMeta: metav1.ObjectMeta{
-					Labels:      r.getCommonLabels(ctx, harvester, harvester.Spec.AdditionalMetadata.Labels),
-					Annotations: harvester.Spec.AdditionalMetadata.Annotations,
+			Spec: corev1.PersistentVolumeSpec{
+				Capacity:    corev1.ResourceList{corev1.ResourceStorage: vol.Capacity},
+				AccessModes: accessModes,
+				PersistentVolumeSource: corev1.PersistentVolumeSource{
+					Local: &corev1.LocalVolumeSource{Path: vol.Path},
 				},
-				Spec: corev1.PodSpec{
-					// TODO add: imagePullSecret, serviceAccountName config
-					Containers: []corev1.Container{
-						{
-							Name:            "chia",
-							SecurityContext: chiaSecContext,
-							Image:           harvester.Spec.ChiaConfig.Image,
-							ImagePullPolicy: imagePullPolicy,
-							Env:             r.getChiaEnv(ctx, harvester),
-							Ports: []corev1.ContainerPort{
-								{
-									Name:          "daemon",
-									ContainerPort: daemonPort,
-									Protocol:      "TCP",
-								},
-								{
-									Name:          "peers",
-									ContainerPort: harvesterPort,
-									Protocol:      "TCP",
-								},
-								{
-									Name:          "rpc",
-									ContainerPort: harvesterRPCPort,
-									Protocol:      "TCP",
+				ClaimRef: &corev1.ObjectReference{
+					Kind:       "PersistentVolumeClaim",
+					APIVersion: "v1",
+					Namespace:  harvester.Namespace,
+					Name:       claimName,
+				},
+				PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimRetain,
+				StorageClassName:              storageClass,
+				NodeAffinity: &corev1.VolumeNodeAffinity{
+					Required: &corev1.NodeSelector{
+						NodeSelectorTerms: []corev1.NodeSelectorTerm{
+							{
+								MatchExpressions: []corev1.NodeSelectorRequirement{
+									{
+										Key:      corev1.LabelHostname,
+										Operator: corev1.NodeSelectorOpIn,
+										Values:   []string{vol.NodeName},
+									},
 								},
 							},
-							LivenessProbe:  chiaLivenessProbe,
-							ReadinessProbe: chiaReadinessProbe,
-							StartupProbe:   chiaStartupProbe,
-							Resources:      chiaResources,
-							VolumeMounts:   r.getChiaVolumeMounts(ctx, harvester),
 						},
 					},
-					NodeSelector: harvester.Spec.NodeSelector,
-					Volumes:      r.getChiaVolumes(ctx, harvester),
 				},
 			},
-		},
-	}
-
-	exporterContainer := getChiaExporterContainer(ctx, chiaExporterImage, chiaSecContext, imagePullPolicy, chiaResources)
-	deploy.Spec.Template.Spec.Containers = append(deploy.Spec.Template.Spec.Containers, exporterContainer)
+		})
 
-	if harvester.Spec.PodSecurityContext != nil {
-		deploy.Spec.Template.Spec.SecurityContext = harvester.Spec.PodSecurityContext
+		pvcs = append(pvcs, corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            claimName,
+				Namespace:       harvester.Namespace,
+				Labels:          r.getCommonLabels(ctx, harvester, harvester.Spec.AdditionalMetadata.Labels),
+				Annotations:     harvester.Spec.AdditionalMetadata.Annotations,
+				OwnerReferences: r.getOwnerReference(ctx, harvester),
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes:      accessModes,
+				StorageClassName: &storageClass,
+				VolumeName:       pvName,
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: vol.Capacity},
+				},
+			},
+		})
 	}
-
-	// TODO add pod affinity, tolerations
-
-	return deploy
+	return pvs, pvcs
 }
 
 // getChiaVolumes retrieves the requisite volumes from the Chia config struct
@@ -291,8 +915,8 @@ func (r *ChiaHarvesterReconciler) getChiaVolumes(ctx context.Context, harvester
 		},
 	})
 
-	// CHIA_ROOT volume -- PVC is respected first if both it and hostpath are specified, falls back to hostPath if specified
-	// If both are empty, fall back to emptyDir so chia-exporter can mount CHIA_ROOT
+	// CHIA_ROOT volume -- PVC is respected first, then hostPath, then NFS, CSI, ephemeral and iSCSI volumes
+	// If none are specified, fall back to emptyDir so chia-exporter can mount CHIA_ROOT
 	var chiaRootAdded bool = false
 	if harvester.Spec.Storage != nil && harvester.Spec.Storage.ChiaRoot != nil {
 		if harvester.Spec.Storage.ChiaRoot.PersistentVolumeClaim != nil {
@@ -300,19 +924,21 @@ func (r *ChiaHarvesterReconciler) getChiaVolumes(ctx context.Context, harvester
 				Name: "chiaroot",
 				VolumeSource: corev1.VolumeSource{
 					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
-						ClaimName: harvester.Spec.Storage.ChiaRoot.HostPathVolume.Path,
+						ClaimName: getChiaRootClaimName(harvester.Spec.Storage.ChiaRoot.PersistentVolumeClaim, fmt.Sprintf("%s-harvester-chiaroot", harvester.Name)),
 					},
 				},
 			})
 			chiaRootAdded = true
 		} else if harvester.Spec.Storage.ChiaRoot.HostPathVolume != nil {
 			v = append(v, corev1.Volume{
-				Name: "chiaroot",
-				VolumeSource: corev1.VolumeSource{
-					HostPath: &corev1.HostPathVolumeSource{
-						Path: harvester.Spec.Storage.ChiaRoot.HostPathVolume.Path,
-					},
-				},
+				Name:         "chiaroot",
+				VolumeSource: getHostPathVolumeSource(harvester.Spec.Storage.ChiaRoot.HostPathVolume),
+			})
+			chiaRootAdded = true
+		} else if source, ok := getChiaRootVolumeSource(harvester.Spec.Storage.ChiaRoot); ok {
+			v = append(v, corev1.Volume{
+				Name:         "chiaroot",
+				VolumeSource: source,
 			})
 			chiaRootAdded = true
 		}
@@ -326,7 +952,7 @@ func (r *ChiaHarvesterReconciler) getChiaVolumes(ctx context.Context, harvester
 		})
 	}
 
-	// hostPath and PVC plot volumes
+	// plot volumes
 	if harvester.Spec.Storage != nil {
 		if harvester.Spec.Storage.Plots != nil {
 			// PVC plot volumes
@@ -350,16 +976,67 @@ func (r *ChiaHarvesterReconciler) getChiaVolumes(ctx context.Context, harvester
 				for i, vol := range harvester.Spec.Storage.Plots.HostPathVolume {
 					if vol != nil {
 						v = append(v, corev1.Volume{
-							Name: fmt.Sprintf("hostpath-plots-%d", i),
-							VolumeSource: corev1.VolumeSource{
-								HostPath: &corev1.HostPathVolumeSource{
-									Path: vol.Path,
-								},
-							},
+							Name:         fmt.Sprintf("hostpath-plots-%d", i),
+							VolumeSource: getHostPathVolumeSource(vol),
 						})
 					}
 				}
 			}
+
+			// NFS plot volumes
+			for i, vol := range harvester.Spec.Storage.Plots.NFSVolume {
+				if vol != nil {
+					v = append(v, corev1.Volume{
+						Name:         fmt.Sprintf("nfs-plots-%d", i),
+						VolumeSource: getNFSVolumeSource(vol, plotMountReadOnly(vol.PlotMountConfig)),
+					})
+				}
+			}
+
+			// CSI plot volumes
+			for i, vol := range harvester.Spec.Storage.Plots.CSIVolume {
+				if vol != nil {
+					v = append(v, corev1.Volume{
+						Name:         fmt.Sprintf("csi-plots-%d", i),
+						VolumeSource: getCSIVolumeSource(vol, plotMountReadOnly(vol.PlotMountConfig)),
+					})
+				}
+			}
+
+			// generic ephemeral plot volumes
+			for i, vol := range harvester.Spec.Storage.Plots.EphemeralVolume {
+				if vol != nil {
+					v = append(v, corev1.Volume{
+						Name:         fmt.Sprintf("ephemeral-plots-%d", i),
+						VolumeSource: getEphemeralVolumeSource(vol),
+					})
+				}
+			}
+
+			// iSCSI plot volumes
+			for i, vol := range harvester.Spec.Storage.Plots.ISCSIVolume {
+				if vol != nil {
+					v = append(v, corev1.Volume{
+						Name:         fmt.Sprintf("iscsi-plots-%d", i),
+						VolumeSource: getISCSIVolumeSource(vol, plotMountReadOnly(vol.PlotMountConfig)),
+					})
+				}
+			}
+
+			// local plot volumes, mounted through the claims bound to their operator created PersistentVolumes
+			for i, vol := range harvester.Spec.Storage.Plots.LocalVolume {
+				if vol != nil {
+					v = append(v, corev1.Volume{
+						Name: fmt.Sprintf("local-plots-%d", i),
+						VolumeSource: corev1.VolumeSource{
+							PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+								ClaimName: getLocalPlotClaimName(harvester, i),
+								ReadOnly:  plotMountReadOnly(vol.PlotMountConfig),
+							},
+						},
+					})
+				}
+			}
 		}
 	}
 
@@ -382,40 +1059,107 @@ func (r *ChiaHarvesterReconciler) getChiaVolumeMounts(ctx context.Context, harve
 		MountPath: "/chia-data",
 	})
 
-	// hostPath and PVC plot volumemounts
-	if harvester.Spec.Storage != nil {
-		if harvester.Spec.Storage.Plots != nil {
-			// PVC plot volume mounts
-			if harvester.Spec.Storage.Plots.PersistentVolumeClaim != nil {
-				for i, vol := range harvester.Spec.Storage.Plots.PersistentVolumeClaim {
-					if vol != nil {
-						v = append(v, corev1.VolumeMount{
-							Name:      fmt.Sprintf("pvc-plots-%d", i),
-							ReadOnly:  true,
-							MountPath: fmt.Sprintf("/plots/pvc-plots-%d", i),
-						})
-					}
-				}
-			}
+	// plot volumemounts
+	v = append(v, r.getPlotVolumeMounts(ctx, harvester)...)
 
-			// hostPath plot volume mounts
-			if harvester.Spec.Storage.Plots.HostPathVolume != nil {
-				for i, vol := range harvester.Spec.Storage.Plots.HostPathVolume {
-					if vol != nil {
-						v = append(v, corev1.VolumeMount{
-							Name:      fmt.Sprintf("hostpath-plots-%d", i),
-							ReadOnly:  true,
-							MountPath: fmt.Sprintf("/plots/hostpath-plots-%d", i),
-						})
-					}
-				}
-			}
+	return v
+}
+
+// getPlotVolumeMounts retrieves the plot volume mounts from the Chia storage config struct
+func (r *ChiaHarvesterReconciler) getPlotVolumeMounts(ctx context.Context, harvester k8schianetv1.ChiaHarvester) []corev1.VolumeMount {
+	var v []corev1.VolumeMount
+	if harvester.Spec.Storage == nil || harvester.Spec.Storage.Plots == nil {
+		return v
+	}
+
+	// PVC plot volume mounts
+	for i, vol := range harvester.Spec.Storage.Plots.PersistentVolumeClaim {
+		if vol != nil {
+			v = append(v, getPlotVolumeMount(fmt.Sprintf("pvc-plots-%d", i), vol.PlotMountConfig))
+		}
+	}
+
+	// hostPath plot volume mounts
+	for i, vol := range harvester.Spec.Storage.Plots.HostPathVolume {
+		if vol != nil {
+			v = append(v, getPlotVolumeMount(fmt.Sprintf("hostpath-plots-%d", i), vol.PlotMountConfig))
+		}
+	}
+
+	// NFS plot volume mounts
+	for i, vol := range harvester.Spec.Storage.Plots.NFSVolume {
+		if vol != nil {
+			v = append(v, getPlotVolumeMount(fmt.Sprintf("nfs-plots-%d", i), vol.PlotMountConfig))
+		}
+	}
+
+	// CSI plot volume mounts
+	for i, vol := range harvester.Spec.Storage.Plots.CSIVolume {
+		if vol != nil {
+			v = append(v, getPlotVolumeMount(fmt.Sprintf("csi-plots-%d", i), vol.PlotMountConfig))
+		}
+	}
+
+	// generic ephemeral plot volume mounts
+	for i, vol := range harvester.Spec.Storage.Plots.EphemeralVolume {
+		if vol != nil {
+			v = append(v, getPlotVolumeMount(fmt.Sprintf("ephemeral-plots-%d", i), vol.PlotMountConfig))
+		}
+	}
+
+	// iSCSI plot volume mounts
+	for i, vol := range harvester.Spec.Storage.Plots.ISCSIVolume {
+		if vol != nil {
+			v = append(v, getPlotVolumeMount(fmt.Sprintf("iscsi-plots-%d", i), vol.PlotMountConfig))
+		}
+	}
+
+	// local plot volume mounts
+	for i, vol := range harvester.Spec.Storage.Plots.LocalVolume {
+		if vol != nil {
+			v = append(v, getPlotVolumeMount(fmt.Sprintf("local-plots-%d", i), vol.PlotMountConfig))
 		}
 	}
 
 	return v
 }
 
+// getPlotVolumeMount assembles the volume mount for a plot volume, mounting it read-only under /plots unless configured otherwise
+func getPlotVolumeMount(name string, mount k8schianetv1.PlotMountConfig) corev1.VolumeMount {
+	var mountPath = mount.MountPath
+	if mountPath == "" {
+		mountPath = fmt.Sprintf("/plots/%s", name)
+	}
+
+	return corev1.VolumeMount{
+		Name:      name,
+		ReadOnly:  plotMountReadOnly(mount),
+		MountPath: mountPath,
+		SubPath:   mount.SubPath,
+	}
+}
+
+// plotMountReadOnly returns whether a plot volume is mounted read-only, which is the default
+func plotMountReadOnly(mount k8schianetv1.PlotMountConfig) bool {
+	if mount.ReadOnly != nil {
+		return *mount.ReadOnly
+	}
+	return true
+}
+
+// getPlotDirectories returns the directories to add to the harvester's plot_directories, defaulting to the mount path of every plot volume
+func (r *ChiaHarvesterReconciler) getPlotDirectories(ctx context.Context, harvester k8schianetv1.ChiaHarvester) []string {
+	if harvester.Spec.Storage != nil && harvester.Spec.Storage.Plots != nil && len(harvester.Spec.Storage.Plots.PlotDirectories) != 0 {
+		return harvester.Spec.Storage.Plots.PlotDirectories
+	}
+
+	var dirs []string
+	for _, mount := range r.getPlotVolumeMounts(ctx, harvester) {
+		dirs = append(dirs, mount.MountPath)
+	}
+	return dirs
+}
+
 // getChiaEnv retrieves the environment variables from the Chia config struct
 func (r *ChiaHarvesterReconciler) getChiaEnv(ctx context.Context, harvester k8schianetv1.ChiaHarvester) []corev1.EnvVar {
 	var env []corev1.EnvVar
@@ -462,11 +1206,23 @@ func (r *ChiaHarvesterReconciler) getChiaEnv(ctx context.Context, harvester k8sc
 		})
 	}
 
-	// recursive_plot_scan env var -- needed because all plot drives are just mounted as subdirs under `/plots`.
-	// TODO make plot mount paths configurable -- make this var optional
+	// plots_dir env var -- a colon separated list of plot directories. chia-docker defaults this to /plots if unset
+	plotDirs := r.getPlotDirectories(ctx, harvester)
+	if len(plotDirs) != 0 {
+		env = append(env, corev1.EnvVar{
+			Name:  "plots_dir",
+			Value: strings.Join(plotDirs, ":"),
+		})
+	}
+
+	// recursive_plot_scan env var -- defaults to true, since plot volumes are mounted as subdirs under `/plots` unless configured otherwise
+	recursiveScan := "true"
+	if harvester.Spec.Storage != nil && harvester.Spec.Storage.Plots != nil && harvester.Spec.Storage.Plots.RecursiveScan != nil && !*harvester.Spec.Storage.Plots.RecursiveScan {
+		recursiveScan = "false"
+	}
 	env = append(env, corev1.EnvVar{
 		Name:  "recursive_plot_scan",
-		Value: "true",
+		Value: recursiveScan,
 	})
 
 	// farmer peer env vars
@@ -491,7 +1247,7 @@ func (r *ChiaHarvesterReconciler) getCommonLabels(ctx context.Context, harvester
 		}
 	}
 	labels["app.kubernetes.io/instance"] = harvester.Name
-	labels["ChiaHarvester-owner"] = harvester.Name
+	labels[chiaHarvesterOwnerLabel] = harvester.Name
 	labels = getCommonLabels(ctx, labels)
 	return labels
 }
@@ -508,3 +1264,357 @@ func (r *ChiaHarvesterReconciler) getOwnerReference(ctx context.Context, harvest
 		},
 	}
 }
+
+// updatePlotStatus gathers the plot inventory of every running harvester pod through its RPC server and sets it in the ChiaHarvester's status
+func (r *ChiaHarvesterReconciler) updatePlotStatus(ctx context.Context, harvester *k8schianetv1.ChiaHarvester) {
+	log := log.FromContext(ctx)
+	now := metav1.Now()
+	harvester.Status.LastStatusUpdateTime = &now
+
+	pods, err := listRunningPods(ctx, r.Client, harvester.Namespace, r.getCommonLabels(ctx, *harvester))
+	if err != nil {
+		log.Info(fmt.Sprintf("ChiaHarvesterReconciler ChiaHarvester=%s/%s unable to list harvester pods: %v", harvester.Namespace, harvester.Name, err))
+		return
+	}
+	if len(pods) == 0 {
+		return
+	}
+
+	tlsConfig, err := getChiaRPCTLSConfig(ctx, r.Client, harvester.Namespace, harvester.Spec.ChiaConfig.CASecretName)
+	if err != nil {
+		log.Info(fmt.Sprintf("ChiaHarvesterReconciler ChiaHarvester=%s/%s unable to create RPC client from CA Secret: %v", harvester.Namespace, harvester.Name, err))
+		return
+	}
+
+	var inventories []podPlots
+	for _, pod := range pods {
+		host, port := getChiaRPCHostPort(pod.Status.PodIP, harvesterRPCPort)
+		plots, err := chiarpc.NewHarvesterClient(host, port, tlsConfig).GetPlots(ctx)
+		if err != nil {
+			log.Info(fmt.Sprintf("ChiaHarvesterReconciler unable to get plots from pod %s/%s: %v", pod.Namespace, pod.Name, err))
+			continue
+		}
+		inventories = append(inventories, podPlots{pod: pod, plots: plots})
+	}
+	if len(inventories) == 0 {
+		return
+	}
+
+	status := getPlotsStatus(inventories)
+	syncing := r.addFarmerPlotCounts(ctx, harvester, tlsConfig, inventories, status)
+
+	// Pods that are restarting, rolling out, unreachable, or still loading plots report fewer plots than the harvester has,
+	// so the count is only compared once every expected pod reported a finished inventory
+	expected, settled := r.getExpectedPodCount(ctx, *harvester)
+	complete := settled && !syncing && len(inventories) == int(expected)
+
+	condition, ok := getPlotsDroppedCondition(*harvester, harvester.Status.Plots, status, complete)
+	harvester.Status.Plots = status
+	if ok {
+		meta.SetStatusCondition(&harvester.Status.Conditions, condition)
+	}
+}
+
+// getPlotsDroppedCondition sets the plot status' expected plot count and returns the PlotsDropped condition for it.
+// Incomplete counts keep the previous expected count and return false, leaving the condition as it was.
+// The expected count is the highest complete count since the spec last changed, so the condition stays true until dropped plots come back
+func getPlotsDroppedCondition(harvester k8schianetv1.ChiaHarvester, previous, status *k8schianetv1.ChiaHarvesterPlotsStatus, complete bool) (metav1.Condition, bool) {
+	if previous != nil {
+		status.ExpectedPlots = previous.ExpectedPlots
+	}
+	if !complete {
+		return metav1.Condition{}, false
+	}
+
+	existing := meta.FindStatusCondition(harvester.Status.Conditions, k8schianetv1.ChiaHarvesterConditionPlotsDropped)
+	if existing == nil || existing.ObservedGeneration != harvester.Generation {
+		status.ExpectedPlots = 0
+	}
+	if status.TotalPlots > status.ExpectedPlots {
+		status.ExpectedPlots = status.TotalPlots
+	}
+
+	condition := metav1.Condition{
+		Type:               k8schianetv1.ChiaHarvesterConditionPlotsDropped,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: harvester.Generation,
+		Reason:             "PlotCountStable",
+		Message:            fmt.Sprintf("%d plots loaded", status.TotalPlots),
+	}
+	switch {
+	case status.TotalPlots < status.ExpectedPlots:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "PlotCountDecreased"
+		condition.Message = fmt.Sprintf("plot count dropped from %d to %d", status.ExpectedPlots, status.TotalPlots)
+	case status.FailedToOpen > 0:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "PlotsFailedToOpen"
+		condition.Message = fmt.Sprintf("%d plot files failed to open", status.FailedToOpen)
+	}
+	return condition, true
+}
+
+// getExpectedPodCount returns the number of harvester pods the Deployment or DaemonSet should be running,
+// and false while it is rolling out, has unavailable pods, or can't be read
+func (r *ChiaHarvesterReconciler) getExpectedPodCount(ctx context.Context, harvester k8schianetv1.ChiaHarvester) (int32, bool) {
+	key := types.NamespacedName{Namespace: harvester.Namespace, Name: fmt.Sprintf("%s-harvester", harvester.Name)}
+	if harvester.Spec.Mode == k8schianetv1.ChiaHarvesterModeDaemonSet {
+		var ds appsv1.DaemonSet
+		if err := r.Get(ctx, key, &ds); err != nil {
+			return 0, false
+		}
+		return daemonSetSettled(ds)
+	}
+
+	var deploy appsv1.Deployment
+	if err := r.Get(ctx, key, &deploy); err != nil {
+		return 0, false
+	}
+	return deploymentSettled(deploy)
+}
+
+// deploymentSettled returns the Deployment's desired replicas, and whether every replica is updated and ready with no old pods left
+func deploymentSettled(deploy appsv1.Deployment) (int32, bool) {
+	replicas := int32(1)
+	if deploy.Spec.Replicas != nil {
+		replicas = *deploy.Spec.Replicas
+	}
+	status := deploy.Status
+	return replicas, deploy.Generation == status.ObservedGeneration &&
+		status.Replicas == replicas && status.UpdatedReplicas == replicas && status.ReadyReplicas == replicas
+}
+
+// daemonSetSettled returns the number of nodes the DaemonSet should run on, and whether every one of them runs an updated, ready pod
+func daemonSetSettled(ds appsv1.DaemonSet) (int32, bool) {
+	status := ds.Status
+	return status.DesiredNumberScheduled, ds.Generation == status.ObservedGeneration &&
+		status.CurrentNumberScheduled == status.DesiredNumberScheduled && status.UpdatedNumberScheduled == status.DesiredNumberScheduled &&
+		status.NumberReady == status.DesiredNumberScheduled && status.NumberUnavailable == 0
+}
+
+// addFarmerPlotCounts adds the duplicate and no-key plot counts the harvester's farmer reports for the harvester's pods to the plot status.
+// These counts are only known to the farmer, so they are left unset if the farmer can't be queried.
+// Returns true if the farmer reports any of the pods is still in its initial plot sync
+func (r *ChiaHarvesterReconciler) addFarmerPlotCounts(ctx context.Context, harvester *k8schianetv1.ChiaHarvester, tlsConfig *tls.Config, inventories []podPlots, status *k8schianetv1.ChiaHarvesterPlotsStatus) bool {
+	if harvester.Spec.ChiaConfig.FarmerAddress == "" {
+		return false
+	}
+
+	address := getFarmerRPCAddress(harvester.Spec.ChiaConfig)
+	host, port := getChiaRPCHostPort(address, farmerRPCPort)
+	summaries, err := chiarpc.NewFarmerClient(host, port, tlsConfig).GetHarvesters(ctx)
+	if err != nil {
+		log.FromContext(ctx).Info(fmt.Sprintf("ChiaHarvesterReconciler unable to get harvesters from farmer %s: %v", address, err))
+		return false
+	}
+
+	var podIPs = make(map[string]bool)
+	for _, inventory := range inventories {
+		podIPs[inventory.pod.Status.PodIP] = true
+	}
+
+	var duplicates, noKey int32
+	var syncing bool
+	for _, summary := range summaries {
+		if podIPs[summary.Connection.Host] {
+			duplicates += int32(summary.Duplicates)
+			noKey += int32(summary.NoKeyFilenames)
+			if summary.Syncing != nil && summary.Syncing.Initial {
+				syncing = true
+			}
+		}
+	}
+	status.Duplicates = &duplicates
+	status.NoKey = &noKey
+	return syncing
+}
+
+// getFarmerRPCAddress returns the address of the harvester's farmer's RPC server. ChiaFarmers serve RPC on a separate <name>-farmer-rpc Service,
+// so a farmerAddress naming a ChiaFarmer's peer Service is pointed at its RPC Service instead
+func getFarmerRPCAddress(config k8schianetv1.ChiaHarvesterConfigSpec) string {
+	if config.FarmerRPCAddress != nil {
+		return *config.FarmerRPCAddress
+	}
+	if net.ParseIP(config.FarmerAddress) != nil {
+		return config.FarmerAddress
+	}
+	service, domain, found := strings.Cut(config.FarmerAddress, ".")
+	if !strings.HasSuffix(service, "-farmer") {
+		return config.FarmerAddress
+	}
+	if !found {
+		return service + "-rpc"
+	}
+	return service + "-rpc." + domain
+}
+
+// podPlots is the plot inventory of a single harvester pod
+type podPlots struct {
+	pod   corev1.Pod
+	plots chiarpc.Plots
+}
+
+// getPlotsStatus totals the plot inventories of a harvester's pods, grouping plots by the volume mount they were found under
+func getPlotsStatus(inventories []podPlots) *k8schianetv1.ChiaHarvesterPlotsStatus {
+	var status k8schianetv1.ChiaHarvesterPlotsStatus
+	var rawSize, effectiveSize uint64
+	var directories = make(map[string]*k8schianetv1.ChiaHarvesterPlotDirectoryStatus)
+	var directorySizes = make(map[string]uint64)
+
+	for _, inventory := range inventories {
+		mounts := getChiaContainerMounts(inventory.pod)
+		for _, plot := range inventory.plots.Plots {
+			status.TotalPlots++
+			rawSize += plot.FileSize
+			effectiveSize += expectedPlotSize(plot.Size)
+
+			path, volume := getPlotMount(plot.Filename, mounts)
+			dir, ok := directories[path]
+			if !ok {
+				dir = &k8schianetv1.ChiaHarvesterPlotDirectoryStatus{
+					Path:   path,
+					Volume: volume,
+				}
+				directories[path] = dir
+			}
+			dir.Plots++
+			directorySizes[path] += plot.FileSize
+		}
+		status.FailedToOpen += int32(len(inventory.plots.FailedToOpenFilenames))
+	}
+
+	status.RawSize = *resource.NewQuantity(int64(rawSize), resource.BinarySI)
+	status.EffectiveSize = *resource.NewQuantity(int64(effectiveSize), resource.BinarySI)
+	for path, dir := range directories {
+		dir.RawSize = *resource.NewQuantity(int64(directorySizes[path]), resource.BinarySI)
+		status.Directories = append(status.Directories, *dir)
+	}
+	sort.Slice(status.Directories, func(i, j int) bool {
+		return status.Directories[i].Path < status.Directories[j].Path
+	})
+
+	return &status
+}
+
+// getChiaContainerMounts returns the volume mounts of a pod's chia container
+func getChiaContainerMounts(pod corev1.Pod) []corev1.VolumeMount {
+	for _, container := range pod.Spec.Containers {
+		if container.Name == "chia" {
+			return container.VolumeMounts
+		}
+	}
+	return nil
+}
+
+// getPlotMount returns the mount path and volume name of the deepest volume mount containing a plot file.
+// Plots that aren't on a mounted volume are grouped by their parent directory with no volume name.
+func getPlotMount(filename string, mounts []corev1.VolumeMount) (string, string) {
+	var path, volume string
+	for _, mount := range mounts {
+		mountPath := strings.TrimSuffix(mount.MountPath, "/")
+		if strings.HasPrefix(filename, mountPath+"/") && len(mountPath) > len(path) {
+			path = mountPath
+			volume = mount.Name
+		}
+	}
+	if path == "" {
+		return filepath.Dir(filename), ""
+	}
+	return path, volume
+}
+
+// expectedPlotSize returns the uncompressed size of a plot of size k, in bytes, the same way chia calculates effective plot size
+func expectedPlotSize(k uint8) uint64 {
+	if k == 0 {
+		return 0
+	}
+	return (2*uint64(k) + 1) << (k - 1)
+}
+
+// filterPlotDiscoveryPaths splits plot discovery paths into the ones the plot-discovery init container can see, absolute paths under hostRoot without whitespace,
+// and the ones it would skip
+func filterPlotDiscoveryPaths(hostRoot string, paths []string) ([]string, []string) {
+	root := strings.TrimSuffix(path.Clean(hostRoot), "/") + "/"
+	var allowed, skipped []string
+	for _, p := range paths {
+		if path.IsAbs(p) && strings.HasPrefix(path.Clean(p)+"/", root) && !strings.ContainsAny(p, " \t\n") {
+			allowed = append(allowed, p)
+		} else {
+			skipped = append(skipped, p)
+		}
+	}
+	return allowed, skipped
+}
+
+// getPlotDiscoveryDocument serializes plot discovery ConfigMap data in node name order, so its checksum only changes with its contents
+func getPlotDiscoveryDocument(data map[string]string) string {
+	var nodes []string
+	for node := range data {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+
+	var doc strings.Builder
+	for _, node := range nodes {
+		doc.WriteString(fmt.Sprintf("%s=%s\n", node, data[node]))
+	}
+	return doc.String()
+}
+
+// getPlotDirectoriesSkippedCondition returns the PlotDirectoriesSkipped condition for the plot discovery paths skipped in DaemonSet mode
+func getPlotDirectoriesSkippedCondition(harvester k8schianetv1.ChiaHarvester, skipped []string) metav1.Condition {
+	hostRoot := getPlotDiscoveryConfig(harvester).HostRoot
+	if len(skipped) == 0 {
+		return metav1.Condition{
+			Type:               k8schianetv1.ChiaHarvesterConditionPlotDirectoriesSkipped,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: harvester.Generation,
+			Reason:             "AllUnderHostRoot",
+			Message:            fmt.Sprintf("all plot discovery paths are under %s", hostRoot),
+		}
+	}
+	return metav1.Condition{
+		Type:               k8schianetv1.ChiaHarvesterConditionPlotDirectoriesSkipped,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: harvester.Generation,
+		Reason:             "OutsideHostRoot",
+		Message:            fmt.Sprintf("plot discovery paths aren't absolute paths under %s without spaces: %s", hostRoot, strings.Join(skipped, ", ")),
+	}
+}
+
+// getDaemonSetStorageValidCondition returns the DaemonSetStorageValid condition of a ChiaHarvester in DaemonSet mode. It is false when CHIA_ROOT is a PersistentVolumeClaim.
+// Every pod in the DaemonSet would mount the same claim, which a ReadWriteOnce volume can't be attached for,
+// and each pod's plot-discovery init container would overwrite the other nodes' plot directories in the shared config.yaml
+func getDaemonSetStorageValidCondition(harvester k8schianetv1.ChiaHarvester) metav1.Condition {
+	if harvester.Spec.Storage == nil || harvester.Spec.Storage.ChiaRoot == nil || harvester.Spec.Storage.ChiaRoot.PersistentVolumeClaim == nil {
+		return metav1.Condition{
+			Type:               k8schianetv1.ChiaHarvesterConditionDaemonSetStorageValid,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: harvester.Generation,
+			Reason:             "Valid",
+			Message:            "CHIA_ROOT storage is local to each DaemonSet pod",
+		}
+	}
+	return metav1.Condition{
+		Type:               k8schianetv1.ChiaHarvesterConditionDaemonSetStorageValid,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: harvester.Generation,
+		Reason:             "SharedChiaRootClaim",
+		Message:            "CHIA_ROOT can't be a PersistentVolumeClaim shared by every DaemonSet pod, use a hostPath volume, an ephemeral volume, or no CHIA_ROOT storage",
+	}
+}
+
+// getPlotDiscoveryConfig returns a ChiaHarvester's plot discovery config with defaults filled in
+func getPlotDiscoveryConfig(harvester k8schianetv1.ChiaHarvester) k8schianetv1.PlotDiscoveryConfig {
+	var discovery k8schianetv1.PlotDiscoveryConfig
+	if harvester.Spec.PlotDiscovery != nil {
+		discovery = *harvester.Spec.PlotDiscovery
+	}
+	if discovery.HostRoot == "" {
+		discovery.HostRoot = defaultPlotDiscoveryHostRoot
+	}
+	if discovery.NodeAnnotation == "" {
+		discovery.NodeAnnotation = defaultPlotDiscoveryNodeAnnotation
+	}
+	return discovery
+}
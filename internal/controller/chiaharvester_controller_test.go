@@ -6,13 +6,26 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"time"
 
 	apiv1 "github.com/chia-network/chia-operator/api/v1"
+	"github.com/chia-network/chia-operator/internal/chiarpc"
+	"github.com/chia-network/chia-operator/internal/chiarpc/chiarpctest"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 // +kubebuilder:docs-gen:collapse=Imports
@@ -88,4 +101,468 @@ var _ = Describe("ChiaHarvester controller", func() {
 			Expect(*createdChiaHarvester.Spec.ChiaConfig.Timezone).Should(Equal(timezone))
 		})
 	})
+
+	Context("When totaling ChiaHarvester plot inventories", func() {
+		It("Should group plots by the volume they were found on", func() {
+			pod := corev1.Pod{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "chia",
+							VolumeMounts: []corev1.VolumeMount{
+								{Name: "chiaroot", MountPath: "/chia-data"},
+								{Name: "pvc-plots-0", MountPath: "/plots/pvc-plots-0"},
+								{Name: "hostpath-plots-0", MountPath: "/plots/hostpath-plots-0"},
+							},
+						},
+					},
+				},
+			}
+			plots := chiarpc.Plots{
+				Plots: []chiarpc.Plot{
+					{Filename: "/plots/pvc-plots-0/a.plot", Size: 32, FileSize: 100},
+					{Filename: "/plots/pvc-plots-0/sub/b.plot", Size: 32, FileSize: 100},
+					{Filename: "/plots/hostpath-plots-0/c.plot", Size: 32, FileSize: 50},
+				},
+				FailedToOpenFilenames: []string{"/plots/hostpath-plots-0/d.plot"},
+			}
+
+			status := getPlotsStatus([]podPlots{{pod: pod, plots: plots}})
+			Expect(status.TotalPlots).Should(Equal(int32(3)))
+			Expect(status.FailedToOpen).Should(Equal(int32(1)))
+			Expect(status.RawSize.Value()).Should(Equal(int64(250)))
+			Expect(status.EffectiveSize.Value()).Should(Equal(int64(3 * expectedPlotSize(32))))
+			Expect(status.Directories).Should(HaveLen(2))
+			Expect(status.Directories[0].Volume).Should(Equal("hostpath-plots-0"))
+			Expect(status.Directories[0].Plots).Should(Equal(int32(1)))
+			Expect(status.Directories[1].Volume).Should(Equal("pvc-plots-0"))
+			Expect(status.Directories[1].Plots).Should(Equal(int32(2)))
+			Expect(status.Directories[1].RawSize.Value()).Should(Equal(int64(200)))
+		})
+	})
+
+	Context("When assembling ChiaHarvester plot directories", func() {
+		It("Should mount plot volumes at their configured paths and list them as plot directories", func() {
+			ctx := context.Background()
+			readWrite := false
+			recursive := false
+			harvester := apiv1.ChiaHarvester{
+				Spec: apiv1.ChiaHarvesterSpec{
+					Storage: &apiv1.StorageConfig{
+						Plots: &apiv1.PlotsConfig{
+							HostPathVolume: []*apiv1.HostPathVolumeConfig{
+								{Path: "/mnt/disk1"},
+								{
+									Path: "/mnt/disk2",
+									PlotMountConfig: apiv1.PlotMountConfig{
+										MountPath: "/farm/disk2",
+										SubPath:   "plots",
+										ReadOnly:  &readWrite,
+									},
+								},
+							},
+							RecursiveScan: &recursive,
+						},
+					},
+				},
+			}
+
+			r := &ChiaHarvesterReconciler{}
+			mounts := r.getPlotVolumeMounts(ctx, harvester)
+			Expect(mounts).Should(Equal([]corev1.VolumeMount{
+				{Name: "hostpath-plots-0", ReadOnly: true, MountPath: "/plots/hostpath-plots-0"},
+				{Name: "hostpath-plots-1", ReadOnly: false, MountPath: "/farm/disk2", SubPath: "plots"},
+			}))
+
+			env := r.getChiaEnv(ctx, harvester)
+			Expect(env).Should(ContainElement(corev1.EnvVar{Name: "plots_dir", Value: "/plots/hostpath-plots-0:/farm/disk2"}))
+			Expect(env).Should(ContainElement(corev1.EnvVar{Name: "recursive_plot_scan", Value: "false"}))
+
+			harvester.Spec.Storage.Plots.PlotDirectories = []string{"/farm/disk2/a", "/farm/disk2/b"}
+			env = r.getChiaEnv(ctx, harvester)
+			Expect(env).Should(ContainElement(corev1.EnvVar{Name: "plots_dir", Value: "/farm/disk2/a:/farm/disk2/b"}))
+		})
+	})
+
+	Context("When assembling ChiaHarvester network and ephemeral plot volumes", func() {
+		It("Should create a volume and read-only mount for every configured volume source", func() {
+			ctx := context.Background()
+			directory := corev1.HostPathDirectory
+			harvester := apiv1.ChiaHarvester{
+				Spec: apiv1.ChiaHarvesterSpec{
+					Storage: &apiv1.StorageConfig{
+						Plots: &apiv1.PlotsConfig{
+							HostPathVolume: []*apiv1.HostPathVolumeConfig{
+								{Path: "/mnt/disk1", Type: &directory},
+							},
+							NFSVolume: []*apiv1.NFSVolumeConfig{
+								{Server: "nas.local", Path: "/export/plots"},
+							},
+							EphemeralVolume: []*apiv1.EphemeralVolumeConfig{
+								{StorageClass: "local-storage", ResourceRequest: resource.MustParse("10Ti")},
+							},
+						},
+					},
+				},
+			}
+
+			r := &ChiaHarvesterReconciler{}
+			volumes := r.getChiaVolumes(ctx, harvester)
+			Expect(volumes).Should(ContainElement(corev1.Volume{
+				Name: "hostpath-plots-0",
+				VolumeSource: corev1.VolumeSource{
+					HostPath: &corev1.HostPathVolumeSource{Path: "/mnt/disk1", Type: &directory},
+				},
+			}))
+			Expect(volumes).Should(ContainElement(corev1.Volume{
+				Name: "nfs-plots-0",
+				VolumeSource: corev1.VolumeSource{
+					NFS: &corev1.NFSVolumeSource{Server: "nas.local", Path: "/export/plots", ReadOnly: true},
+				},
+			}))
+			Expect(volumes).Should(ContainElement(HaveField("Name", "ephemeral-plots-0")))
+
+			mounts := r.getPlotVolumeMounts(ctx, harvester)
+			Expect(mounts).Should(Equal([]corev1.VolumeMount{
+				{Name: "hostpath-plots-0", ReadOnly: true, MountPath: "/plots/hostpath-plots-0"},
+				{Name: "nfs-plots-0", ReadOnly: true, MountPath: "/plots/nfs-plots-0"},
+				{Name: "ephemeral-plots-0", ReadOnly: true, MountPath: "/plots/ephemeral-plots-0"},
+			}))
+		})
+
+		It("Should bind each local plot volume to its node through an operator created PersistentVolume and claim", func() {
+			ctx := context.Background()
+			harvester := apiv1.ChiaHarvester{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-chiaharvester", Namespace: "default"},
+				Spec: apiv1.ChiaHarvesterSpec{
+					Storage: &apiv1.StorageConfig{
+						Plots: &apiv1.PlotsConfig{
+							LocalVolume: []*apiv1.LocalVolumeConfig{
+								{Path: "/mnt/disk1", NodeName: "storage-1", Capacity: resource.MustParse("18Ti")},
+							},
+						},
+					},
+				},
+			}
+
+			r := &ChiaHarvesterReconciler{}
+			pvs, pvcs := r.assembleLocalPlotVolumes(ctx, harvester)
+			Expect(pvs).Should(HaveLen(1))
+			Expect(pvcs).Should(HaveLen(1))
+
+			pv := pvs[0]
+			Expect(pv.Name).Should(Equal("default-test-chiaharvester-harvester-local-plots-0"))
+			Expect(pv.Spec.Local.Path).Should(Equal("/mnt/disk1"))
+			Expect(pv.Spec.NodeAffinity.Required.NodeSelectorTerms[0].MatchExpressions).Should(Equal([]corev1.NodeSelectorRequirement{
+				{Key: corev1.LabelHostname, Operator: corev1.NodeSelectorOpIn, Values: []string{"storage-1"}},
+			}))
+			Expect(pv.Spec.PersistentVolumeReclaimPolicy).Should(Equal(corev1.PersistentVolumeReclaimRetain))
+			Expect(pv.Spec.ClaimRef.Namespace).Should(Equal("default"))
+			Expect(pv.Spec.ClaimRef.Name).Should(Equal(pvcs[0].Name))
+
+			pvc := pvcs[0]
+			Expect(pvc.Name).Should(Equal("test-chiaharvester-harvester-local-plots-0"))
+			Expect(pvc.Spec.VolumeName).Should(Equal(pv.Name))
+			Expect(*pvc.Spec.StorageClassName).Should(Equal(pv.Spec.StorageClassName))
+			Expect(pvc.Spec.Resources.Requests.Storage().String()).Should(Equal("18Ti"))
+
+			Expect(r.getChiaVolumes(ctx, harvester)).Should(ContainElement(corev1.Volume{
+				Name: "local-plots-0",
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: pvc.Name, ReadOnly: true},
+				},
+			}))
+			Expect(r.getPlotVolumeMounts(ctx, harvester)).Should(Equal([]corev1.VolumeMount{
+				{Name: "local-plots-0", ReadOnly: true, MountPath: "/plots/local-plots-0"},
+			}))
+		})
+	})
+
+	Context("When reading ChiaHarvester plot counts from the farmer", func() {
+		It("Should query the farmer's RPC Service rather than its peer Service", func() {
+			ctx := context.Background()
+			server, err := chiarpctest.NewServer()
+			Expect(err).NotTo(HaveOccurred())
+			defer server.Close()
+			server.SetResponse("get_harvesters_summary", map[string]interface{}{
+				"harvesters": []map[string]interface{}{
+					{"connection": map[string]interface{}{"host": "10.0.0.1"}, "duplicates": 2, "no_key_filenames": 1},
+					{"connection": map[string]interface{}{"host": "10.0.0.2"}, "duplicates": 5, "no_key_filenames": 5},
+				},
+			})
+
+			// Only the farmer's -rpc Service serves the RPC port
+			var dialed []string
+			originalHostPort := getChiaRPCHostPort
+			defer func() { getChiaRPCHostPort = originalHostPort }()
+			getChiaRPCHostPort = func(host string, port int) (string, int) {
+				dialed = append(dialed, fmt.Sprintf("%s:%d", host, port))
+				if host != "mainnet-farmer-rpc.default.svc.cluster.local" {
+					return "127.0.0.1", 1
+				}
+				return server.HostPort()
+			}
+
+			tlsConfig, err := chiarpc.NewTLSConfigFromSecretData(server.CASecretData())
+			Expect(err).NotTo(HaveOccurred())
+
+			harvester := &apiv1.ChiaHarvester{
+				Spec: apiv1.ChiaHarvesterSpec{
+					ChiaConfig: apiv1.ChiaHarvesterConfigSpec{
+						FarmerAddress: "mainnet-farmer.default.svc.cluster.local",
+					},
+				},
+			}
+			inventories := []podPlots{{pod: corev1.Pod{Status: corev1.PodStatus{PodIP: "10.0.0.1"}}}}
+			status := &apiv1.ChiaHarvesterPlotsStatus{}
+			r := &ChiaHarvesterReconciler{}
+			r.addFarmerPlotCounts(ctx, harvester, tlsConfig, inventories, status)
+			Expect(dialed).Should(Equal([]string{fmt.Sprintf("mainnet-farmer-rpc.default.svc.cluster.local:%d", farmerRPCPort)}))
+			Expect(*status.Duplicates).Should(Equal(int32(2)))
+			Expect(*status.NoKey).Should(Equal(int32(1)))
+
+			Expect(getFarmerRPCAddress(apiv1.ChiaHarvesterConfigSpec{FarmerAddress: "mainnet-farmer"})).Should(Equal("mainnet-farmer-rpc"))
+			Expect(getFarmerRPCAddress(apiv1.ChiaHarvesterConfigSpec{FarmerAddress: "10.0.0.5"})).Should(Equal("10.0.0.5"))
+			Expect(getFarmerRPCAddress(apiv1.ChiaHarvesterConfigSpec{FarmerAddress: "farmer.example.com"})).Should(Equal("farmer.example.com"))
+			rpcAddress := "farmer-rpc.example.com"
+			Expect(getFarmerRPCAddress(apiv1.ChiaHarvesterConfigSpec{FarmerAddress: "farmer.example.com", FarmerRPCAddress: &rpcAddress})).Should(Equal(rpcAddress))
+		})
+	})
+
+	Context("When reporting dropped ChiaHarvester plots", func() {
+		It("Should only compare complete plot counts and keep the condition until the count recovers", func() {
+			harvester := apiv1.ChiaHarvester{ObjectMeta: metav1.ObjectMeta{Generation: 1}}
+			observe := func(total int32, complete bool) *metav1.Condition {
+				status := &apiv1.ChiaHarvesterPlotsStatus{TotalPlots: total}
+				condition, ok := getPlotsDroppedCondition(harvester, harvester.Status.Plots, status, complete)
+				harvester.Status.Plots = status
+				if ok {
+					meta.SetStatusCondition(&harvester.Status.Conditions, condition)
+				}
+				return meta.FindStatusCondition(harvester.Status.Conditions, apiv1.ChiaHarvesterConditionPlotsDropped)
+			}
+
+			Expect(observe(100, true).Status).Should(Equal(metav1.ConditionFalse))
+			Expect(harvester.Status.Plots.ExpectedPlots).Should(Equal(int32(100)))
+
+			// A pod restarting or rolling out reports fewer plots, which isn't a drop
+			Expect(observe(40, false).Status).Should(Equal(metav1.ConditionFalse))
+			Expect(harvester.Status.Plots.ExpectedPlots).Should(Equal(int32(100)))
+
+			// A complete count below the expected count stays dropped until it recovers
+			Expect(observe(90, true).Reason).Should(Equal("PlotCountDecreased"))
+			Expect(observe(90, true).Status).Should(Equal(metav1.ConditionTrue))
+			Expect(observe(95, true).Message).Should(Equal("plot count dropped from 100 to 95"))
+			Expect(observe(100, true).Status).Should(Equal(metav1.ConditionFalse))
+
+			// Spec changes accept the current count
+			observe(90, true)
+			harvester.Generation = 2
+			Expect(observe(90, true).Status).Should(Equal(metav1.ConditionFalse))
+			Expect(harvester.Status.Plots.ExpectedPlots).Should(Equal(int32(90)))
+		})
+
+		It("Should only consider workloads with every pod updated and ready as settled", func() {
+			replicas := int32(1)
+			deploy := appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Generation: 3},
+				Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+				Status:     appsv1.DeploymentStatus{ObservedGeneration: 3, Replicas: 1, UpdatedReplicas: 1, ReadyReplicas: 1},
+			}
+			expected, settled := deploymentSettled(deploy)
+			Expect(expected).Should(Equal(int32(1)))
+			Expect(settled).Should(BeTrue())
+			deploy.Status.Replicas = 2
+			_, settled = deploymentSettled(deploy)
+			Expect(settled).Should(BeFalse())
+
+			ds := appsv1.DaemonSet{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Status: appsv1.DaemonSetStatus{
+					ObservedGeneration:     2,
+					DesiredNumberScheduled: 3,
+					CurrentNumberScheduled: 3,
+					UpdatedNumberScheduled: 2,
+					NumberReady:            3,
+				},
+			}
+			expected, settled = daemonSetSettled(ds)
+			Expect(expected).Should(Equal(int32(3)))
+			Expect(settled).Should(BeFalse())
+			ds.Status.UpdatedNumberScheduled = 3
+			_, settled = daemonSetSettled(ds)
+			Expect(settled).Should(BeTrue())
+		})
+	})
+
+	Context("When discovering ChiaHarvester plot directories in DaemonSet mode", func() {
+		It("Should only pass on plot directories under the host root and report the rest", func() {
+			harvester := apiv1.ChiaHarvester{
+				ObjectMeta: metav1.ObjectMeta{Name: chiaHarvesterName, Namespace: chiaHarvesterNamespace, Generation: 1},
+				Spec: apiv1.ChiaHarvesterSpec{
+					Mode:         apiv1.ChiaHarvesterModeDaemonSet,
+					NodeSelector: map[string]string{"chia.net/storage-node": "true"},
+					PlotDiscovery: &apiv1.PlotDiscoveryConfig{
+						PathGlobs: []string{"/mnt/disk*/plots", "/plots/*"},
+					},
+				},
+			}
+			r := &ChiaHarvesterReconciler{Client: fake.NewClientBuilder().WithObjects(
+				&corev1.Node{ObjectMeta: metav1.ObjectMeta{
+					Name:        "storage-1",
+					Labels:      map[string]string{"chia.net/storage-node": "true"},
+					Annotations: map[string]string{defaultPlotDiscoveryNodeAnnotation: "/mnt/jbod1, /mnt/../etc,/mnt2/jbod2"},
+				}},
+				&corev1.Node{ObjectMeta: metav1.ObjectMeta{
+					Name:        "storage-2",
+					Labels:      map[string]string{"chia.net/storage-node": "true"},
+					Annotations: map[string]string{defaultPlotDiscoveryNodeAnnotation: "jbod3"},
+				}},
+			).Build()}
+
+			data, skipped, err := r.getPlotDiscoveryData(context.TODO(), harvester)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(data).Should(Equal(map[string]string{"storage-1": "/mnt/jbod1"}))
+			Expect(skipped).Should(ConsistOf("/plots/*", "storage-1:/mnt/../etc", "storage-1:/mnt2/jbod2", "storage-2:jbod3"))
+
+			condition := getPlotDirectoriesSkippedCondition(harvester, skipped)
+			Expect(condition.Status).Should(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).Should(Equal("OutsideHostRoot"))
+			Expect(getPlotDirectoriesSkippedCondition(harvester, nil).Status).Should(Equal(metav1.ConditionFalse))
+
+			ds := r.assembleDaemonSet(context.TODO(), harvester, map[string]string{}, "", data)
+			Expect(ds.Spec.Template.Spec.InitContainers[0].Env).Should(ContainElement(corev1.EnvVar{Name: "PLOT_GLOBS", Value: "/mnt/disk*/plots"}))
+		})
+
+		It("Should restart DaemonSet pods when node plot directories change", func() {
+			harvester := apiv1.ChiaHarvester{
+				ObjectMeta: metav1.ObjectMeta{Name: chiaHarvesterName, Namespace: chiaHarvesterNamespace},
+				Spec:       apiv1.ChiaHarvesterSpec{Mode: apiv1.ChiaHarvesterModeDaemonSet},
+			}
+			r := &ChiaHarvesterReconciler{}
+			checksum := func(data map[string]string) string {
+				return r.assembleDaemonSet(context.TODO(), harvester, map[string]string{}, "", data).Spec.Template.Annotations[plotDiscoveryChecksumAnnotation]
+			}
+
+			before := checksum(map[string]string{"storage-1": "/mnt/jbod1", "storage-2": "/mnt/jbod2"})
+			Expect(before).ShouldNot(BeEmpty())
+			Expect(checksum(map[string]string{"storage-2": "/mnt/jbod2", "storage-1": "/mnt/jbod1"})).Should(Equal(before))
+			Expect(checksum(map[string]string{"storage-1": "/mnt/jbod1,/mnt/jbod3", "storage-2": "/mnt/jbod2"})).ShouldNot(Equal(before))
+		})
+
+		It("Should report a CHIA_ROOT PersistentVolumeClaim shared by every DaemonSet pod in the DaemonSetStorageValid condition", func() {
+			harvester := apiv1.ChiaHarvester{
+				Spec: apiv1.ChiaHarvesterSpec{Mode: apiv1.ChiaHarvesterModeDaemonSet},
+			}
+			Expect(getDaemonSetStorageValidCondition(harvester).Status).Should(Equal(metav1.ConditionTrue))
+
+			harvester.Spec.Storage = &apiv1.StorageConfig{ChiaRoot: &apiv1.ChiaRootConfig{
+				HostPathVolume: &apiv1.HostPathVolumeConfig{Path: "/var/lib/chia"},
+			}}
+			Expect(getDaemonSetStorageValidCondition(harvester).Status).Should(Equal(metav1.ConditionTrue))
+
+			harvester.Spec.Storage.ChiaRoot.PersistentVolumeClaim = &apiv1.PersistentVolumeClaimConfig{ClaimName: "harvester-chiaroot"}
+			condition := getDaemonSetStorageValidCondition(harvester)
+			Expect(condition.Status).Should(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).Should(Equal("SharedChiaRootClaim"))
+		})
+
+		It("Should pass discovered plot directories to yq as values rather than part of its expression", func() {
+			dir := GinkgoT().TempDir()
+			plots := filepath.Join(dir, `plots"]|.x=["`)
+			Expect(os.MkdirAll(plots, 0755)).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(dir, "discovery"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "discovery", "storage-1"), []byte(plots), 0644)).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(dir, "chia-data", "config"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "chia-data", "config", "config.yaml"), []byte("harvester: {}\n"), 0644)).To(Succeed())
+
+			// Stands in for yq, recording each expression and the PLOT_DIRECTORY it was run with
+			Expect(os.MkdirAll(filepath.Join(dir, "bin"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "bin", "yq"), []byte("#!/bin/sh\nprintf '%s|%s\\n' \"$2\" \"${PLOT_DIRECTORY}\" >> \""+filepath.Join(dir, "yq.log")+"\"\n"), 0755)).To(Succeed())
+
+			script := strings.ReplaceAll(plotDiscoveryScript, plotDiscoveryPath, filepath.Join(dir, "discovery"))
+			cmd := exec.Command("/bin/sh", "-c", script)
+			cmd.Env = append(os.Environ(), "PATH="+filepath.Join(dir, "bin")+":"+os.Getenv("PATH"), "CHIA_ROOT="+filepath.Join(dir, "chia-data"), "NODE_NAME=storage-1", "PLOT_GLOBS=")
+			out, err := cmd.CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), string(out))
+
+			log, err := os.ReadFile(filepath.Join(dir, "yq.log"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(strings.Split(strings.TrimSpace(string(log)), "\n")).Should(Equal([]string{
+				".harvester.plot_directories = []|",
+				".harvester.plot_directories += [strenv(PLOT_DIRECTORY)]|" + plots,
+			}))
+		})
+	})
+
+	Context("When monitoring a ChiaHarvester with the Prometheus Operator", func() {
+		It("Should render a ServiceMonitor for the metrics Service and a PrometheusRule with the default alerts", func() {
+			ctx := context.Background()
+			harvester := apiv1.ChiaHarvester{
+				ObjectMeta: metav1.ObjectMeta{Name: chiaHarvesterName, Namespace: chiaHarvesterNamespace},
+				Spec: apiv1.ChiaHarvesterSpec{
+					AdditionalMetadata: apiv1.AdditionalMetadata{Labels: map[string]string{"team": "farm"}},
+					ChiaExporterConfig: apiv1.ChiaExporterConfigSpec{
+						ServiceLabels: map[string]string{"key": "value"},
+						ServiceMonitor: &apiv1.ChiaExporterServiceMonitorSpec{
+							Enabled:  true,
+							Labels:   map[string]string{"release": "prometheus"},
+							Interval: "30s",
+						},
+						PrometheusRule: &apiv1.ChiaExporterPrometheusRuleSpec{
+							Enabled:     true,
+							AlertLabels: map[string]string{"severity": "warning"},
+						},
+					},
+				},
+			}
+			r := &ChiaHarvesterReconciler{}
+			Expect(serviceMonitorEnabled(harvester.Spec.ChiaExporterConfig)).Should(BeTrue())
+			Expect(prometheusRuleEnabled(harvester.Spec.ChiaExporterConfig)).Should(BeTrue())
+
+			monitor := r.assembleChiaExporterServiceMonitor(ctx, harvester)
+			Expect(monitor.GetKind()).Should(Equal("ServiceMonitor"))
+			Expect(monitor.GetLabels()).Should(HaveKeyWithValue("release", "prometheus"))
+			endpoints, _, _ := unstructured.NestedSlice(monitor.Object, "spec", "endpoints")
+			Expect(endpoints).Should(Equal([]interface{}{map[string]interface{}{"port": "metrics", "path": "/metrics", "interval": "30s"}}))
+
+			// The ServiceMonitor must select the metrics Service, and only it
+			srv := r.assembleChiaExporterService(ctx, harvester)
+			selector, _, _ := unstructured.NestedStringMap(monitor.Object, "spec", "selector", "matchLabels")
+			for k, v := range selector {
+				Expect(srv.Labels).Should(HaveKeyWithValue(k, v))
+			}
+			Expect(selector).ShouldNot(Equal(r.getCommonLabels(ctx, harvester)))
+
+			rule := r.assembleChiaExporterPrometheusRule(ctx, harvester)
+			Expect(rule.GetKind()).Should(Equal("PrometheusRule"))
+			groups, _, _ := unstructured.NestedSlice(rule.Object, "spec", "groups")
+			Expect(groups).Should(HaveLen(1))
+			rules := groups[0].(map[string]interface{})["rules"].([]interface{})
+			var alerts []string
+			for _, rule := range rules {
+				alert := rule.(map[string]interface{})
+				alerts = append(alerts, alert["alert"].(string))
+				Expect(alert["expr"]).Should(ContainSubstring(fmt.Sprintf(`service="%s"`, srv.Name)))
+				Expect(alert["labels"]).Should(HaveKeyWithValue("severity", "warning"))
+			}
+			Expect(alerts).Should(Equal([]string{"ChiaExporterDown", "ChiaHarvesterPlotsDropped"}))
+
+			// Monitoring resources aren't rendered without the chia-exporter sidecar
+			disabled := false
+			harvester.Spec.ChiaExporterConfig.Enabled = &disabled
+			Expect(serviceMonitorEnabled(harvester.Spec.ChiaExporterConfig)).Should(BeFalse())
+			Expect(prometheusRuleEnabled(harvester.Spec.ChiaExporterConfig)).Should(BeFalse())
+		})
+
+		It("Should skip monitoring resources when the Prometheus Operator CRDs aren't installed", func() {
+			c := fake.NewClientBuilder().Build()
+			monitor := (&ChiaHarvesterReconciler{}).assembleChiaExporterServiceMonitor(context.TODO(), apiv1.ChiaHarvester{
+				ObjectMeta: metav1.ObjectMeta{Name: chiaHarvesterName, Namespace: chiaHarvesterNamespace},
+			})
+			res, err := reconcileOptionalResource(context.TODO(), c, nil, monitor, true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(BeNil())
+		})
+	})
 })
@@ -0,0 +1,304 @@
+/*
+Copyright 2023 Chia Network Inc.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/yaml"
+
+	k8schianetv1 "github.com/chia-network/chia-operator/api/v1"
+	"github.com/chia-network/chia-operator/internal/chiarpc"
+	"github.com/cisco-open/operator-tools/pkg/reconciler"
+)
+
+const (
+	// harvesterCertKey is the bundle Secret data key containing the harvester's private certificate
+	harvesterCertKey = "private_harvester.crt"
+
+	// harvesterKeyKey is the bundle Secret data key containing the harvester's private key
+	harvesterKeyKey = "private_harvester.key"
+
+	// harvesterConfigKey is the bundle Secret data key containing the config.yaml snippet pointing the harvester at its farmer
+	harvesterConfigKey = "harvester-config.yaml"
+)
+
+// ChiaHarvesterBundleReconciler reconciles a ChiaHarvesterBundle object
+type ChiaHarvesterBundleReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=k8s.chia.net,resources=chiaharvesterbundles,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=k8s.chia.net,resources=chiaharvesterbundles/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=k8s.chia.net,resources=chiaharvesterbundles/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch
+
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.14.4/pkg/reconcile
+func (r *ChiaHarvesterBundleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+	resourceReconciler := reconciler.NewReconcilerWith(r.Client, reconciler.WithLog(log))
+	log.Info(fmt.Sprintf("ChiaHarvesterBundleReconciler ChiaHarvesterBundle=%s", req.NamespacedName.String()))
+
+	// Get the custom resource
+	var bundle k8schianetv1.ChiaHarvesterBundle
+	err := r.Get(ctx, req.NamespacedName, &bundle)
+	if err != nil && errors.IsNotFound(err) {
+		// Return here, this can happen if the CR was deleted
+		return ctrl.Result{}, nil
+	}
+	if err != nil {
+		log.Error(err, fmt.Sprintf("ChiaHarvesterBundleReconciler ChiaHarvesterBundle=%s unable to fetch ChiaHarvesterBundle resource", req.NamespacedName))
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	host, condition, err := r.getFarmerPeerHost(ctx, bundle)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("ChiaHarvesterBundleReconciler ChiaHarvesterBundle=%s encountered error resolving farmer address: %v", req.NamespacedName, err)
+	}
+	meta.SetStatusCondition(&bundle.Status.Conditions, condition)
+	if host == "" {
+		// The farmer Service is watched, so the bundle is reconciled again once the Service changes
+		log.Info(fmt.Sprintf("ChiaHarvesterBundleReconciler ChiaHarvesterBundle=%s waiting for farmer address: %s", req.NamespacedName, condition.Message))
+		bundle.Status.Ready = false
+		err = r.Status().Update(ctx, &bundle)
+		if err != nil {
+			log.Error(err, fmt.Sprintf("ChiaHarvesterBundleReconciler ChiaHarvesterBundle=%s unable to update ChiaHarvesterBundle status", req.NamespacedName))
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	var ca corev1.Secret
+	err = r.Get(ctx, types.NamespacedName{Namespace: bundle.Namespace, Name: bundle.Spec.CASecretName}, &ca)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("ChiaHarvesterBundleReconciler ChiaHarvesterBundle=%s encountered error fetching CA Secret: %v", req.NamespacedName, err)
+	}
+
+	var existing corev1.Secret
+	err = r.Get(ctx, types.NamespacedName{Namespace: bundle.Namespace, Name: bundle.Spec.Secret}, &existing)
+	if err != nil && !errors.IsNotFound(err) {
+		return ctrl.Result{}, fmt.Errorf("ChiaHarvesterBundleReconciler ChiaHarvesterBundle=%s encountered error fetching bundle Secret: %v", req.NamespacedName, err)
+	}
+
+	data, err := getHarvesterBundleSecretData(ca.Data, existing.Data, host, bundle.Spec.FarmerPeer.Port)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("ChiaHarvesterBundleReconciler ChiaHarvesterBundle=%s encountered error assembling bundle Secret data: %v", req.NamespacedName, err)
+	}
+	secret := r.assembleBundleSecret(ctx, bundle, data)
+	res, err := reconcileSecret(ctx, resourceReconciler, secret)
+	if err != nil {
+		if res == nil {
+			res = &reconcile.Result{}
+		}
+		return *res, fmt.Errorf("ChiaHarvesterBundleReconciler ChiaHarvesterBundle=%s encountered error reconciling bundle Secret: %v", req.NamespacedName, err)
+	}
+
+	// Update CR status
+	bundle.Status.Ready = true
+	bundle.Status.FarmerPeer = net.JoinHostPort(host, strconv.Itoa(int(bundle.Spec.FarmerPeer.Port)))
+	err = r.Status().Update(ctx, &bundle)
+	if err != nil {
+		log.Error(err, fmt.Sprintf("ChiaHarvesterBundleReconciler ChiaHarvesterBundle=%s unable to update ChiaHarvesterBundle status", req.NamespacedName))
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ChiaHarvesterBundleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		// Only spec changes trigger reconciles, status updates while waiting for the farmer address would otherwise requeue the bundle immediately
+		For(&k8schianetv1.ChiaHarvesterBundle{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Owns(&corev1.Secret{}).
+		Watches(
+			&corev1.Service{},
+			handler.EnqueueRequestsFromMapFunc(r.bundlesForFarmerService),
+		).
+		Complete(r)
+}
+
+// bundlesForFarmerService returns a reconcile request for every ChiaHarvesterBundle using the ChiaFarmer whose peer Service this is,
+// so bundles pick up the farmer's load balancer address when it's assigned or changes
+func (r *ChiaHarvesterBundleReconciler) bundlesForFarmerService(ctx context.Context, srv client.Object) []reconcile.Request {
+	if !strings.HasSuffix(srv.GetName(), "-farmer") {
+		return nil
+	}
+
+	var bundles k8schianetv1.ChiaHarvesterBundleList
+	err := r.List(ctx, &bundles, client.InNamespace(srv.GetNamespace()))
+	if err != nil {
+		log.FromContext(ctx).Error(err, fmt.Sprintf("ChiaHarvesterBundleReconciler unable to list ChiaHarvesterBundles for Service %s", srv.GetName()))
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, bundle := range bundles.Items {
+		if bundle.Spec.FarmerPeer.Host == "" && bundle.Spec.FarmerPeer.ChiaFarmerName != "" && fmt.Sprintf("%s-farmer", bundle.Spec.FarmerPeer.ChiaFarmerName) == srv.GetName() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: bundle.Namespace, Name: bundle.Name},
+			})
+		}
+	}
+	return requests
+}
+
+// assembleBundleSecret assembles the Secret resource holding the harvester's certificates and config for a ChiaHarvesterBundle CR
+func (r *ChiaHarvesterBundleReconciler) assembleBundleSecret(ctx context.Context, bundle k8schianetv1.ChiaHarvesterBundle, data map[string][]byte) corev1.Secret {
+	return corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            bundle.Spec.Secret,
+			Namespace:       bundle.Namespace,
+			Labels:          r.getCommonLabels(ctx, bundle, bundle.Spec.AdditionalMetadata.Labels),
+			Annotations:     bundle.Spec.AdditionalMetadata.Annotations,
+			OwnerReferences: r.getOwnerReference(ctx, bundle),
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: data,
+	}
+}
+
+// getFarmerPeerHost returns the farmer's external host, from the spec or from the load balancer of the referenced ChiaFarmer's Service,
+// and the FarmerPeerResolved condition for it. An empty host is returned with the reason in the condition while the Service is missing,
+// isn't a LoadBalancer, or has no load balancer address yet
+func (r *ChiaHarvesterBundleReconciler) getFarmerPeerHost(ctx context.Context, bundle k8schianetv1.ChiaHarvesterBundle) (string, metav1.Condition, error) {
+	condition := metav1.Condition{
+		Type:               k8schianetv1.ChiaHarvesterBundleConditionFarmerPeerResolved,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: bundle.Generation,
+		Reason:             "HostSet",
+		Message:            "farmerPeer.host is set",
+	}
+	if bundle.Spec.FarmerPeer.Host != "" {
+		return bundle.Spec.FarmerPeer.Host, condition, nil
+	}
+	if bundle.Spec.FarmerPeer.ChiaFarmerName == "" {
+		return "", condition, fmt.Errorf("one of farmerPeer.host or farmerPeer.chiaFarmerName must be set")
+	}
+
+	name := fmt.Sprintf("%s-farmer", bundle.Spec.FarmerPeer.ChiaFarmerName)
+	condition.Status = metav1.ConditionFalse
+	var srv corev1.Service
+	err := r.Get(ctx, types.NamespacedName{Namespace: bundle.Namespace, Name: name}, &srv)
+	if errors.IsNotFound(err) {
+		condition.Reason = "ServiceNotFound"
+		condition.Message = fmt.Sprintf("Service %s for ChiaFarmer %s doesn't exist", name, bundle.Spec.FarmerPeer.ChiaFarmerName)
+		return "", condition, nil
+	}
+	if err != nil {
+		return "", condition, err
+	}
+	if srv.Spec.Type != corev1.ServiceTypeLoadBalancer {
+		condition.Reason = "NotLoadBalancer"
+		condition.Message = fmt.Sprintf("Service %s is type %s, set the ChiaFarmer's peer serviceType to LoadBalancer or set farmerPeer.host", name, srv.Spec.Type)
+		return "", condition, nil
+	}
+	for _, ingress := range srv.Status.LoadBalancer.Ingress {
+		host := ingress.IP
+		if host == "" {
+			host = ingress.Hostname
+		}
+		if host != "" {
+			condition.Status = metav1.ConditionTrue
+			condition.Reason = "LoadBalancerAssigned"
+			condition.Message = fmt.Sprintf("using Service %s's load balancer address", name)
+			return host, condition, nil
+		}
+	}
+	condition.Reason = "WaitingForLoadBalancer"
+	condition.Message = fmt.Sprintf("Service %s hasn't been assigned a load balancer address yet", name)
+	return "", condition, nil
+}
+
+// getHarvesterBundleSecretData assembles the bundle Secret's data: the private CA certificate, a harvester certificate signed by that CA, and a config.yaml snippet for the farmer peer.
+// The harvester certificate in the existing data is kept unless it was signed by a different CA, so reconciling doesn't invalidate already onboarded harvesters
+func getHarvesterBundleSecretData(caData, existing map[string][]byte, host string, port int32) (map[string][]byte, error) {
+	caCert, ok := caData[chiarpc.PrivateCACertKey]
+	if !ok {
+		return nil, fmt.Errorf("CA Secret is missing the %s key", chiarpc.PrivateCACertKey)
+	}
+	caKey, ok := caData[chiarpc.PrivateCAKeyKey]
+	if !ok {
+		return nil, fmt.Errorf("CA Secret is missing the %s key", chiarpc.PrivateCAKeyKey)
+	}
+
+	cert, key := existing[harvesterCertKey], existing[harvesterKeyKey]
+	if len(cert) == 0 || len(key) == 0 || !bytes.Equal(existing[chiarpc.PrivateCACertKey], caCert) {
+		var err error
+		cert, key, err = chiarpc.GenerateCertificate(caCert, caKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	config, err := yaml.Marshal(map[string]interface{}{
+		"harvester": map[string]interface{}{
+			"farmer_peers": []map[string]interface{}{
+				{
+					"host": host,
+					"port": port,
+				},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string][]byte{
+		chiarpc.PrivateCACertKey: caCert,
+		harvesterCertKey:         cert,
+		harvesterKeyKey:          key,
+		harvesterConfigKey:       config,
+	}, nil
+}
+
+// getCommonLabels gives some common labels for ChiaHarvesterBundle related objects
+func (r *ChiaHarvesterBundleReconciler) getCommonLabels(ctx context.Context, bundle k8schianetv1.ChiaHarvesterBundle, additionalLabels ...map[string]string) map[string]string {
+	var labels = make(map[string]string)
+	for _, addition := range additionalLabels {
+		for k, v := range addition {
+			labels[k] = v
+		}
+	}
+	labels["app.kubernetes.io/instance"] = bundle.Name
+	labels["chiaharvesterbundle-owner"] = bundle.Name
+	labels = getCommonLabels(ctx, labels)
+	return labels
+}
+
+// getOwnerReference gives the common owner reference spec for ChiaHarvesterBundle related objects
+func (r *ChiaHarvesterBundleReconciler) getOwnerReference(ctx context.Context, bundle k8schianetv1.ChiaHarvesterBundle) []metav1.OwnerReference {
+	return []metav1.OwnerReference{
+		{
+			APIVersion: bundle.APIVersion,
+			Kind:       bundle.Kind,
+			Name:       bundle.Name,
+			UID:        bundle.UID,
+			Controller: &controllerOwner,
+		},
+	}
+}
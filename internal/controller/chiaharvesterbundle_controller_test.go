@@ -0,0 +1,174 @@
+/*
+Copyright 2023 Chia Network Inc.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"time"
+
+	apiv1 "github.com/chia-network/chia-operator/api/v1"
+	"github.com/chia-network/chia-operator/internal/chiarpc"
+	"github.com/chia-network/chia-operator/internal/chiarpc/chiarpctest"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// +kubebuilder:docs-gen:collapse=Imports
+
+var _ = Describe("ChiaHarvesterBundle controller", func() {
+	const (
+		chiaHarvesterBundleName      = "test-chiaharvesterbundle"
+		chiaHarvesterBundleNamespace = "default"
+
+		timeout  = time.Second * 10
+		duration = time.Second * 10
+		interval = time.Millisecond * 250
+	)
+	var (
+		caSecretName     = "test-secret"
+		bundleSecretName = "test-bundle-secret"
+		farmerHost       = "farmer.example.com"
+	)
+
+	Context("When creating a ChiaHarvesterBundle", func() {
+		It("Should create the ChiaHarvesterBundle", func() {
+			By("By creating a new ChiaHarvesterBundle")
+			ctx := context.Background()
+			bundle := &apiv1.ChiaHarvesterBundle{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "k8s.chia.net/v1",
+					Kind:       "ChiaHarvesterBundle",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      chiaHarvesterBundleName,
+					Namespace: chiaHarvesterBundleNamespace,
+				},
+				Spec: apiv1.ChiaHarvesterBundleSpec{
+					CASecretName: caSecretName,
+					Secret:       bundleSecretName,
+					FarmerPeer: apiv1.ChiaHarvesterBundleFarmerPeerSpec{
+						Host: farmerHost,
+					},
+				},
+			}
+
+			// Create ChiaHarvesterBundle
+			Expect(k8sClient.Create(ctx, bundle)).Should(Succeed())
+
+			// Look up the created ChiaHarvesterBundle
+			lookupKey := types.NamespacedName{Name: chiaHarvesterBundleName, Namespace: chiaHarvesterBundleNamespace}
+			createdChiaHarvesterBundle := &apiv1.ChiaHarvesterBundle{}
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, lookupKey, createdChiaHarvesterBundle)
+				return err == nil
+			}, timeout, interval).Should(BeTrue())
+
+			// Ensure the ChiaHarvesterBundle's farmer port was defaulted
+			Expect(createdChiaHarvesterBundle.Spec.FarmerPeer.Port).Should(Equal(int32(8447)))
+		})
+	})
+
+	Context("When assembling a ChiaHarvesterBundle Secret", func() {
+		It("Should sign a harvester certificate without including the CA key, and keep it across reconciles", func() {
+			server, err := chiarpctest.NewServer()
+			Expect(err).NotTo(HaveOccurred())
+			defer server.Close()
+			caData := server.CASecretData()
+
+			data, err := getHarvesterBundleSecretData(caData, nil, farmerHost, 8447)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(data).ShouldNot(HaveKey(chiarpc.PrivateCAKeyKey))
+			Expect(data[chiarpc.PrivateCACertKey]).Should(Equal(caData[chiarpc.PrivateCACertKey]))
+			Expect(string(data[harvesterConfigKey])).Should(Equal("harvester:\n  farmer_peers:\n  - host: farmer.example.com\n    port: 8447\n"))
+
+			// The harvester certificate must chain to the CA it was signed by
+			roots := x509.NewCertPool()
+			Expect(roots.AppendCertsFromPEM(caData[chiarpc.PrivateCACertKey])).Should(BeTrue())
+			block, _ := pem.Decode(data[harvesterCertKey])
+			Expect(block).NotTo(BeNil())
+			cert, err := x509.ParseCertificate(block.Bytes)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = cert.Verify(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}})
+			Expect(err).NotTo(HaveOccurred())
+
+			again, err := getHarvesterBundleSecretData(caData, data, "10.0.0.1", 8447)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(again[harvesterCertKey]).Should(Equal(data[harvesterCertKey]))
+			Expect(again[harvesterKeyKey]).Should(Equal(data[harvesterKeyKey]))
+			Expect(string(again[harvesterConfigKey])).Should(ContainSubstring("host: 10.0.0.1"))
+
+			other, err := chiarpctest.NewServer()
+			Expect(err).NotTo(HaveOccurred())
+			defer other.Close()
+			rotated, err := getHarvesterBundleSecretData(other.CASecretData(), data, farmerHost, 8447)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rotated[harvesterCertKey]).ShouldNot(Equal(data[harvesterCertKey]))
+		})
+	})
+
+	Context("When resolving a ChiaHarvesterBundle's farmer address from a ChiaFarmer", func() {
+		It("Should report why the farmer Service has no load balancer address, and reconcile bundles when it changes", func() {
+			testScheme := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(testScheme)).To(Succeed())
+			Expect(apiv1.AddToScheme(testScheme)).To(Succeed())
+
+			bundle := apiv1.ChiaHarvesterBundle{
+				ObjectMeta: metav1.ObjectMeta{Name: chiaHarvesterBundleName, Namespace: chiaHarvesterBundleNamespace, Generation: 1},
+				Spec: apiv1.ChiaHarvesterBundleSpec{
+					FarmerPeer: apiv1.ChiaHarvesterBundleFarmerPeerSpec{ChiaFarmerName: "mainnet"},
+				},
+			}
+			srv := &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "mainnet-farmer", Namespace: chiaHarvesterBundleNamespace},
+				Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP},
+			}
+			resolve := func(objs ...client.Object) (string, metav1.Condition) {
+				r := &ChiaHarvesterBundleReconciler{Client: fake.NewClientBuilder().WithScheme(testScheme).WithObjects(objs...).Build()}
+				host, condition, err := r.getFarmerPeerHost(context.TODO(), bundle)
+				Expect(err).NotTo(HaveOccurred())
+				return host, condition
+			}
+
+			host, condition := resolve()
+			Expect(host).Should(BeEmpty())
+			Expect(condition.Status).Should(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).Should(Equal("ServiceNotFound"))
+
+			_, condition = resolve(srv.DeepCopy())
+			Expect(condition.Reason).Should(Equal("NotLoadBalancer"))
+			Expect(condition.Message).Should(ContainSubstring("is type ClusterIP"))
+
+			srv.Spec.Type = corev1.ServiceTypeLoadBalancer
+			_, condition = resolve(srv.DeepCopy())
+			Expect(condition.Reason).Should(Equal("WaitingForLoadBalancer"))
+
+			srv.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{Hostname: farmerHost}}
+			host, condition = resolve(srv.DeepCopy())
+			Expect(host).Should(Equal(farmerHost))
+			Expect(condition.Status).Should(Equal(metav1.ConditionTrue))
+
+			other := bundle.DeepCopy()
+			other.Name = "other-bundle"
+			other.Spec.FarmerPeer.ChiaFarmerName = "testnet"
+			r := &ChiaHarvesterBundleReconciler{Client: fake.NewClientBuilder().WithScheme(testScheme).WithObjects(bundle.DeepCopy(), other).Build()}
+			Expect(r.bundlesForFarmerService(context.TODO(), srv)).Should(Equal([]reconcile.Request{
+				{NamespacedName: types.NamespacedName{Namespace: chiaHarvesterBundleNamespace, Name: chiaHarvesterBundleName}},
+			}))
+			Expect(r.bundlesForFarmerService(context.TODO(), &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "mainnet-farmer-rpc", Namespace: chiaHarvesterBundleNamespace},
+			})).Should(BeEmpty())
+		})
+	})
+})
@@ -6,21 +6,38 @@ package controller
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/yaml"
 
 	k8schianetv1 "github.com/chia-network/chia-operator/api/v1"
+	"github.com/chia-network/chia-operator/internal/chiarpc"
 	"github.com/cisco-open/operator-tools/pkg/reconciler"
 )
 
@@ -35,10 +52,76 @@ const (
 	nodeRPCPort = 8555
 )
 
+const (
+	// databaseBootstrapContainerName is the name of the init container that restores a ChiaNode's blockchain database
+	databaseBootstrapContainerName = "db-bootstrap"
+
+	// databaseBootstrapPath is the directory a PersistentVolumeClaim database bootstrap source is mounted to
+	databaseBootstrapPath = "/db-bootstrap"
+
+	// defaultDatabaseBootstrapImage is the default image of the database bootstrap init container
+	defaultDatabaseBootstrapImage = "alpine:latest"
+
+	// defaultDatabasePath is chia's default full_node database_path, relative to CHIA_ROOT
+	defaultDatabasePath = "db/blockchain_v2_CHALLENGE.sqlite"
+
+	// databaseBootstrapScript restores a blockchain database into CHIA_ROOT/db if there isn't one yet. The database is staged and verified
+	// in a working directory on the same volume, so an interrupted restore is retried from scratch and never leaves a partial database behind.
+	// The outcome is written to the termination log prefixed with Skipped, Completed or Failed so it can be reported in the ChiaNode's status
+	databaseBootstrapScript = `set -e
+db_dir="${CHIA_ROOT}/db"
+if ls "${db_dir}"/blockchain_v2_*.sqlite >/dev/null 2>&1; then
+  echo "Skipped: ${db_dir} already has a blockchain database" | tee /dev/termination-log
+  exit 0
+fi
+work="${db_dir}/.bootstrap"
+rm -rf "${work}"
+mkdir -p "${work}"
+if [ -n "${BOOTSTRAP_URL}" ]; then
+  echo "Downloading ${BOOTSTRAP_URL}"
+  wget -q -O "${work}/archive" "${BOOTSTRAP_URL}"
+  verify="${work}/archive"
+  archive="${verify}"
+else
+  echo "Copying ${BOOTSTRAP_PATH}"
+  cp "${BOOTSTRAP_PATH}" "${work}/"
+  verify="${work}/$(basename "${BOOTSTRAP_PATH}")"
+  case "${verify}" in
+    *.sqlite) archive="" ;;
+    *) archive="${verify}" ;;
+  esac
+fi
+if [ -n "${BOOTSTRAP_SHA256}" ]; then
+  echo "Verifying SHA-256 checksum of ${verify}"
+  if ! echo "${BOOTSTRAP_SHA256}  ${verify}" | sha256sum -c -; then
+    echo "Failed: SHA-256 checksum mismatch" | tee /dev/termination-log
+    exit 1
+  fi
+fi
+if [ -n "${archive}" ]; then
+  echo "Extracting ${archive}"
+  tar -xf "${archive}" -C "${work}"
+  rm "${archive}"
+fi
+db=$(find "${work}" -name 'blockchain_v2_*.sqlite' | head -n 1)
+if [ -z "${db}" ]; then
+  echo "Failed: no blockchain_v2_*.sqlite database found" | tee /dev/termination-log
+  exit 1
+fi
+mv "${db}" "${db_dir}/"
+rm -rf "${work}"
+echo "Completed: restored $(basename "${db}")" | tee /dev/termination-log
+`
+)
+
+// databaseVersionRegexp matches the schema version in a blockchain database file name
+var databaseVersionRegexp = regexp.MustCompile(`^blockchain_v(\d+)_`)
+
 // ChiaNodeReconciler reconciles a ChiaNode object
 type ChiaNodeReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
 }
 
 //+kubebuilder:rbac:groups=k8s.chia.net,resources=chianodes,verbs=get;list;watch;create;update;patch;delete
@@ -46,6 +129,15 @@ type ChiaNodeReconciler struct {
 //+kubebuilder:rbac:groups=k8s.chia.net,resources=chianodes/finalizers,verbs=update
 //+kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch
 //+kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=tcproutes;tlsroutes,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors;prometheusrules,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.14.4/pkg/reconcile
@@ -66,9 +158,30 @@ func (r *ChiaNodeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	// The CHIA_ROOT storage request is parsed once for the volumeClaimTemplate and volume expansion. An invalid one is a spec error,
+	// so it is reported in status and the ChiaNode is reconciled again when its spec changes
+	chiaRootRequest, err := getChiaRootResourceRequest(node.Spec.Storage)
+	if err != nil {
+		meta.SetStatusCondition(&node.Status.Conditions, metav1.Condition{
+			Type:               k8schianetv1.ChiaNodeConditionVolumesExpanded,
+			Status:             metav1.ConditionUnknown,
+			ObservedGeneration: node.Generation,
+			Reason:             "InvalidResourceRequest",
+			Message:            fmt.Sprintf("unable to parse resourceRequest: %v", err),
+		})
+		node.Status.Ready = false
+		err = r.Status().Update(ctx, &node)
+		if err != nil {
+			log.Error(err, fmt.Sprintf("ChiaNodeReconciler ChiaNode=%s unable to update ChiaNode status", req.NamespacedName))
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
 	// Reconcile ChiaNode owned objects
+	peersConfig, daemonConfig, rpcConfig := getServiceConfigs(node.Spec.Services)
 	srv := r.assembleBaseService(ctx, node)
-	res, err := reconcileService(ctx, resourceReconciler, srv)
+	res, err := reconcileService(ctx, resourceReconciler, srv, serviceEnabled(peersConfig))
 	if err != nil {
 		if res == nil {
 			res = &reconcile.Result{}
@@ -76,8 +189,26 @@ func (r *ChiaNodeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		return *res, fmt.Errorf("ChiaNodeReconciler ChiaNode=%s encountered error reconciling node Service: %v", req.NamespacedName, err)
 	}
 
+	srv = r.assembleDaemonService(ctx, node)
+	res, err = reconcileService(ctx, resourceReconciler, srv, serviceEnabled(daemonConfig))
+	if err != nil {
+		if res == nil {
+			res = &reconcile.Result{}
+		}
+		return *res, fmt.Errorf("ChiaNodeReconciler ChiaNode=%s encountered error reconciling node daemon Service: %v", req.NamespacedName, err)
+	}
+
+	srv = r.assembleRPCService(ctx, node)
+	res, err = reconcileService(ctx, resourceReconciler, srv, serviceEnabled(rpcConfig))
+	if err != nil {
+		if res == nil {
+			res = &reconcile.Result{}
+		}
+		return *res, fmt.Errorf("ChiaNodeReconciler ChiaNode=%s encountered error reconciling node RPC Service: %v", req.NamespacedName, err)
+	}
+
 	srv = r.assembleInternalService(ctx, node)
-	res, err = reconcileService(ctx, resourceReconciler, srv)
+	res, err = reconcileService(ctx, resourceReconciler, srv, true)
 	if err != nil {
 		if res == nil {
 			res = &reconcile.Result{}
@@ -86,7 +217,7 @@ func (r *ChiaNodeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 	}
 
 	srv = r.assembleHeadlessService(ctx, node)
-	res, err = reconcileService(ctx, resourceReconciler, srv)
+	res, err = reconcileService(ctx, resourceReconciler, srv, true)
 	if err != nil {
 		if res == nil {
 			res = &reconcile.Result{}
@@ -95,7 +226,7 @@ func (r *ChiaNodeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 	}
 
 	srv = r.assembleChiaExporterService(ctx, node)
-	res, err = reconcileService(ctx, resourceReconciler, srv)
+	res, err = reconcileService(ctx, resourceReconciler, srv, chiaExporterEnabled(node.Spec.ChiaExporterConfig))
 	if err != nil {
 		if res == nil {
 			res = &reconcile.Result{}
@@ -103,7 +234,94 @@ func (r *ChiaNodeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		return *res, fmt.Errorf("ChiaNodeReconciler ChiaNode=%s encountered error reconciling node chia-exporter Service: %v", req.NamespacedName, err)
 	}
 
-	stateful := r.assembleStatefulset(ctx, node)
+	networkPolicy := r.assembleNetworkPolicy(ctx, node)
+	res, err = reconcileNetworkPolicy(ctx, resourceReconciler, networkPolicy, networkPolicyEnabled(node.Spec.NetworkPolicy))
+	if err != nil {
+		if res == nil {
+			res = &reconcile.Result{}
+		}
+		return *res, fmt.Errorf("ChiaNodeReconciler ChiaNode=%s encountered error reconciling node NetworkPolicy: %v", req.NamespacedName, err)
+	}
+
+	for _, exposure := range r.getPortExposures(ctx, node) {
+		res, err = reconcilePortExposure(ctx, r.Client, resourceReconciler, exposure)
+		if err != nil {
+			if res == nil {
+				res = &reconcile.Result{}
+			}
+			return *res, fmt.Errorf("ChiaNodeReconciler ChiaNode=%s encountered error reconciling node %s routes: %v", req.NamespacedName, exposure.objMeta.Name, err)
+		}
+	}
+
+	monitor := r.assembleChiaExporterServiceMonitor(ctx, node)
+	res, err = reconcileOptionalResource(ctx, r.Client, resourceReconciler, monitor, serviceMonitorEnabled(node.Spec.ChiaExporterConfig))
+	if err != nil {
+		if res == nil {
+			res = &reconcile.Result{}
+		}
+		return *res, fmt.Errorf("ChiaNodeReconciler ChiaNode=%s encountered error reconciling node chia-exporter ServiceMonitor: %v", req.NamespacedName, err)
+	}
+
+	rule := r.assembleChiaExporterPrometheusRule(ctx, node)
+	res, err = reconcileOptionalResource(ctx, r.Client, resourceReconciler, rule, prometheusRuleEnabled(node.Spec.ChiaExporterConfig))
+	if err != nil {
+		if res == nil {
+			res = &reconcile.Result{}
+		}
+		return *res, fmt.Errorf("ChiaNodeReconciler ChiaNode=%s encountered error reconciling node chia-exporter PrometheusRule: %v", req.NamespacedName, err)
+	}
+
+	overrides, err := getChiaConfigOverridesData(ctx, node.Spec.ChiaConfig.ConfigOverrides)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("ChiaNodeReconciler ChiaNode=%s encountered error assembling config overrides: %v", req.NamespacedName, err)
+	}
+	overridesRef, err := getChiaConfigOverridesRefData(ctx, r.Client, node.Namespace, node.Spec.ChiaConfig.ConfigOverrides)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("ChiaNodeReconciler ChiaNode=%s encountered error reading referenced config overrides: %v", req.NamespacedName, err)
+	}
+	if ipFamilies, _ := getServiceIPFamilies(node.Spec.Services); len(ipFamilies) != 0 {
+		overrides[chiaIPFamiliesKey], err = getIPFamiliesOverride("full_node", ipFamilies)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("ChiaNodeReconciler ChiaNode=%s encountered error assembling IP family config: %v", req.NamespacedName, err)
+		}
+	}
+	configMap := r.assembleConfigOverridesConfigMap(ctx, node, overrides)
+	res, err = reconcileConfigMap(ctx, resourceReconciler, configMap, len(overrides) != 0)
+	if err != nil {
+		if res == nil {
+			res = &reconcile.Result{}
+		}
+		return *res, fmt.Errorf("ChiaNodeReconciler ChiaNode=%s encountered error reconciling node config overrides ConfigMap: %v", req.NamespacedName, err)
+	}
+
+	stateful := r.assembleStatefulset(ctx, node, overrides, overridesRef, chiaRootRequest)
+
+	var existing appsv1.StatefulSet
+	err = r.Get(ctx, types.NamespacedName{Namespace: stateful.Namespace, Name: stateful.Name}, &existing)
+	if err != nil && !errors.IsNotFound(err) {
+		return ctrl.Result{}, fmt.Errorf("ChiaNodeReconciler ChiaNode=%s encountered error getting node StatefulSet: %v", req.NamespacedName, err)
+	}
+	if err == nil {
+		changes := getStatefulSetImmutableChanges(existing, stateful)
+		if len(changes) != 0 && node.Spec.UpdatePolicy == k8schianetv1.ChiaNodeUpdatePolicyOrphanRecreate {
+			err = r.recreateStatefulSet(ctx, &node, existing, changes)
+			if err != nil {
+				return ctrl.Result{}, fmt.Errorf("ChiaNodeReconciler ChiaNode=%s encountered error deleting node StatefulSet for recreation: %v", req.NamespacedName, err)
+			}
+
+			// The StatefulSet is recreated once its orphaning deletion is done
+			return ctrl.Result{Requeue: true}, nil
+		}
+		r.updateStatefulSetUpToDateCondition(&node, existing.Name, changes)
+		r.updateDatabaseBootstrapAppliedCondition(&node, existing)
+
+		// Immutable fields are kept from the existing StatefulSet, larger volumeClaimTemplate sizes are applied to claims directly in expandVolumes
+		keepStatefulSetImmutableFields(existing, &stateful)
+	} else {
+		r.updateStatefulSetUpToDateCondition(&node, stateful.Name, nil)
+		r.updateDatabaseBootstrapAppliedCondition(&node, stateful)
+	}
+
 	res, err = reconcileStatefulset(ctx, resourceReconciler, stateful)
 	if err != nil {
 		if res == nil {
@@ -114,62 +332,90 @@ func (r *ChiaNodeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 
 	// Update CR status
 	node.Status.Ready = true
+	r.updateSyncStatus(ctx, &node, getDatabaseVersion(overrides, overridesRef))
+	r.expandVolumes(ctx, &node, chiaRootRequest)
+	r.updateDatabaseBootstrapStatus(ctx, &node)
 	err = r.Status().Update(ctx, &node)
 	if err != nil {
 		log.Error(err, fmt.Sprintf("ChiaNodeReconciler ChiaCA=%s unable to update ChiaNode status", req.NamespacedName))
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: statusRefreshInterval}, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *ChiaNodeReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&k8schianetv1.ChiaNode{}).
+		// Only spec changes trigger reconciles, status updates would otherwise requeue the node immediately instead of after statusRefreshInterval
+		For(&k8schianetv1.ChiaNode{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Complete(r)
 }
 
-// assembleBaseService assembles the main Service resource for a ChiaNode CR
+// assembleBaseService assembles the main peer port Service resource for a ChiaNode CR
 func (r *ChiaNodeReconciler) assembleBaseService(ctx context.Context, node k8schianetv1.ChiaNode) corev1.Service {
-	return corev1.Service{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:            fmt.Sprintf("%s-node", node.Name),
-			Namespace:       node.Namespace,
-			Labels:          r.getCommonLabels(ctx, node, node.Spec.AdditionalMetadata.Labels),
-			Annotations:     node.Spec.AdditionalMetadata.Annotations,
-			OwnerReferences: r.getOwnerReference(ctx, node),
-		},
-		Spec: corev1.ServiceSpec{
-			Type: corev1.ServiceType(node.Spec.ServiceType),
-			Ports: []corev1.ServicePort{
-				{
-					Port:       daemonPort,
-					TargetPort: intstr.FromString("daemon"),
-					Protocol:   "TCP",
-					Name:       "daemon",
-				},
-				{
-					Port:       r.getFullNodePort(ctx, node),
-					TargetPort: intstr.FromString("peers"),
-					Protocol:   "TCP",
-					Name:       "peers",
-				},
-				{
-					Port:       nodeRPCPort,
-					TargetPort: intstr.FromString("rpc"),
-					Protocol:   "TCP",
-					Name:       "rpc",
-				},
-			},
-			Selector: r.getCommonLabels(ctx, node, node.Spec.AdditionalMetadata.Labels),
-		},
+	peers, _, _ := getServiceConfigs(node.Spec.Services)
+	objMeta := metav1.ObjectMeta{
+		Name:            fmt.Sprintf("%s-node", node.Name),
+		Namespace:       node.Namespace,
+		Labels:          r.getCommonLabels(ctx, node, node.Spec.AdditionalMetadata.Labels),
+		Annotations:     node.Spec.AdditionalMetadata.Annotations,
+		OwnerReferences: r.getOwnerReference(ctx, node),
+	}
+	srv := getPortService(objMeta, r.getCommonLabels(ctx, node, node.Spec.AdditionalMetadata.Labels), peers, corev1.ServiceType(node.Spec.ServiceType), corev1.ServicePort{
+		Port:       r.getFullNodePort(ctx, node),
+		TargetPort: intstr.FromString("peers"),
+		Protocol:   "TCP",
+		Name:       "peers",
+	})
+	srv.Spec.IPFamilies, srv.Spec.IPFamilyPolicy = getServiceIPFamilies(node.Spec.Services)
+	return srv
+}
+
+// assembleDaemonService assembles the daemon port Service resource for a ChiaNode CR
+func (r *ChiaNodeReconciler) assembleDaemonService(ctx context.Context, node k8schianetv1.ChiaNode) corev1.Service {
+	_, daemon, _ := getServiceConfigs(node.Spec.Services)
+	objMeta := metav1.ObjectMeta{
+		Name:            fmt.Sprintf("%s-node-daemon", node.Name),
+		Namespace:       node.Namespace,
+		Labels:          r.getCommonLabels(ctx, node, node.Spec.AdditionalMetadata.Labels),
+		Annotations:     node.Spec.AdditionalMetadata.Annotations,
+		OwnerReferences: r.getOwnerReference(ctx, node),
 	}
+	srv := getPortService(objMeta, r.getCommonLabels(ctx, node, node.Spec.AdditionalMetadata.Labels), daemon, corev1.ServiceTypeClusterIP, corev1.ServicePort{
+		Port:       daemonPort,
+		TargetPort: intstr.FromString("daemon"),
+		Protocol:   "TCP",
+		Name:       "daemon",
+	})
+	srv.Spec.IPFamilies, srv.Spec.IPFamilyPolicy = getServiceIPFamilies(node.Spec.Services)
+	return srv
+}
+
+// assembleRPCService assembles the RPC port Service resource for a ChiaNode CR
+func (r *ChiaNodeReconciler) assembleRPCService(ctx context.Context, node k8schianetv1.ChiaNode) corev1.Service {
+	_, _, rpc := getServiceConfigs(node.Spec.Services)
+	objMeta := metav1.ObjectMeta{
+		Name:            fmt.Sprintf("%s-node-rpc", node.Name),
+		Namespace:       node.Namespace,
+		Labels:          r.getCommonLabels(ctx, node, node.Spec.AdditionalMetadata.Labels),
+		Annotations:     node.Spec.AdditionalMetadata.Annotations,
+		OwnerReferences: r.getOwnerReference(ctx, node),
+	}
+	srv := getPortService(objMeta, r.getCommonLabels(ctx, node, node.Spec.AdditionalMetadata.Labels), rpc, corev1.ServiceTypeClusterIP, corev1.ServicePort{
+		Port:       nodeRPCPort,
+		TargetPort: intstr.FromString("rpc"),
+		Protocol:   "TCP",
+		Name:       "rpc",
+	})
+	srv.Spec.IPFamilies, srv.Spec.IPFamilyPolicy = getServiceIPFamilies(node.Spec.Services)
+	return srv
 }
 
 // assembleInternalService assembles the internal Service resource for a ChiaNode CR
 func (r *ChiaNodeReconciler) assembleInternalService(ctx context.Context, node k8schianetv1.ChiaNode) corev1.Service {
 	local := corev1.ServiceInternalTrafficPolicyLocal
+	ipFamilies, ipFamilyPolicy := getServiceIPFamilies(node.Spec.Services)
 	return corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            fmt.Sprintf("%s-node-internal", node.Name),
@@ -181,6 +427,8 @@ func (r *ChiaNodeReconciler) assembleInternalService(ctx context.Context, node k
 		Spec: corev1.ServiceSpec{
 			Type:                  corev1.ServiceType("ClusterIP"),
 			InternalTrafficPolicy: &local,
+			IPFamilies:            ipFamilies,
+			IPFamilyPolicy:        ipFamilyPolicy,
 			Ports: []corev1.ServicePort{
 				{
 					Port:       daemonPort,
@@ -208,6 +456,7 @@ func (r *ChiaNodeReconciler) assembleInternalService(ctx context.Context, node k
 
 // assembleHeadlessService assembles the headless Service resource for a ChiaNode CR
 func (r *ChiaNodeReconciler) assembleHeadlessService(ctx context.Context, node k8schianetv1.ChiaNode) corev1.Service {
+	ipFamilies, ipFamilyPolicy := getServiceIPFamilies(node.Spec.Services)
 	return corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            fmt.Sprintf("%s-node-headless", node.Name),
@@ -217,8 +466,10 @@ func (r *ChiaNodeReconciler) assembleHeadlessService(ctx context.Context, node k
 			OwnerReferences: r.getOwnerReference(ctx, node),
 		},
 		Spec: corev1.ServiceSpec{
-			Type:      corev1.ServiceType("ClusterIP"),
-			ClusterIP: "None",
+			Type:           corev1.ServiceType("ClusterIP"),
+			ClusterIP:      "None",
+			IPFamilies:     ipFamilies,
+			IPFamilyPolicy: ipFamilyPolicy,
 			Ports: []corev1.ServicePort{
 				{
 					Port:       daemonPort,
@@ -246,19 +497,22 @@ func (r *ChiaNodeReconciler) assembleHeadlessService(ctx context.Context, node k
 
 // assembleChiaExporterService assembles the chia-exporter Service resource for a ChiaNode CR
 func (r *ChiaNodeReconciler) assembleChiaExporterService(ctx context.Context, node k8schianetv1.ChiaNode) corev1.Service {
+	ipFamilies, ipFamilyPolicy := getServiceIPFamilies(node.Spec.Services)
 	return corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            fmt.Sprintf("%s-node-metrics", node.Name),
 			Namespace:       node.Namespace,
-			Labels:          r.getCommonLabels(ctx, node, node.Spec.AdditionalMetadata.Labels, node.Spec.ChiaExporterConfig.ServiceLabels),
+			Labels:          r.getCommonLabels(ctx, node, node.Spec.AdditionalMetadata.Labels, node.Spec.ChiaExporterConfig.ServiceLabels, chiaExporterServiceLabels),
 			Annotations:     node.Spec.AdditionalMetadata.Annotations,
 			OwnerReferences: r.getOwnerReference(ctx, node),
 		},
 		Spec: corev1.ServiceSpec{
-			Type: corev1.ServiceType("ClusterIP"),
+			Type:           corev1.ServiceType("ClusterIP"),
+			IPFamilies:     ipFamilies,
+			IPFamilyPolicy: ipFamilyPolicy,
 			Ports: []corev1.ServicePort{
 				{
-					Port:       chiaExporterPort,
+					Port:       getChiaExporterPort(node.Spec.ChiaExporterConfig),
 					TargetPort: intstr.FromString("metrics"),
 					Protocol:   "TCP",
 					Name:       "metrics",
@@ -269,8 +523,86 @@ func (r *ChiaNodeReconciler) assembleChiaExporterService(ctx context.Context, no
 	}
 }
 
+// assembleNetworkPolicy assembles the NetworkPolicy resource for a ChiaNode CR. The peer port is open to any source, since full nodes accept public peers
+func (r *ChiaNodeReconciler) assembleNetworkPolicy(ctx context.Context, node k8schianetv1.ChiaNode) networkingv1.NetworkPolicy {
+	peerRules := []networkingv1.NetworkPolicyIngressRule{
+		getNetworkPolicyIngressRule(r.getFullNodePort(ctx, node), nil),
+	}
+
+	objMeta := metav1.ObjectMeta{
+		Name:            fmt.Sprintf("%s-node", node.Name),
+		Namespace:       node.Namespace,
+		Labels:          r.getCommonLabels(ctx, node, node.Spec.AdditionalMetadata.Labels),
+		Annotations:     node.Spec.AdditionalMetadata.Annotations,
+		OwnerReferences: r.getOwnerReference(ctx, node),
+	}
+	return getNetworkPolicy(objMeta, r.getCommonLabels(ctx, node, node.Spec.AdditionalMetadata.Labels), node.Spec.NetworkPolicy, peerRules, nodeRPCPort, node.Spec.ChiaExporterConfig)
+}
+
+// getPortExposures gives the Gateway API routes exposing the peer and RPC ports of a ChiaNode
+func (r *ChiaNodeReconciler) getPortExposures(ctx context.Context, node k8schianetv1.ChiaNode) []portExposure {
+	objMeta := metav1.ObjectMeta{
+		Name:            fmt.Sprintf("%s-node", node.Name),
+		Namespace:       node.Namespace,
+		Labels:          r.getCommonLabels(ctx, node, node.Spec.AdditionalMetadata.Labels),
+		Annotations:     node.Spec.AdditionalMetadata.Annotations,
+		OwnerReferences: r.getOwnerReference(ctx, node),
+	}
+	return getPortExposures(objMeta, node.Spec.Expose, r.getFullNodePort(ctx, node), nodeRPCPort)
+}
+
+// assembleChiaExporterServiceMonitor assembles the chia-exporter ServiceMonitor resource for a ChiaNode CR
+func (r *ChiaNodeReconciler) assembleChiaExporterServiceMonitor(ctx context.Context, node k8schianetv1.ChiaNode) *unstructured.Unstructured {
+	objMeta := metav1.ObjectMeta{
+		Name:            fmt.Sprintf("%s-node-metrics", node.Name),
+		Namespace:       node.Namespace,
+		Labels:          r.getCommonLabels(ctx, node, node.Spec.AdditionalMetadata.Labels),
+		Annotations:     node.Spec.AdditionalMetadata.Annotations,
+		OwnerReferences: r.getOwnerReference(ctx, node),
+	}
+	return getChiaExporterServiceMonitor(ctx, objMeta, node.Spec.ChiaExporterConfig, r.getCommonLabels(ctx, node, chiaExporterServiceLabels))
+}
+
+// assembleChiaExporterPrometheusRule assembles the chia-exporter PrometheusRule resource for a ChiaNode CR
+func (r *ChiaNodeReconciler) assembleChiaExporterPrometheusRule(ctx context.Context, node k8schianetv1.ChiaNode) *unstructured.Unstructured {
+	objMeta := metav1.ObjectMeta{
+		Name:            fmt.Sprintf("%s-node", node.Name),
+		Namespace:       node.Namespace,
+		Labels:          r.getCommonLabels(ctx, node, node.Spec.AdditionalMetadata.Labels),
+		Annotations:     node.Spec.AdditionalMetadata.Annotations,
+		OwnerReferences: r.getOwnerReference(ctx, node),
+	}
+	service := fmt.Sprintf("%s-node-metrics", node.Name)
+	selector := getChiaExporterAlertSelector(node.Namespace, service)
+	alerts := []chiaExporterAlert{
+		getChiaExporterDownAlert(node.Name, node.Namespace, service),
+		{
+			Name:        "ChiaNodeNotSynced",
+			Expr:        fmt.Sprintf("chia_blockchain_sync_status_synced%s == 0", selector),
+			For:         "30m",
+			Summary:     fmt.Sprintf("Chia full_node %s/%s is not synced", node.Namespace, node.Name),
+			Description: fmt.Sprintf("The full_node {{ $labels.pod }} in %s has not been synced to the blockchain for 30 minutes.", node.Namespace),
+		},
+	}
+	return getChiaExporterPrometheusRule(ctx, objMeta, node.Spec.ChiaExporterConfig, objMeta.Name, alerts)
+}
+
+// assembleConfigOverridesConfigMap assembles the config.yaml overrides ConfigMap resource for a ChiaNode CR
+func (r *ChiaNodeReconciler) assembleConfigOverridesConfigMap(ctx context.Context, node k8schianetv1.ChiaNode, data map[string]string) corev1.ConfigMap {
+	return corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            fmt.Sprintf("%s-node-config-overrides", node.Name),
+			Namespace:       node.Namespace,
+			Labels:          r.getCommonLabels(ctx, node, node.Spec.AdditionalMetadata.Labels),
+			Annotations:     node.Spec.AdditionalMetadata.Annotations,
+			OwnerReferences: r.getOwnerReference(ctx, node),
+		},
+		Data: data,
+	}
+}
+
 // assembleStatefulset assembles the node StatefulSet resource for a ChiaNode CR
-func (r *ChiaNodeReconciler) assembleStatefulset(ctx context.Context, node k8schianetv1.ChiaNode) appsv1.StatefulSet {
+func (r *ChiaNodeReconciler) assembleStatefulset(ctx context.Context, node k8schianetv1.ChiaNode, overrides map[string]string, overridesRef string, chiaRootRequest resource.Quantity) appsv1.StatefulSet {
 	var chiaSecContext *corev1.SecurityContext
 	if node.Spec.ChiaConfig.SecurityContext != nil {
 		chiaSecContext = node.Spec.ChiaConfig.SecurityContext
@@ -301,12 +633,7 @@ func (r *ChiaNodeReconciler) assembleStatefulset(ctx context.Context, node k8sch
 		imagePullPolicy = *node.Spec.ImagePullPolicy
 	}
 
-	var chiaExporterImage = node.Spec.ChiaExporterConfig.Image
-	if chiaExporterImage == "" {
-		chiaExporterImage = defaultChiaExporterImage
-	}
-
-	vols, volClaimTemplates := r.getChiaVolumesAndTemplates(ctx, node)
+	vols, volClaimTemplates := r.getChiaVolumesAndTemplates(ctx, node, chiaRootRequest)
 
 	var stateful appsv1.StatefulSet = appsv1.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
@@ -325,7 +652,7 @@ func (r *ChiaNodeReconciler) assembleStatefulset(ctx context.Context, node k8sch
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels:      r.getCommonLabels(ctx, node, node.Spec.AdditionalMetadata.Labels),
-					Annotations: node.Spec.AdditionalMetadata.Annotations,
+					Annotations: getConfigChecksumPodAnnotations(getConfigOverridesPodAnnotations(node.Spec.AdditionalMetadata.Annotations, overrides, overridesRef), overrides, chiaIPFamiliesKey, ipFamiliesChecksumAnnotation),
 				},
 				Spec: corev1.PodSpec{
 					// TODO add: imagePullSecret, serviceAccountName config
@@ -368,8 +695,20 @@ func (r *ChiaNodeReconciler) assembleStatefulset(ctx context.Context, node k8sch
 		},
 	}
 
-	exporterContainer := getChiaExporterContainer(ctx, chiaExporterImage, chiaSecContext, imagePullPolicy, chiaResources)
-	stateful.Spec.Template.Spec.Containers = append(stateful.Spec.Template.Spec.Containers, exporterContainer)
+	if chiaExporterEnabled(node.Spec.ChiaExporterConfig) {
+		exporterContainer := getChiaExporterContainer(ctx, node.Spec.ChiaExporterConfig, chiaSecContext, imagePullPolicy)
+		stateful.Spec.Template.Spec.Containers = append(stateful.Spec.Template.Spec.Containers, exporterContainer)
+	}
+
+	if databaseBootstrapInitContainerEnabled(node.Spec.DatabaseBootstrap) {
+		stateful.Spec.Template.Spec.InitContainers = append(stateful.Spec.Template.Spec.InitContainers, r.getDatabaseBootstrapInitContainer(ctx, node, chiaSecContext, imagePullPolicy))
+	}
+
+	overridesVolume, ok := getChiaConfigOverridesVolume(ctx, node.Spec.ChiaConfig.ConfigOverrides, fmt.Sprintf("%s-node-config-overrides", node.Name), overrides)
+	if ok {
+		stateful.Spec.Template.Spec.Volumes = append(stateful.Spec.Template.Spec.Volumes, overridesVolume)
+		stateful.Spec.Template.Spec.InitContainers = append(stateful.Spec.Template.Spec.InitContainers, getChiaConfigOverridesInitContainer(ctx, node.Spec.ChiaConfig.Image, chiaSecContext, imagePullPolicy))
+	}
 
 	if node.Spec.PodSecurityContext != nil {
 		stateful.Spec.Template.Spec.SecurityContext = node.Spec.PodSecurityContext
@@ -381,7 +720,7 @@ func (r *ChiaNodeReconciler) assembleStatefulset(ctx context.Context, node k8sch
 }
 
 // getChiaVolumes retrieves the requisite volumes from the Chia config struct
-func (r *ChiaNodeReconciler) getChiaVolumesAndTemplates(ctx context.Context, node k8schianetv1.ChiaNode) ([]corev1.Volume, []corev1.PersistentVolumeClaim) {
+func (r *ChiaNodeReconciler) getChiaVolumesAndTemplates(ctx context.Context, node k8schianetv1.ChiaNode, chiaRootRequest resource.Quantity) ([]corev1.Volume, []corev1.PersistentVolumeClaim) {
 	var v []corev1.Volume
 	var vcts []corev1.PersistentVolumeClaim
 
@@ -395,8 +734,8 @@ func (r *ChiaNodeReconciler) getChiaVolumesAndTemplates(ctx context.Context, nod
 		},
 	})
 
-	// CHIA_ROOT volume -- PVC is respected first if both it and hostpath are specified, falls back to hostPath if specified
-	// If both are empty, fall back to emptyDir so chia-exporter can mount CHIA_ROOT
+	// CHIA_ROOT volume -- PVC is respected first, then hostPath, then NFS, CSI, ephemeral and iSCSI volumes
+	// If none are specified, fall back to emptyDir so chia-exporter can mount CHIA_ROOT
 	var chiaRootAdded bool = false
 	if node.Spec.Storage != nil && node.Spec.Storage.ChiaRoot != nil {
 		if node.Spec.Storage.ChiaRoot.PersistentVolumeClaim != nil {
@@ -405,24 +744,27 @@ func (r *ChiaNodeReconciler) getChiaVolumesAndTemplates(ctx context.Context, nod
 					Name: "chiaroot",
 				},
 				Spec: corev1.PersistentVolumeClaimSpec{
-					AccessModes:      []corev1.PersistentVolumeAccessMode{"ReadWriteOnce"},
+					AccessModes:      getChiaRootAccessModes(node.Spec.Storage.ChiaRoot.PersistentVolumeClaim),
 					StorageClassName: &node.Spec.Storage.ChiaRoot.PersistentVolumeClaim.StorageClass,
 					Resources: corev1.ResourceRequirements{
 						Requests: corev1.ResourceList{
-							corev1.ResourceStorage: resource.MustParse(node.Spec.Storage.ChiaRoot.PersistentVolumeClaim.ResourceRequest),
+							corev1.ResourceStorage: chiaRootRequest,
 						},
 					},
+					DataSource: getDatabaseBootstrapDataSource(node.Spec.DatabaseBootstrap),
 				},
 			})
 			chiaRootAdded = true
 		} else if node.Spec.Storage.ChiaRoot.HostPathVolume != nil {
 			v = append(v, corev1.Volume{
-				Name: "chiaroot",
-				VolumeSource: corev1.VolumeSource{
-					HostPath: &corev1.HostPathVolumeSource{
-						Path: node.Spec.Storage.ChiaRoot.HostPathVolume.Path,
-					},
-				},
+				Name:         "chiaroot",
+				VolumeSource: getHostPathVolumeSource(node.Spec.Storage.ChiaRoot.HostPathVolume),
+			})
+			chiaRootAdded = true
+		} else if source, ok := getChiaRootVolumeSource(node.Spec.Storage.ChiaRoot); ok {
+			v = append(v, corev1.Volume{
+				Name:         "chiaroot",
+				VolumeSource: source,
 			})
 			chiaRootAdded = true
 		}
@@ -436,6 +778,19 @@ func (r *ChiaNodeReconciler) getChiaVolumesAndTemplates(ctx context.Context, nod
 		})
 	}
 
+	// database bootstrap source volume
+	if databaseBootstrapInitContainerEnabled(node.Spec.DatabaseBootstrap) && node.Spec.DatabaseBootstrap.PersistentVolumeClaim != nil {
+		v = append(v, corev1.Volume{
+			Name: "db-bootstrap",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: node.Spec.DatabaseBootstrap.PersistentVolumeClaim.ClaimName,
+					ReadOnly:  true,
+				},
+			},
+		})
+	}
+
 	return v, vcts
 }
 
@@ -551,3 +906,586 @@ func (r *ChiaNodeReconciler) getFullNodePort(ctx context.Context, node k8schiane
 	}
 	return mainnetNodePort
 }
+
+// updateSyncStatus gathers the sync status of every running full_node replica through its RPC server and sets it in the ChiaNode's status
+func (r *ChiaNodeReconciler) updateSyncStatus(ctx context.Context, node *k8schianetv1.ChiaNode, databaseVersion int32) {
+	now := metav1.Now()
+	node.Status.LastStatusUpdateTime = &now
+
+	pods, err := listRunningPods(ctx, r.Client, node.Namespace, r.getCommonLabels(ctx, *node))
+	if err != nil {
+		meta.SetStatusCondition(&node.Status.Conditions, metav1.Condition{
+			Type:               k8schianetv1.ChiaNodeConditionInSync,
+			Status:             metav1.ConditionUnknown,
+			ObservedGeneration: node.Generation,
+			Reason:             "StatusUnavailable",
+			Message:            fmt.Sprintf("unable to list full_node pods: %v", err),
+		})
+		return
+	}
+
+	tlsConfig, err := getChiaRPCTLSConfig(ctx, r.Client, node.Namespace, node.Spec.ChiaConfig.CASecretName)
+	if err != nil {
+		meta.SetStatusCondition(&node.Status.Conditions, metav1.Condition{
+			Type:               k8schianetv1.ChiaNodeConditionInSync,
+			Status:             metav1.ConditionUnknown,
+			ObservedGeneration: node.Generation,
+			Reason:             "StatusUnavailable",
+			Message:            fmt.Sprintf("unable to create RPC client from CA Secret: %v", err),
+		})
+		return
+	}
+
+	var replicas []k8schianetv1.ChiaNodeReplicaStatus
+	var synced int32
+	for _, pod := range pods {
+		replica := r.getReplicaStatus(ctx, pod, tlsConfig, databaseVersion)
+		if replica.SyncMode == k8schianetv1.ChiaNodeSynced {
+			synced++
+		}
+		replicas = append(replicas, replica)
+	}
+	node.Status.Replicas = replicas
+	node.Status.SyncedReplicas = synced
+
+	condition := metav1.Condition{
+		Type:               k8schianetv1.ChiaNodeConditionInSync,
+		ObservedGeneration: node.Generation,
+	}
+	switch {
+	case synced > 0:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "Synced"
+		condition.Message = fmt.Sprintf("%d of %d running replicas are synced", synced, len(replicas))
+	case len(replicas) == 0:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "NoRunningReplicas"
+		condition.Message = "no full_node replicas are running"
+	default:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "NotSynced"
+		condition.Message = fmt.Sprintf("0 of %d running replicas are synced", len(replicas))
+	}
+	meta.SetStatusCondition(&node.Status.Conditions, condition)
+}
+
+// getReplicaStatus queries a full_node pod's RPC server for its sync status. The database version is only reported for replicas whose RPC server responds
+func (r *ChiaNodeReconciler) getReplicaStatus(ctx context.Context, pod corev1.Pod, tlsConfig *tls.Config, databaseVersion int32) k8schianetv1.ChiaNodeReplicaStatus {
+	log := log.FromContext(ctx)
+	replica := k8schianetv1.ChiaNodeReplicaStatus{
+		PodName:  pod.Name,
+		SyncMode: k8schianetv1.ChiaNodeSyncUnknown,
+	}
+
+	host, port := getChiaRPCHostPort(pod.Status.PodIP, nodeRPCPort)
+	rpc := chiarpc.NewFullNodeClient(host, port, tlsConfig)
+	state, err := rpc.GetBlockchainState(ctx)
+	if err != nil {
+		log.Info(fmt.Sprintf("ChiaNodeReconciler unable to get blockchain state from pod %s/%s: %v", pod.Namespace, pod.Name, err))
+		return replica
+	}
+	replica.NodeID = state.NodeID
+	replica.DatabaseVersion = databaseVersion
+	if state.Peak != nil {
+		replica.PeakHeight = int64(state.Peak.Height)
+	}
+	replica.SyncMode, replica.SyncProgressPercent = getSyncMode(state)
+
+	conns, err := rpc.GetConnections(ctx)
+	if err != nil {
+		log.Info(fmt.Sprintf("ChiaNodeReconciler unable to get connections from pod %s/%s: %v", pod.Namespace, pod.Name, err))
+		return replica
+	}
+	for _, conn := range conns {
+		if conn.Type == chiarpc.NodeTypeFullNode {
+			replica.Peers++
+		}
+	}
+
+	return replica
+}
+
+// getDatabaseVersion returns the blockchain database schema version in the full_node's database_path, from the last config.yaml override document setting it.
+// Documents are merged in file name order, and chia's default path is used when none set it
+func getDatabaseVersion(overrides map[string]string, overridesRef string) int32 {
+	docs := make(map[string]string)
+	for k, v := range overrides {
+		docs[k] = v
+	}
+	if overridesRef != "" {
+		docs[chiaConfigOverridesRefKey] = overridesRef
+	}
+	var keys []string
+	for k := range docs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	databasePath := defaultDatabasePath
+	for _, k := range keys {
+		var doc struct {
+			FullNode struct {
+				DatabasePath string `json:"database_path"`
+			} `json:"full_node"`
+		}
+		if yaml.Unmarshal([]byte(docs[k]), &doc) == nil && doc.FullNode.DatabasePath != "" {
+			databasePath = doc.FullNode.DatabasePath
+		}
+	}
+
+	match := databaseVersionRegexp.FindStringSubmatch(path.Base(databasePath))
+	if match == nil {
+		return 0
+	}
+	version, err := strconv.ParseInt(match[1], 10, 32)
+	if err != nil {
+		return 0
+	}
+	return int32(version)
+}
+
+// getSyncMode summarizes a full_node's blockchain state as a sync mode and a sync progress percentage
+func getSyncMode(state chiarpc.BlockchainState) (k8schianetv1.ChiaNodeSyncMode, int32) {
+	if state.Sync.Synced {
+		return k8schianetv1.ChiaNodeSynced, 100
+	}
+	if state.Sync.SyncMode {
+		var progress int32
+		if state.Sync.SyncTipHeight > 0 {
+			progress = int32(uint64(state.Sync.SyncProgressHeight) * 100 / uint64(state.Sync.SyncTipHeight))
+		}
+		return k8schianetv1.ChiaNodeSyncing, progress
+	}
+	return k8schianetv1.ChiaNodeNotSynced, 0
+}
+
+// getStatefulSetImmutableChanges returns the immutable StatefulSet fields that differ between an existing StatefulSet and its desired state.
+// volumeClaimTemplate storage requests are ignored since existing claims are expanded directly
+func getStatefulSetImmutableChanges(existing, desired appsv1.StatefulSet) []string {
+	var changes []string
+	if !equality.Semantic.DeepEqual(existing.Spec.Selector, desired.Spec.Selector) {
+		changes = append(changes, "selector")
+	}
+	if existing.Spec.ServiceName != desired.Spec.ServiceName {
+		changes = append(changes, "serviceName")
+	}
+	if desired.Spec.PodManagementPolicy != "" && existing.Spec.PodManagementPolicy != desired.Spec.PodManagementPolicy {
+		changes = append(changes, "podManagementPolicy")
+	}
+	if volumeClaimTemplatesChanged(existing.Spec.VolumeClaimTemplates, desired.Spec.VolumeClaimTemplates) {
+		changes = append(changes, "volumeClaimTemplates")
+	}
+	return changes
+}
+
+// recreateStatefulSet deletes a ChiaNode's StatefulSet, orphaning its pods and PersistentVolumeClaims, so it can be recreated with changed immutable fields.
+// A StatefulSet that is already being deleted is left alone so the deletion and its event aren't repeated while it finishes
+func (r *ChiaNodeReconciler) recreateStatefulSet(ctx context.Context, node *k8schianetv1.ChiaNode, existing appsv1.StatefulSet, changes []string) error {
+	if existing.DeletionTimestamp != nil {
+		return nil
+	}
+
+	err := r.Delete(ctx, &existing, client.PropagationPolicy(metav1.DeletePropagationOrphan), client.Preconditions{UID: &existing.UID})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	r.Recorder.Eventf(node, corev1.EventTypeNormal, "StatefulSetRecreated", "Deleted StatefulSet %s, keeping its pods and PersistentVolumeClaims, to change %s", existing.Name, strings.Join(changes, ", "))
+	return nil
+}
+
+// updateStatefulSetUpToDateCondition sets a ChiaNode's StatefulSetUpToDate condition, warning about changed immutable fields only when the condition changes
+func (r *ChiaNodeReconciler) updateStatefulSetUpToDateCondition(node *k8schianetv1.ChiaNode, name string, changes []string) {
+	condition := getStatefulSetUpToDateCondition(node.Generation, name, changes)
+	if condition.Status == metav1.ConditionFalse && conditionChanged(node.Status.Conditions, condition) {
+		r.Recorder.Event(node, corev1.EventTypeWarning, "ImmutableFieldsChanged", condition.Message)
+	}
+	meta.SetStatusCondition(&node.Status.Conditions, condition)
+}
+
+// updateDatabaseBootstrapAppliedCondition sets a ChiaNode's DatabaseBootstrapApplied condition from whether the StatefulSet's volumeClaimTemplates
+// provision claims from the configured VolumeSnapshot, warning that it isn't applied only when the condition changes. It is removed without a VolumeSnapshot
+func (r *ChiaNodeReconciler) updateDatabaseBootstrapAppliedCondition(node *k8schianetv1.ChiaNode, stateful appsv1.StatefulSet) {
+	snapshot := getDatabaseBootstrapDataSource(node.Spec.DatabaseBootstrap)
+	if snapshot == nil {
+		meta.RemoveStatusCondition(&node.Status.Conditions, k8schianetv1.ChiaNodeConditionDatabaseBootstrapApplied)
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:               k8schianetv1.ChiaNodeConditionDatabaseBootstrapApplied,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: node.Generation,
+		Reason:             "Applied",
+		Message:            fmt.Sprintf("new CHIA_ROOT claims of StatefulSet %s are restored from VolumeSnapshot %s", stateful.Name, snapshot.Name),
+	}
+	if !volumeClaimTemplatesHaveDataSource(stateful.Spec.VolumeClaimTemplates, snapshot) {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "DatabaseBootstrapNotApplied"
+		condition.Message = fmt.Sprintf("VolumeSnapshot %s is only restored to CHIA_ROOT claims created after StatefulSet %s is recreated with it, existing claims keep their data", snapshot.Name, stateful.Name)
+		if conditionChanged(node.Status.Conditions, condition) {
+			r.Recorder.Event(node, corev1.EventTypeWarning, condition.Reason, condition.Message)
+		}
+	}
+	meta.SetStatusCondition(&node.Status.Conditions, condition)
+}
+
+// getStatefulSetUpToDateCondition returns the StatefulSetUpToDate condition for the immutable StatefulSet fields a ChiaNode changes
+func getStatefulSetUpToDateCondition(generation int64, name string, changes []string) metav1.Condition {
+	if len(changes) == 0 {
+		return metav1.Condition{
+			Type:               k8schianetv1.ChiaNodeConditionStatefulSetUpToDate,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: generation,
+			Reason:             "UpToDate",
+			Message:            fmt.Sprintf("StatefulSet %s has every immutable field the ChiaNode requires", name),
+		}
+	}
+	return metav1.Condition{
+		Type:               k8schianetv1.ChiaNodeConditionStatefulSetUpToDate,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: generation,
+		Reason:             "ImmutableFieldsChanged",
+		Message:            fmt.Sprintf("StatefulSet %s can't change %s without being recreated, set spec.updatePolicy to OrphanRecreate to recreate it", name, strings.Join(changes, ", ")),
+	}
+}
+
+// volumeClaimTemplatesHaveDataSource returns true if every volumeClaimTemplate provisions new claims from the given data source
+func volumeClaimTemplatesHaveDataSource(templates []corev1.PersistentVolumeClaim, dataSource *corev1.TypedLocalObjectReference) bool {
+	for _, template := range templates {
+		if !equality.Semantic.DeepEqual(template.Spec.DataSource, dataSource) {
+			return false
+		}
+	}
+	return true
+}
+
+// volumeClaimTemplatesChanged returns true if the templates' names, StorageClasses, access modes or data sources differ
+func volumeClaimTemplatesChanged(existing, desired []corev1.PersistentVolumeClaim) bool {
+	if len(existing) != len(desired) {
+		return true
+	}
+	for i := range desired {
+		if existing[i].Name != desired[i].Name {
+			return true
+		}
+		if !equality.Semantic.DeepEqual(existing[i].Spec.StorageClassName, desired[i].Spec.StorageClassName) {
+			return true
+		}
+		if !equality.Semantic.DeepEqual(existing[i].Spec.AccessModes, desired[i].Spec.AccessModes) {
+			return true
+		}
+		if !equality.Semantic.DeepEqual(existing[i].Spec.DataSource, desired[i].Spec.DataSource) {
+			return true
+		}
+	}
+	return false
+}
+
+// keepStatefulSetImmutableFields copies the immutable fields of an existing StatefulSet into its desired state so it can be updated
+func keepStatefulSetImmutableFields(existing appsv1.StatefulSet, desired *appsv1.StatefulSet) {
+	desired.Spec.Selector = existing.Spec.Selector
+	desired.Spec.ServiceName = existing.Spec.ServiceName
+	desired.Spec.PodManagementPolicy = existing.Spec.PodManagementPolicy
+	desired.Spec.VolumeClaimTemplates = existing.Spec.VolumeClaimTemplates
+}
+
+// expandVolumes raises the storage request of every existing replica's CHIA_ROOT PVC that is smaller than the ChiaNode's resourceRequest,
+// and reports the progress of each expansion in the ChiaNode's status
+func (r *ChiaNodeReconciler) expandVolumes(ctx context.Context, node *k8schianetv1.ChiaNode, requested resource.Quantity) {
+	if node.Spec.Storage == nil || node.Spec.Storage.ChiaRoot == nil || node.Spec.Storage.ChiaRoot.PersistentVolumeClaim == nil {
+		node.Status.Volumes = nil
+		meta.RemoveStatusCondition(&node.Status.Conditions, k8schianetv1.ChiaNodeConditionVolumesExpanded)
+		return
+	}
+
+	var replicas int32 = 1
+	if node.Spec.Replicas != nil {
+		replicas = *node.Spec.Replicas
+	}
+
+	var volumes []k8schianetv1.ChiaNodeVolumeStatus
+	var failures []string
+	for i := int32(0); i < replicas; i++ {
+		var pvc corev1.PersistentVolumeClaim
+		name := fmt.Sprintf("chiaroot-%s-node-%d", node.Name, i)
+		err := r.Get(ctx, types.NamespacedName{Namespace: node.Namespace, Name: name}, &pvc)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("unable to get PersistentVolumeClaim %s: %v", name, err))
+			continue
+		}
+
+		volume, err := r.expandVolume(ctx, &pvc, requested)
+		if err != nil {
+			failures = append(failures, err.Error())
+		}
+		volumes = append(volumes, volume)
+	}
+	node.Status.Volumes = volumes
+
+	meta.SetStatusCondition(&node.Status.Conditions, getVolumesExpandedCondition(node.Generation, volumes, failures))
+}
+
+// getChiaRootResourceRequest parses the storage request of a ChiaNode's CHIA_ROOT volumeClaimTemplate. It is zero when CHIA_ROOT isn't a PersistentVolumeClaim
+func getChiaRootResourceRequest(storage *k8schianetv1.StorageConfig) (resource.Quantity, error) {
+	if storage == nil || storage.ChiaRoot == nil || storage.ChiaRoot.PersistentVolumeClaim == nil {
+		return resource.Quantity{}, nil
+	}
+	return resource.ParseQuantity(storage.ChiaRoot.PersistentVolumeClaim.ResourceRequest)
+}
+
+// expandVolume raises the storage request of a CHIA_ROOT PVC if it is smaller than requested and its StorageClass allows expansion, and returns its status
+func (r *ChiaNodeReconciler) expandVolume(ctx context.Context, pvc *corev1.PersistentVolumeClaim, requested resource.Quantity) (k8schianetv1.ChiaNodeVolumeStatus, error) {
+	current := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	if current.Cmp(requested) >= 0 {
+		return getVolumeStatus(*pvc), nil
+	}
+
+	expandable, err := r.storageClassAllowsExpansion(ctx, pvc.Spec.StorageClassName)
+	if err != nil {
+		return getVolumeStatus(*pvc), err
+	}
+	if !expandable {
+		volume := getVolumeStatus(*pvc)
+		volume.State = k8schianetv1.ChiaNodeVolumeExpansionNotSupported
+		return volume, nil
+	}
+
+	expanded := pvc.DeepCopy()
+	if expanded.Spec.Resources.Requests == nil {
+		expanded.Spec.Resources.Requests = corev1.ResourceList{}
+	}
+	expanded.Spec.Resources.Requests[corev1.ResourceStorage] = requested
+	err = r.Update(ctx, expanded)
+	if err != nil {
+		return getVolumeStatus(*pvc), fmt.Errorf("unable to expand PersistentVolumeClaim %s: %v", pvc.Name, err)
+	}
+	return getVolumeStatus(*expanded), nil
+}
+
+// storageClassAllowsExpansion returns true if the named StorageClass allows its volumes to be expanded. Claims without a StorageClass can't be expanded
+func (r *ChiaNodeReconciler) storageClassAllowsExpansion(ctx context.Context, name *string) (bool, error) {
+	if name == nil || *name == "" {
+		return false, nil
+	}
+
+	var class storagev1.StorageClass
+	err := r.Get(ctx, types.NamespacedName{Name: *name}, &class)
+	if err != nil {
+		return false, fmt.Errorf("unable to get StorageClass %s: %v", *name, err)
+	}
+	return class.AllowVolumeExpansion != nil && *class.AllowVolumeExpansion, nil
+}
+
+// getVolumeStatus reports a CHIA_ROOT PVC's size and the progress of resizing its volume to the requested storage
+func getVolumeStatus(pvc corev1.PersistentVolumeClaim) k8schianetv1.ChiaNodeVolumeStatus {
+	volume := k8schianetv1.ChiaNodeVolumeStatus{
+		ClaimName: pvc.Name,
+		State:     k8schianetv1.ChiaNodeVolumeReady,
+	}
+
+	current, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	if ok {
+		volume.Requested = &current
+	}
+	if capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+		volume.Capacity = &capacity
+	}
+
+	for _, condition := range pvc.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case corev1.PersistentVolumeClaimFileSystemResizePending:
+			volume.State = k8schianetv1.ChiaNodeVolumeFileSystemResizePending
+			return volume
+		case corev1.PersistentVolumeClaimResizing:
+			volume.State = k8schianetv1.ChiaNodeVolumeResizing
+			return volume
+		}
+	}
+	if volume.Capacity != nil && volume.Capacity.Cmp(current) < 0 {
+		volume.State = k8schianetv1.ChiaNodeVolumeResizing
+	}
+	return volume
+}
+
+// getVolumesExpandedCondition summarizes the state of every replica's CHIA_ROOT PVC into the VolumesExpanded condition
+func getVolumesExpandedCondition(generation int64, volumes []k8schianetv1.ChiaNodeVolumeStatus, failures []string) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               k8schianetv1.ChiaNodeConditionVolumesExpanded,
+		ObservedGeneration: generation,
+	}
+
+	var notSupported, pending, resizing []string
+	for _, volume := range volumes {
+		switch volume.State {
+		case k8schianetv1.ChiaNodeVolumeExpansionNotSupported:
+			notSupported = append(notSupported, volume.ClaimName)
+		case k8schianetv1.ChiaNodeVolumeFileSystemResizePending:
+			pending = append(pending, volume.ClaimName)
+		case k8schianetv1.ChiaNodeVolumeResizing:
+			resizing = append(resizing, volume.ClaimName)
+		}
+	}
+
+	switch {
+	case len(failures) != 0:
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = "ExpansionFailed"
+		condition.Message = strings.Join(failures, "; ")
+	case len(notSupported) != 0:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "ExpansionNotSupported"
+		condition.Message = fmt.Sprintf("the StorageClass of %s doesn't allow volume expansion", strings.Join(notSupported, ", "))
+	case len(resizing) != 0:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "Resizing"
+		condition.Message = fmt.Sprintf("%s are being resized", strings.Join(resizing, ", "))
+	case len(pending) != 0:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "FileSystemResizePending"
+		condition.Message = fmt.Sprintf("%s are waiting for a pod restart to resize their filesystem", strings.Join(pending, ", "))
+	default:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "Expanded"
+		condition.Message = fmt.Sprintf("%d volumes have the requested capacity", len(volumes))
+	}
+	return condition
+}
+
+// databaseBootstrapInitContainerEnabled returns true if the ChiaNode's database is restored by an init container, rather than from a VolumeSnapshot
+func databaseBootstrapInitContainerEnabled(bootstrap *k8schianetv1.ChiaNodeDatabaseBootstrapSpec) bool {
+	return bootstrap != nil && bootstrap.VolumeSnapshot == nil && (bootstrap.PersistentVolumeClaim != nil || bootstrap.URL != nil)
+}
+
+// getDatabaseBootstrapDataSource returns the VolumeSnapshot data source for new CHIA_ROOT claims, if the database is restored from one
+func getDatabaseBootstrapDataSource(bootstrap *k8schianetv1.ChiaNodeDatabaseBootstrapSpec) *corev1.TypedLocalObjectReference {
+	if bootstrap == nil || bootstrap.VolumeSnapshot == nil {
+		return nil
+	}
+
+	apiGroup := "snapshot.storage.k8s.io"
+	return &corev1.TypedLocalObjectReference{
+		APIGroup: &apiGroup,
+		Kind:     "VolumeSnapshot",
+		Name:     bootstrap.VolumeSnapshot.Name,
+	}
+}
+
+// getDatabaseBootstrapInitContainer assembles the init container that restores the blockchain database from a PersistentVolumeClaim or URL
+func (r *ChiaNodeReconciler) getDatabaseBootstrapInitContainer(ctx context.Context, node k8schianetv1.ChiaNode, secContext *corev1.SecurityContext, pullPolicy corev1.PullPolicy) corev1.Container {
+	bootstrap := node.Spec.DatabaseBootstrap
+
+	var image = bootstrap.Image
+	if image == "" {
+		image = defaultDatabaseBootstrapImage
+	}
+
+	env := []corev1.EnvVar{
+		{
+			Name:  "CHIA_ROOT",
+			Value: "/chia-data",
+		},
+		{
+			Name:  "BOOTSTRAP_SHA256",
+			Value: bootstrap.SHA256,
+		},
+	}
+	mounts := []corev1.VolumeMount{
+		{
+			Name:      "chiaroot",
+			MountPath: "/chia-data",
+		},
+	}
+	if bootstrap.PersistentVolumeClaim != nil {
+		env = append(env, corev1.EnvVar{
+			Name:  "BOOTSTRAP_PATH",
+			Value: path.Join(databaseBootstrapPath, bootstrap.PersistentVolumeClaim.Path),
+		})
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      "db-bootstrap",
+			MountPath: databaseBootstrapPath,
+			ReadOnly:  true,
+		})
+	} else {
+		env = append(env, corev1.EnvVar{
+			Name:  "BOOTSTRAP_URL",
+			Value: bootstrap.URL.URL,
+		})
+	}
+
+	return corev1.Container{
+		Name:                     databaseBootstrapContainerName,
+		SecurityContext:          secContext,
+		Image:                    image,
+		ImagePullPolicy:          pullPolicy,
+		Command:                  []string{"/bin/sh", "-c", databaseBootstrapScript},
+		Env:                      env,
+		VolumeMounts:             mounts,
+		TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+	}
+}
+
+// updateDatabaseBootstrapStatus reports the progress of each replica's database bootstrap init container in the ChiaNode's status
+func (r *ChiaNodeReconciler) updateDatabaseBootstrapStatus(ctx context.Context, node *k8schianetv1.ChiaNode) {
+	if !databaseBootstrapInitContainerEnabled(node.Spec.DatabaseBootstrap) {
+		node.Status.DatabaseBootstrap = nil
+		return
+	}
+
+	var pods corev1.PodList
+	err := r.List(ctx, &pods, client.InNamespace(node.Namespace), client.MatchingLabels(r.getCommonLabels(ctx, *node)))
+	if err != nil {
+		log.FromContext(ctx).Error(err, fmt.Sprintf("ChiaNodeReconciler ChiaNode=%s unable to list pods for database bootstrap status", node.Name))
+		return
+	}
+	sort.Slice(pods.Items, func(i, j int) bool {
+		return pods.Items[i].Name < pods.Items[j].Name
+	})
+
+	var statuses []k8schianetv1.ChiaNodeDatabaseBootstrapStatus
+	for _, pod := range pods.Items {
+		statuses = append(statuses, getDatabaseBootstrapStatus(pod))
+	}
+	node.Status.DatabaseBootstrap = statuses
+}
+
+// getDatabaseBootstrapStatus derives a replica's database bootstrap phase from its init container's state and termination message
+func getDatabaseBootstrapStatus(pod corev1.Pod) k8schianetv1.ChiaNodeDatabaseBootstrapStatus {
+	status := k8schianetv1.ChiaNodeDatabaseBootstrapStatus{
+		PodName: pod.Name,
+		Phase:   k8schianetv1.ChiaNodeDatabaseBootstrapPending,
+	}
+
+	for _, container := range pod.Status.InitContainerStatuses {
+		if container.Name != databaseBootstrapContainerName {
+			continue
+		}
+
+		switch {
+		case container.State.Terminated != nil:
+			status.Message = strings.TrimSpace(container.State.Terminated.Message)
+			switch {
+			case container.State.Terminated.ExitCode != 0:
+				status.Phase = k8schianetv1.ChiaNodeDatabaseBootstrapFailed
+			case strings.HasPrefix(status.Message, "Skipped"):
+				status.Phase = k8schianetv1.ChiaNodeDatabaseBootstrapSkipped
+			default:
+				status.Phase = k8schianetv1.ChiaNodeDatabaseBootstrapCompleted
+			}
+		case container.State.Running != nil:
+			status.Phase = k8schianetv1.ChiaNodeDatabaseBootstrapRestoring
+		case container.LastTerminationState.Terminated != nil && container.LastTerminationState.Terminated.ExitCode != 0:
+			status.Phase = k8schianetv1.ChiaNodeDatabaseBootstrapFailed
+			status.Message = strings.TrimSpace(container.LastTerminationState.Terminated.Message)
+		}
+	}
+	return status
+}
@@ -6,13 +6,26 @@ package controller
 
 import (
 	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"time"
 
 	apiv1 "github.com/chia-network/chia-operator/api/v1"
+	"github.com/chia-network/chia-operator/internal/chiarpc"
+	"github.com/chia-network/chia-operator/internal/chiarpc/chiarpctest"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
 )
 
 // +kubebuilder:docs-gen:collapse=Imports
@@ -86,4 +99,608 @@ var _ = Describe("ChiaNode controller", func() {
 			Expect(*createdChiaNode.Spec.ChiaConfig.Timezone).Should(Equal(timezone))
 		})
 	})
+
+	Context("When gathering ChiaNode replica status", func() {
+		It("Should report a synced replica's peak height and full_node peers", func() {
+			By("By pointing the reconciler at a fake full_node RPC server")
+			ctx := context.Background()
+			server, err := chiarpctest.NewServer()
+			Expect(err).NotTo(HaveOccurred())
+			defer server.Close()
+			server.SetResponse("get_blockchain_state", map[string]interface{}{
+				"blockchain_state": map[string]interface{}{
+					"peak":    map[string]interface{}{"height": 1234},
+					"sync":    map[string]interface{}{"synced": true},
+					"node_id": "abcd",
+				},
+			})
+			server.SetResponse("get_connections", map[string]interface{}{
+				"connections": []map[string]interface{}{
+					{"node_id": "peer1", "type": chiarpc.NodeTypeFullNode},
+					{"node_id": "peer2", "type": chiarpc.NodeTypeFullNode},
+					{"node_id": "wallet", "type": chiarpc.NodeTypeWallet},
+				},
+			})
+
+			originalHostPort := getChiaRPCHostPort
+			defer func() { getChiaRPCHostPort = originalHostPort }()
+			getChiaRPCHostPort = func(host string, port int) (string, int) {
+				return server.HostPort()
+			}
+
+			tlsConfig, err := chiarpc.NewTLSConfigFromSecretData(server.CASecretData())
+			Expect(err).NotTo(HaveOccurred())
+
+			r := &ChiaNodeReconciler{}
+			pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test-chianode-node-0"}}
+			replica := r.getReplicaStatus(ctx, pod, tlsConfig, getDatabaseVersion(map[string]string{}, ""))
+			Expect(replica.PodName).Should(Equal("test-chianode-node-0"))
+			Expect(replica.NodeID).Should(Equal("abcd"))
+			Expect(replica.PeakHeight).Should(Equal(int64(1234)))
+			Expect(replica.SyncMode).Should(Equal(apiv1.ChiaNodeSynced))
+			Expect(replica.SyncProgressPercent).Should(Equal(int32(100)))
+			Expect(replica.Peers).Should(Equal(int32(2)))
+			Expect(replica.DatabaseVersion).Should(Equal(int32(2)))
+		})
+
+		It("Should read the database version from the configured database_path", func() {
+			Expect(getDatabaseVersion(map[string]string{}, "")).Should(Equal(int32(2)))
+
+			inline := "full_node:\n  database_path: db/blockchain_v1_CHALLENGE.sqlite\n"
+			Expect(getDatabaseVersion(map[string]string{chiaConfigOverridesInlineKey: inline}, "")).Should(Equal(int32(1)))
+
+			// Inline overrides are merged after the referenced ConfigMap's document
+			ref := "full_node:\n  database_path: /data/blockchain_v3_mainnet.sqlite\n"
+			Expect(getDatabaseVersion(map[string]string{chiaConfigOverridesInlineKey: inline}, ref)).Should(Equal(int32(1)))
+			Expect(getDatabaseVersion(map[string]string{}, ref)).Should(Equal(int32(3)))
+
+			Expect(getDatabaseVersion(map[string]string{chiaConfigOverridesInlineKey: "full_node:\n  database_path: db/chain.sqlite\n"}, "")).Should(Equal(int32(0)))
+		})
+	})
+
+	Context("When reporting ChiaNode volume expansion", func() {
+		It("Should report each claim's resize progress and summarize it in the VolumesExpanded condition", func() {
+			pvc := corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "chiaroot-test-chianode-node-0"},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("400Gi")},
+					},
+				},
+				Status: corev1.PersistentVolumeClaimStatus{
+					Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("300Gi")},
+				},
+			}
+			volume := getVolumeStatus(pvc)
+			Expect(volume.State).Should(Equal(apiv1.ChiaNodeVolumeResizing))
+			Expect(volume.Requested.String()).Should(Equal("400Gi"))
+			Expect(volume.Capacity.String()).Should(Equal("300Gi"))
+
+			pvc.Status.Conditions = []corev1.PersistentVolumeClaimCondition{
+				{Type: corev1.PersistentVolumeClaimFileSystemResizePending, Status: corev1.ConditionTrue},
+			}
+			Expect(getVolumeStatus(pvc).State).Should(Equal(apiv1.ChiaNodeVolumeFileSystemResizePending))
+
+			pvc.Status.Conditions = nil
+			pvc.Status.Capacity[corev1.ResourceStorage] = resource.MustParse("400Gi")
+			ready := getVolumeStatus(pvc)
+			Expect(ready.State).Should(Equal(apiv1.ChiaNodeVolumeReady))
+
+			condition := getVolumesExpandedCondition(1, []apiv1.ChiaNodeVolumeStatus{ready}, nil)
+			Expect(condition.Status).Should(Equal(metav1.ConditionTrue))
+
+			notSupported := apiv1.ChiaNodeVolumeStatus{ClaimName: "chiaroot-test-chianode-node-1", State: apiv1.ChiaNodeVolumeExpansionNotSupported}
+			condition = getVolumesExpandedCondition(1, []apiv1.ChiaNodeVolumeStatus{ready, notSupported}, nil)
+			Expect(condition.Status).Should(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).Should(Equal("ExpansionNotSupported"))
+		})
+
+		It("Should parse the CHIA_ROOT resourceRequest once and reject an unparsable one", func() {
+			storage := &apiv1.StorageConfig{
+				ChiaRoot: &apiv1.ChiaRootConfig{
+					PersistentVolumeClaim: &apiv1.PersistentVolumeClaimConfig{ResourceRequest: "lots"},
+				},
+			}
+			_, err := getChiaRootResourceRequest(storage)
+			Expect(err).Should(HaveOccurred())
+
+			storage.ChiaRoot.PersistentVolumeClaim.ResourceRequest = "400Gi"
+			request, err := getChiaRootResourceRequest(storage)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(request.String()).Should(Equal("400Gi"))
+
+			request, err = getChiaRootResourceRequest(nil)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(request.IsZero()).Should(BeTrue())
+		})
+	})
+
+	Context("When ChiaNode changes require immutable StatefulSet fields to change", func() {
+		It("Should report the changed immutable fields and ignore volume size increases", func() {
+			fast := "fast"
+			slow := "slow"
+			existing := appsv1.StatefulSet{
+				Spec: appsv1.StatefulSetSpec{
+					Selector:    &metav1.LabelSelector{MatchLabels: map[string]string{"app": "node"}},
+					ServiceName: "test-chianode-node-headless",
+					VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+						{
+							ObjectMeta: metav1.ObjectMeta{Name: "chiaroot"},
+							Spec: corev1.PersistentVolumeClaimSpec{
+								AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+								StorageClassName: &slow,
+								Resources: corev1.ResourceRequirements{
+									Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("300Gi")},
+								},
+							},
+						},
+					},
+				},
+			}
+
+			desired := *existing.DeepCopy()
+			desired.Spec.VolumeClaimTemplates[0].Spec.Resources.Requests[corev1.ResourceStorage] = resource.MustParse("400Gi")
+			Expect(getStatefulSetImmutableChanges(existing, desired)).Should(BeEmpty())
+
+			desired.Spec.VolumeClaimTemplates[0].Spec.StorageClassName = &fast
+			desired.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "node", "tier": "db"}}
+			Expect(getStatefulSetImmutableChanges(existing, desired)).Should(Equal([]string{"selector", "volumeClaimTemplates"}))
+
+			keepStatefulSetImmutableFields(existing, &desired)
+			Expect(getStatefulSetImmutableChanges(existing, desired)).Should(BeEmpty())
+		})
+
+		It("Should only warn about changed immutable fields when the StatefulSetUpToDate condition changes", func() {
+			recorder := record.NewFakeRecorder(10)
+			r := &ChiaNodeReconciler{Recorder: recorder}
+			node := &apiv1.ChiaNode{ObjectMeta: metav1.ObjectMeta{Name: chiaNodeName, Namespace: chiaNodeNamespace, Generation: 2}}
+
+			r.updateStatefulSetUpToDateCondition(node, "test-chianode-node", []string{"volumeClaimTemplates"})
+			condition := meta.FindStatusCondition(node.Status.Conditions, apiv1.ChiaNodeConditionStatefulSetUpToDate)
+			Expect(condition.Status).Should(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).Should(Equal("ImmutableFieldsChanged"))
+			Expect(recorder.Events).Should(HaveLen(1))
+			Expect(<-recorder.Events).Should(ContainSubstring("ImmutableFieldsChanged"))
+
+			r.updateStatefulSetUpToDateCondition(node, "test-chianode-node", []string{"volumeClaimTemplates"})
+			Expect(recorder.Events).Should(BeEmpty())
+
+			r.updateStatefulSetUpToDateCondition(node, "test-chianode-node", []string{"selector", "volumeClaimTemplates"})
+			Expect(recorder.Events).Should(HaveLen(1))
+			<-recorder.Events
+
+			r.updateStatefulSetUpToDateCondition(node, "test-chianode-node", nil)
+			Expect(meta.IsStatusConditionTrue(node.Status.Conditions, apiv1.ChiaNodeConditionStatefulSetUpToDate)).Should(BeTrue())
+			Expect(recorder.Events).Should(BeEmpty())
+		})
+
+		It("Should not delete or report recreating a StatefulSet that is already being deleted", func() {
+			recorder := record.NewFakeRecorder(10)
+			r := &ChiaNodeReconciler{Client: fake.NewClientBuilder().Build(), Recorder: recorder}
+			node := &apiv1.ChiaNode{ObjectMeta: metav1.ObjectMeta{Name: chiaNodeName, Namespace: chiaNodeNamespace}}
+			now := metav1.Now()
+			existing := appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-chianode-node", Namespace: chiaNodeNamespace, DeletionTimestamp: &now},
+			}
+
+			Expect(r.recreateStatefulSet(context.Background(), node, existing, []string{"volumeClaimTemplates"})).Should(Succeed())
+			Expect(recorder.Events).Should(BeEmpty())
+
+			existing.DeletionTimestamp = nil
+			Expect(r.Create(context.Background(), &existing)).Should(Succeed())
+			Expect(r.recreateStatefulSet(context.Background(), node, existing, []string{"volumeClaimTemplates"})).Should(Succeed())
+			Expect(recorder.Events).Should(HaveLen(1))
+			Expect(<-recorder.Events).Should(ContainSubstring("StatefulSetRecreated"))
+		})
+	})
+
+	Context("When bootstrapping a ChiaNode database", func() {
+		It("Should extract archives copied from a PersistentVolumeClaim", func() {
+			dir := GinkgoT().TempDir()
+			Expect(os.MkdirAll(filepath.Join(dir, "src", "db"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "src", "db", "blockchain_v2_mainnet.sqlite"), []byte("db"), 0644)).To(Succeed())
+			Expect(exec.Command("tar", "-czf", filepath.Join(dir, "db.tar.gz"), "-C", filepath.Join(dir, "src"), "db").Run()).To(Succeed())
+
+			script := strings.ReplaceAll(databaseBootstrapScript, "/dev/termination-log", filepath.Join(dir, "termination-log"))
+			cmd := exec.Command("/bin/sh", "-c", script)
+			cmd.Env = append(os.Environ(), "CHIA_ROOT="+filepath.Join(dir, "chia-data"), "BOOTSTRAP_PATH="+filepath.Join(dir, "db.tar.gz"))
+			out, err := cmd.CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), string(out))
+			Expect(filepath.Join(dir, "chia-data", "db", "blockchain_v2_mainnet.sqlite")).Should(BeARegularFile())
+			Expect(filepath.Join(dir, "chia-data", "db", ".bootstrap")).ShouldNot(BeAnExistingFile())
+		})
+
+		It("Should treat a VolumeSnapshot data source added to an existing StatefulSet as an immutable change", func() {
+			existing := appsv1.StatefulSet{
+				Spec: appsv1.StatefulSetSpec{
+					VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+						{ObjectMeta: metav1.ObjectMeta{Name: "chiaroot"}},
+					},
+				},
+			}
+			snapshot := getDatabaseBootstrapDataSource(&apiv1.ChiaNodeDatabaseBootstrapSpec{
+				VolumeSnapshot: &apiv1.DatabaseBootstrapVolumeSnapshotSource{Name: "chiaroot-snapshot"},
+			})
+			Expect(volumeClaimTemplatesHaveDataSource(existing.Spec.VolumeClaimTemplates, snapshot)).Should(BeFalse())
+
+			desired := *existing.DeepCopy()
+			desired.Spec.VolumeClaimTemplates[0].Spec.DataSource = snapshot
+			Expect(getStatefulSetImmutableChanges(existing, desired)).Should(Equal([]string{"volumeClaimTemplates"}))
+			Expect(volumeClaimTemplatesHaveDataSource(desired.Spec.VolumeClaimTemplates, snapshot)).Should(BeTrue())
+		})
+
+		It("Should only warn that a VolumeSnapshot isn't applied when the DatabaseBootstrapApplied condition changes", func() {
+			recorder := record.NewFakeRecorder(10)
+			r := &ChiaNodeReconciler{Recorder: recorder}
+			node := &apiv1.ChiaNode{
+				ObjectMeta: metav1.ObjectMeta{Name: chiaNodeName, Namespace: chiaNodeNamespace},
+				Spec: apiv1.ChiaNodeSpec{
+					DatabaseBootstrap: &apiv1.ChiaNodeDatabaseBootstrapSpec{
+						VolumeSnapshot: &apiv1.DatabaseBootstrapVolumeSnapshotSource{Name: "chiaroot-snapshot"},
+					},
+				},
+			}
+			existing := appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-chianode-node"},
+				Spec: appsv1.StatefulSetSpec{
+					VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+						{ObjectMeta: metav1.ObjectMeta{Name: "chiaroot"}},
+					},
+				},
+			}
+
+			r.updateDatabaseBootstrapAppliedCondition(node, existing)
+			condition := meta.FindStatusCondition(node.Status.Conditions, apiv1.ChiaNodeConditionDatabaseBootstrapApplied)
+			Expect(condition.Status).Should(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).Should(Equal("DatabaseBootstrapNotApplied"))
+			Expect(recorder.Events).Should(HaveLen(1))
+			Expect(<-recorder.Events).Should(ContainSubstring("DatabaseBootstrapNotApplied"))
+
+			r.updateDatabaseBootstrapAppliedCondition(node, existing)
+			Expect(recorder.Events).Should(BeEmpty())
+
+			existing.Spec.VolumeClaimTemplates[0].Spec.DataSource = getDatabaseBootstrapDataSource(node.Spec.DatabaseBootstrap)
+			r.updateDatabaseBootstrapAppliedCondition(node, existing)
+			Expect(meta.IsStatusConditionTrue(node.Status.Conditions, apiv1.ChiaNodeConditionDatabaseBootstrapApplied)).Should(BeTrue())
+			Expect(recorder.Events).Should(BeEmpty())
+
+			node.Spec.DatabaseBootstrap = nil
+			r.updateDatabaseBootstrapAppliedCondition(node, existing)
+			Expect(meta.FindStatusCondition(node.Status.Conditions, apiv1.ChiaNodeConditionDatabaseBootstrapApplied)).Should(BeNil())
+		})
+	})
+
+	Context("When reporting ChiaNode database bootstrap progress", func() {
+		It("Should derive each replica's phase from its db-bootstrap init container", func() {
+			pod := corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-chianode-node-0"},
+			}
+			Expect(getDatabaseBootstrapStatus(pod).Phase).Should(Equal(apiv1.ChiaNodeDatabaseBootstrapPending))
+
+			pod.Status.InitContainerStatuses = []corev1.ContainerStatus{
+				{
+					Name:  databaseBootstrapContainerName,
+					State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+				},
+			}
+			Expect(getDatabaseBootstrapStatus(pod).Phase).Should(Equal(apiv1.ChiaNodeDatabaseBootstrapRestoring))
+
+			pod.Status.InitContainerStatuses[0].State = corev1.ContainerState{}
+			pod.Status.InitContainerStatuses[0].LastTerminationState = corev1.ContainerState{
+				Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Message: "Failed: SHA-256 checksum mismatch\n"},
+			}
+			Expect(getDatabaseBootstrapStatus(pod)).Should(Equal(apiv1.ChiaNodeDatabaseBootstrapStatus{
+				PodName: "test-chianode-node-0",
+				Phase:   apiv1.ChiaNodeDatabaseBootstrapFailed,
+				Message: "Failed: SHA-256 checksum mismatch",
+			}))
+
+			pod.Status.InitContainerStatuses[0].State = corev1.ContainerState{
+				Terminated: &corev1.ContainerStateTerminated{ExitCode: 0, Message: "Skipped: /chia-data/db already has a blockchain database\n"},
+			}
+			Expect(getDatabaseBootstrapStatus(pod).Phase).Should(Equal(apiv1.ChiaNodeDatabaseBootstrapSkipped))
+
+			pod.Status.InitContainerStatuses[0].State.Terminated.Message = "Completed: restored blockchain_v2_mainnet.sqlite\n"
+			Expect(getDatabaseBootstrapStatus(pod).Phase).Should(Equal(apiv1.ChiaNodeDatabaseBootstrapCompleted))
+		})
+	})
+
+	Context("When exposing ChiaNode ports through Gateway API routes", func() {
+		It("Should route each exposed port to the node Service", func() {
+			ctx := context.Background()
+			gatewayNamespace := "gateways"
+			node := apiv1.ChiaNode{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      chiaNodeName,
+					Namespace: chiaNodeNamespace,
+				},
+				Spec: apiv1.ChiaNodeSpec{
+					ChiaConfig: apiv1.ChiaNodeConfigSpec{
+						Testnet: &testnet,
+					},
+					Expose: &apiv1.ExposeConfig{
+						Peers: &apiv1.PortExposureConfig{
+							Gateway: &apiv1.GatewayRouteConfig{
+								Enabled:    true,
+								Kind:       apiv1.GatewayTLSRoute,
+								ParentRefs: []apiv1.GatewayParentReference{{Name: "public", Namespace: &gatewayNamespace}},
+								Hostnames:  []string{"node.example.com"},
+							},
+						},
+						RPC: &apiv1.PortExposureConfig{
+							Gateway: &apiv1.GatewayRouteConfig{
+								Enabled:    true,
+								ParentRefs: []apiv1.GatewayParentReference{{Name: "internal"}},
+							},
+						},
+					},
+				},
+			}
+
+			r := &ChiaNodeReconciler{}
+			exposures := r.getPortExposures(ctx, node)
+			Expect(exposures).Should(HaveLen(2))
+
+			peers := exposures[0]
+			route := getGatewayRoute(peers, apiv1.GatewayTLSRoute)
+			Expect(route.GetKind()).Should(Equal("TLSRoute"))
+			Expect(route.GetName()).Should(Equal("test-chianode-node-peers"))
+			Expect(route.Object["spec"]).Should(HaveKeyWithValue("hostnames", []interface{}{"node.example.com"}))
+			Expect(route.Object["spec"]).Should(HaveKeyWithValue("parentRefs", []interface{}{
+				map[string]interface{}{"name": "public", "namespace": gatewayNamespace},
+			}))
+			Expect(route.Object["spec"]).Should(HaveKeyWithValue("rules", []interface{}{
+				map[string]interface{}{
+					"backendRefs": []interface{}{
+						map[string]interface{}{"name": "test-chianode-node", "port": int64(testnetNodePort)},
+					},
+				},
+			}))
+
+			rpc := exposures[1]
+			Expect(getGatewayRouteKind(rpc.config.Gateway)).Should(Equal(apiv1.GatewayTCPRoute))
+			route = getGatewayRoute(rpc, apiv1.GatewayTCPRoute)
+			Expect(route.GetKind()).Should(Equal("TCPRoute"))
+			Expect(route.GetName()).Should(Equal("test-chianode-node-rpc"))
+			Expect(route.Object["spec"]).ShouldNot(HaveKey("hostnames"))
+			Expect(route.Object["spec"]).Should(HaveKeyWithValue("rules", []interface{}{
+				map[string]interface{}{
+					"backendRefs": []interface{}{
+						map[string]interface{}{"name": "test-chianode-node-rpc", "port": int64(nodeRPCPort)},
+					},
+				},
+			}))
+		})
+	})
+
+	Context("When tuning ChiaNode load balancer and node port Services", func() {
+		It("Should apply the settings that the Service's type supports", func() {
+			ctx := context.Background()
+			local := corev1.ServiceExternalTrafficPolicyTypeLocal
+			lbClass := "io.cilium/bgp"
+			nodePort := int32(30444)
+			services := &apiv1.ChiaServicesConfig{
+				Peers: &apiv1.ChiaServiceConfig{
+					ExternalTrafficPolicy:    &local,
+					NodePort:                 &nodePort,
+					LoadBalancerIP:           "203.0.113.10",
+					LoadBalancerClass:        &lbClass,
+					LoadBalancerSourceRanges: []string{"0.0.0.0/0"},
+				},
+			}
+			node := apiv1.ChiaNode{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      chiaNodeName,
+					Namespace: chiaNodeNamespace,
+				},
+				Spec: apiv1.ChiaNodeSpec{
+					ServiceType: "LoadBalancer",
+					Services:    services,
+				},
+			}
+
+			r := &ChiaNodeReconciler{}
+			srv := r.assembleBaseService(ctx, node)
+			Expect(srv.Spec.ExternalTrafficPolicy).Should(Equal(local))
+			Expect(srv.Spec.Ports[0].NodePort).Should(Equal(nodePort))
+			Expect(srv.Spec.LoadBalancerIP).Should(Equal("203.0.113.10"))
+			Expect(*srv.Spec.LoadBalancerClass).Should(Equal(lbClass))
+			Expect(srv.Spec.LoadBalancerSourceRanges).Should(Equal([]string{"0.0.0.0/0"}))
+
+			node.Spec.ServiceType = "NodePort"
+			srv = r.assembleBaseService(ctx, node)
+			Expect(srv.Spec.Ports[0].NodePort).Should(Equal(nodePort))
+			Expect(srv.Spec.LoadBalancerClass).Should(BeNil())
+
+			node.Spec.ServiceType = "ClusterIP"
+			srv = r.assembleBaseService(ctx, node)
+			Expect(srv.Spec.Ports[0].NodePort).Should(BeZero())
+			Expect(srv.Spec.ExternalTrafficPolicy).Should(BeEmpty())
+		})
+	})
+
+	Context("When running a ChiaNode on an IPv6 or dual-stack cluster", func() {
+		It("Should set the IP families on every Service and prefer IPv6 peers", func() {
+			ctx := context.Background()
+			requireDualStack := corev1.IPFamilyPolicyRequireDualStack
+			node := apiv1.ChiaNode{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      chiaNodeName,
+					Namespace: chiaNodeNamespace,
+				},
+				Spec: apiv1.ChiaNodeSpec{
+					ServiceType: "LoadBalancer",
+					Services: &apiv1.ChiaServicesConfig{
+						IPFamilies:     []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol},
+						IPFamilyPolicy: &requireDualStack,
+					},
+				},
+			}
+
+			r := &ChiaNodeReconciler{}
+			for _, srv := range []corev1.Service{
+				r.assembleBaseService(ctx, node),
+				r.assembleDaemonService(ctx, node),
+				r.assembleRPCService(ctx, node),
+				r.assembleInternalService(ctx, node),
+				r.assembleHeadlessService(ctx, node),
+				r.assembleChiaExporterService(ctx, node),
+			} {
+				Expect(srv.Spec.IPFamilies).Should(Equal([]corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol}))
+				Expect(*srv.Spec.IPFamilyPolicy).Should(Equal(requireDualStack))
+			}
+
+			override, err := getIPFamiliesOverride("full_node", node.Spec.Services.IPFamilies)
+			Expect(err).NotTo(HaveOccurred())
+			var config map[string]interface{}
+			Expect(yaml.Unmarshal([]byte(override), &config)).To(Succeed())
+			Expect(config).Should(Equal(map[string]interface{}{
+				"full_node":     map[string]interface{}{"prefer_ipv6": true},
+				"self_hostname": "::",
+			}))
+
+			override, err = getIPFamiliesOverride("full_node", []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(override).Should(Equal("full_node:\n  prefer_ipv6: false\n"))
+
+			// Pods restart when the IP family settings change
+			overrides := map[string]string{chiaIPFamiliesKey: "prefer_ipv6: true\n"}
+			stateful := r.assembleStatefulset(ctx, node, overrides, "", resource.Quantity{})
+			Expect(stateful.Spec.Template.Annotations).Should(HaveKey(ipFamiliesChecksumAnnotation))
+		})
+	})
+
+	Context("When merging ChiaNode config.yaml overrides", func() {
+		It("Should render the inline document and restart pods when either override document changes", func() {
+			ctx := context.Background()
+			inline := "full_node:\n  target_peer_count: 40\n"
+			refDoc := "logging:\n  log_maxfilesrotation: 10\n"
+			node := apiv1.ChiaNode{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      chiaNodeName,
+					Namespace: chiaNodeNamespace,
+				},
+				Spec: apiv1.ChiaNodeSpec{
+					ChiaConfig: apiv1.ChiaNodeConfigSpec{
+						ConfigOverrides: &apiv1.ChiaConfigOverridesSpec{
+							Inline: inline,
+							ConfigMapRef: &corev1.ConfigMapKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "chia-config-overrides"},
+								Key:                  "config.yaml",
+							},
+						},
+					},
+				},
+			}
+
+			_, err := getChiaConfigOverridesData(ctx, &apiv1.ChiaConfigOverridesSpec{Inline: "- not a map"})
+			Expect(err).To(HaveOccurred())
+			overrides, err := getChiaConfigOverridesData(ctx, node.Spec.ChiaConfig.ConfigOverrides)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(overrides).Should(Equal(map[string]string{chiaConfigOverridesInlineKey: inline}))
+
+			c := fake.NewClientBuilder().WithObjects(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "chia-config-overrides",
+					Namespace: chiaNodeNamespace,
+				},
+				Data: map[string]string{"config.yaml": refDoc},
+			}).Build()
+			overridesRef, err := getChiaConfigOverridesRefData(ctx, c, chiaNodeNamespace, node.Spec.ChiaConfig.ConfigOverrides)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(overridesRef).Should(Equal(refDoc))
+
+			r := &ChiaNodeReconciler{}
+			stateful := r.assembleStatefulset(ctx, node, overrides, overridesRef, resource.Quantity{})
+			annotations := stateful.Spec.Template.Annotations
+			Expect(annotations).Should(HaveKey(configOverridesChecksumAnnotation))
+			Expect(annotations).Should(HaveKey(configOverridesRefChecksumAnnotation))
+
+			// Editing either document changes only its own checksum
+			edited := r.assembleStatefulset(ctx, node, overrides, "logging:\n  log_maxfilesrotation: 5\n", resource.Quantity{})
+			Expect(edited.Spec.Template.Annotations[configOverridesRefChecksumAnnotation]).ShouldNot(Equal(annotations[configOverridesRefChecksumAnnotation]))
+			Expect(edited.Spec.Template.Annotations[configOverridesChecksumAnnotation]).Should(Equal(annotations[configOverridesChecksumAnnotation]))
+			edited = r.assembleStatefulset(ctx, node, map[string]string{chiaConfigOverridesInlineKey: "full_node:\n  target_peer_count: 80\n"}, overridesRef, resource.Quantity{})
+			Expect(edited.Spec.Template.Annotations[configOverridesChecksumAnnotation]).ShouldNot(Equal(annotations[configOverridesChecksumAnnotation]))
+
+			// A missing ConfigMap is only an error when the reference isn't optional
+			node.Spec.ChiaConfig.ConfigOverrides.ConfigMapRef.Name = "missing"
+			_, err = getChiaConfigOverridesRefData(ctx, c, chiaNodeNamespace, node.Spec.ChiaConfig.ConfigOverrides)
+			Expect(err).To(HaveOccurred())
+			optional := true
+			node.Spec.ChiaConfig.ConfigOverrides.ConfigMapRef.Optional = &optional
+			overridesRef, err = getChiaConfigOverridesRefData(ctx, c, chiaNodeNamespace, node.Spec.ChiaConfig.ConfigOverrides)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(overridesRef).Should(BeEmpty())
+		})
+	})
+
+	Context("When configuring the ChiaNode chia-exporter sidecar", func() {
+		It("Should render the sidecar with its own settings, and leave it out when disabled", func() {
+			ctx := context.Background()
+			runAsUser := int64(1000)
+			exporterUser := int64(2000)
+			pullAlways := corev1.PullAlways
+			node := apiv1.ChiaNode{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      chiaNodeName,
+					Namespace: chiaNodeNamespace,
+				},
+				Spec: apiv1.ChiaNodeSpec{
+					ChiaConfig: apiv1.ChiaNodeConfigSpec{
+						SecurityContext: &corev1.SecurityContext{RunAsUser: &runAsUser},
+						Resources: &corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("8Gi")},
+						},
+					},
+				},
+			}
+			r := &ChiaNodeReconciler{}
+			exporterContainer := func(stateful appsv1.StatefulSet) *corev1.Container {
+				for i, container := range stateful.Spec.Template.Spec.Containers {
+					if container.Name == "chia-exporter" {
+						return &stateful.Spec.Template.Spec.Containers[i]
+					}
+				}
+				return nil
+			}
+
+			// By default the sidecar shares the chia container's security context, but not its resources
+			exporter := exporterContainer(r.assembleStatefulset(ctx, node, map[string]string{}, "", resource.Quantity{}))
+			Expect(exporter).NotTo(BeNil())
+			Expect(exporter.Image).Should(Equal(defaultChiaExporterImage))
+			Expect(exporter.SecurityContext.RunAsUser).Should(Equal(&runAsUser))
+			Expect(exporter.Resources).Should(Equal(corev1.ResourceRequirements{}))
+			Expect(exporter.LivenessProbe.HTTPGet.Port.IntValue()).Should(Equal(chiaExporterPort))
+
+			node.Spec.ChiaExporterConfig = apiv1.ChiaExporterConfigSpec{
+				ImagePullPolicy: &pullAlways,
+				MetricsPort:     9915,
+				Args:            []string{"serve"},
+				Env:             []corev1.EnvVar{{Name: "CHIA_EXPORTER_LOG_LEVEL", Value: "debug"}},
+				Resources: &corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("64Mi")},
+				},
+				SecurityContext: &corev1.SecurityContext{RunAsUser: &exporterUser},
+			}
+			exporter = exporterContainer(r.assembleStatefulset(ctx, node, map[string]string{}, "", resource.Quantity{}))
+			Expect(exporter).NotTo(BeNil())
+			Expect(exporter.ImagePullPolicy).Should(Equal(corev1.PullAlways))
+			Expect(exporter.Args).Should(Equal([]string{"serve"}))
+			Expect(exporter.Env).Should(ContainElement(corev1.EnvVar{Name: "CHIA_EXPORTER_METRICS_PORT", Value: "9915"}))
+			Expect(exporter.Env).Should(ContainElement(corev1.EnvVar{Name: "CHIA_EXPORTER_LOG_LEVEL", Value: "debug"}))
+			Expect(exporter.Ports[0].ContainerPort).Should(Equal(int32(9915)))
+			Expect(exporter.LivenessProbe.HTTPGet.Port.IntValue()).Should(Equal(9915))
+			Expect(exporter.ReadinessProbe.HTTPGet.Port.IntValue()).Should(Equal(9915))
+			Expect(exporter.StartupProbe.HTTPGet.Port.IntValue()).Should(Equal(9915))
+			Expect(r.assembleChiaExporterService(ctx, node).Spec.Ports[0].Port).Should(Equal(int32(9915)))
+			Expect(exporter.Resources.Requests.Memory().String()).Should(Equal("64Mi"))
+			Expect(exporter.SecurityContext.RunAsUser).Should(Equal(&exporterUser))
+
+			disabled := false
+			node.Spec.ChiaExporterConfig.Enabled = &disabled
+			Expect(exporterContainer(r.assembleStatefulset(ctx, node, map[string]string{}, "", resource.Quantity{}))).To(BeNil())
+			Expect(chiaExporterEnabled(node.Spec.ChiaExporterConfig)).Should(BeFalse())
+		})
+	})
 })
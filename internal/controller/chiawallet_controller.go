@@ -7,19 +7,33 @@ package controller
 import (
 	"context"
 	"fmt"
+	"net"
+	"sort"
+	"strconv"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/yaml"
 
 	k8schianetv1 "github.com/chia-network/chia-operator/api/v1"
+	"github.com/chia-network/chia-operator/internal/chiarpc"
 	"github.com/cisco-open/operator-tools/pkg/reconciler"
 )
 
@@ -31,6 +45,21 @@ const (
 	walletRPCPort = 9256
 )
 
+const (
+	// chiaWalletTrustedPeersKey is the config overrides ConfigMap key the wallet's trusted_peers are rendered to, merged after any user overrides
+	chiaWalletTrustedPeersKey = "30-trusted-peers.yaml"
+
+	// trustedPeersChecksumAnnotation is set on wallet pods to restart them when their trusted peers change
+	trustedPeersChecksumAnnotation = "k8s.chia.net/trusted-peers-checksum"
+
+	// walletSelectKeyScript writes the selected fingerprint to the file the wallet reads the key to log into from when it starts,
+	// next to the wallet database in the directory of the default wallet.database_path
+	walletSelectKeyScript = `set -e
+mkdir -p "${CHIA_ROOT}/wallet/db"
+echo "${WALLET_FINGERPRINT}" > "${CHIA_ROOT}/wallet/db/last_used_fingerprint"
+`
+)
+
 // ChiaWalletReconciler reconciles a ChiaWallet object
 type ChiaWalletReconciler struct {
 	client.Client
@@ -42,6 +71,14 @@ type ChiaWalletReconciler struct {
 //+kubebuilder:rbac:groups=k8s.chia.net,resources=chiawallets/finalizers,verbs=update
 //+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch
 //+kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=tcproutes;tlsroutes,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create
+//+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups=k8s.chia.net,resources=chianodes,verbs=get;list;watch
+//+kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors;prometheusrules,verbs=get;list;watch;create;update;patch;delete
 
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.15.0/pkg/reconcile
@@ -63,8 +100,9 @@ func (r *ChiaWalletReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	}
 
 	// Reconcile ChiaWallet owned objects
+	peersConfig, daemonConfig, rpcConfig := getServiceConfigs(wallet.Spec.Services)
 	service := r.assembleBaseService(ctx, wallet)
-	res, err := reconcileService(ctx, resourceReconciler, service)
+	res, err := reconcileService(ctx, resourceReconciler, service, serviceEnabled(peersConfig))
 	if err != nil {
 		if res == nil {
 			res = &reconcile.Result{}
@@ -72,8 +110,26 @@ func (r *ChiaWalletReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return *res, fmt.Errorf("ChiaWalletReconciler ChiaWallet=%s encountered error reconciling wallet Service: %v", req.NamespacedName, err)
 	}
 
+	service = r.assembleDaemonService(ctx, wallet)
+	res, err = reconcileService(ctx, resourceReconciler, service, serviceEnabled(daemonConfig))
+	if err != nil {
+		if res == nil {
+			res = &reconcile.Result{}
+		}
+		return *res, fmt.Errorf("ChiaWalletReconciler ChiaWallet=%s encountered error reconciling wallet daemon Service: %v", req.NamespacedName, err)
+	}
+
+	service = r.assembleRPCService(ctx, wallet)
+	res, err = reconcileService(ctx, resourceReconciler, service, serviceEnabled(rpcConfig))
+	if err != nil {
+		if res == nil {
+			res = &reconcile.Result{}
+		}
+		return *res, fmt.Errorf("ChiaWalletReconciler ChiaWallet=%s encountered error reconciling wallet RPC Service: %v", req.NamespacedName, err)
+	}
+
 	service = r.assembleChiaExporterService(ctx, wallet)
-	res, err = reconcileService(ctx, resourceReconciler, service)
+	res, err = reconcileService(ctx, resourceReconciler, service, chiaExporterEnabled(wallet.Spec.ChiaExporterConfig))
 	if err != nil {
 		if res == nil {
 			res = &reconcile.Result{}
@@ -81,8 +137,94 @@ func (r *ChiaWalletReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return *res, fmt.Errorf("ChiaWalletReconciler ChiaWallet=%s encountered error reconciling wallet chia-exporter Service: %v", req.NamespacedName, err)
 	}
 
-	deploy := r.assembleDeployment(ctx, wallet)
-	res, err = reconcileDeployment(ctx, resourceReconciler, deploy)
+	networkPolicy := r.assembleNetworkPolicy(ctx, wallet)
+	res, err = reconcileNetworkPolicy(ctx, resourceReconciler, networkPolicy, networkPolicyEnabled(wallet.Spec.NetworkPolicy))
+	if err != nil {
+		if res == nil {
+			res = &reconcile.Result{}
+		}
+		return *res, fmt.Errorf("ChiaWalletReconciler ChiaWallet=%s encountered error reconciling wallet NetworkPolicy: %v", req.NamespacedName, err)
+	}
+
+	for _, exposure := range r.getPortExposures(ctx, wallet) {
+		res, err = reconcilePortExposure(ctx, r.Client, resourceReconciler, exposure)
+		if err != nil {
+			if res == nil {
+				res = &reconcile.Result{}
+			}
+			return *res, fmt.Errorf("ChiaWalletReconciler ChiaWallet=%s encountered error reconciling wallet %s routes: %v", req.NamespacedName, exposure.objMeta.Name, err)
+		}
+	}
+
+	monitor := r.assembleChiaExporterServiceMonitor(ctx, wallet)
+	res, err = reconcileOptionalResource(ctx, r.Client, resourceReconciler, monitor, serviceMonitorEnabled(wallet.Spec.ChiaExporterConfig))
+	if err != nil {
+		if res == nil {
+			res = &reconcile.Result{}
+		}
+		return *res, fmt.Errorf("ChiaWalletReconciler ChiaWallet=%s encountered error reconciling wallet chia-exporter ServiceMonitor: %v", req.NamespacedName, err)
+	}
+
+	rule := r.assembleChiaExporterPrometheusRule(ctx, wallet)
+	res, err = reconcileOptionalResource(ctx, r.Client, resourceReconciler, rule, prometheusRuleEnabled(wallet.Spec.ChiaExporterConfig))
+	if err != nil {
+		if res == nil {
+			res = &reconcile.Result{}
+		}
+		return *res, fmt.Errorf("ChiaWalletReconciler ChiaWallet=%s encountered error reconciling wallet chia-exporter PrometheusRule: %v", req.NamespacedName, err)
+	}
+
+	overrides, err := getChiaConfigOverridesData(ctx, wallet.Spec.ChiaConfig.ConfigOverrides)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("ChiaWalletReconciler ChiaWallet=%s encountered error assembling config overrides: %v", req.NamespacedName, err)
+	}
+	overridesRef, err := getChiaConfigOverridesRefData(ctx, r.Client, wallet.Namespace, wallet.Spec.ChiaConfig.ConfigOverrides)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("ChiaWalletReconciler ChiaWallet=%s encountered error reading referenced config overrides: %v", req.NamespacedName, err)
+	}
+	trustedPeers, err := r.getTrustedPeers(ctx, wallet)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("ChiaWalletReconciler ChiaWallet=%s encountered error resolving trusted peers: %v", req.NamespacedName, err)
+	}
+	if len(trustedPeers) != 0 {
+		overrides[chiaWalletTrustedPeersKey], err = getTrustedPeersOverride(trustedPeers)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("ChiaWalletReconciler ChiaWallet=%s encountered error assembling trusted peers config: %v", req.NamespacedName, err)
+		}
+	}
+	wallet.Status.TrustedPeers = trustedPeers
+
+	if ipFamilies, _ := getServiceIPFamilies(wallet.Spec.Services); len(ipFamilies) != 0 {
+		overrides[chiaIPFamiliesKey], err = getIPFamiliesOverride("wallet", ipFamilies)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("ChiaWalletReconciler ChiaWallet=%s encountered error assembling IP family config: %v", req.NamespacedName, err)
+		}
+	}
+	configMap := r.assembleConfigOverridesConfigMap(ctx, wallet, overrides)
+	res, err = reconcileConfigMap(ctx, resourceReconciler, configMap, len(overrides) != 0)
+	if err != nil {
+		if res == nil {
+			res = &reconcile.Result{}
+		}
+		return *res, fmt.Errorf("ChiaWalletReconciler ChiaWallet=%s encountered error reconciling wallet config overrides ConfigMap: %v", req.NamespacedName, err)
+	}
+
+	if chiaRootClaimCreated(wallet.Spec.Storage) {
+		pvc, err := r.assembleChiaRootPersistentVolumeClaim(ctx, wallet)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("ChiaWalletReconciler ChiaWallet=%s encountered error assembling wallet CHIA_ROOT PersistentVolumeClaim: %v", req.NamespacedName, err)
+		}
+		res, err = reconcilePersistentVolumeClaim(ctx, resourceReconciler, pvc)
+		if err != nil {
+			if res == nil {
+				res = &reconcile.Result{}
+			}
+			return *res, fmt.Errorf("ChiaWalletReconciler ChiaWallet=%s encountered error reconciling wallet CHIA_ROOT PersistentVolumeClaim: %v", req.NamespacedName, err)
+		}
+	}
+
+	deploy := r.assembleDeployment(ctx, wallet, overrides, overridesRef)
+	res, err = reconcileDeployment(ctx, resourceReconciler, deploy, true)
 	if err != nil {
 		if res == nil {
 			res = &reconcile.Result{}
@@ -92,74 +234,156 @@ func (r *ChiaWalletReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 	// Update CR status
 	wallet.Status.Ready = true
+	r.updateWalletStatus(ctx, &wallet)
 	err = r.Status().Update(ctx, &wallet)
 	if err != nil {
 		log.Error(err, fmt.Sprintf("ChiaWalletReconciler ChiaWallet=%s unable to update ChiaWallet status", req.NamespacedName))
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: statusRefreshInterval}, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *ChiaWalletReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&k8schianetv1.ChiaWallet{}).
+		// Only spec changes trigger reconciles, status updates would otherwise requeue the wallet immediately instead of after statusRefreshInterval
+		For(&k8schianetv1.ChiaWallet{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(
+			&k8schianetv1.ChiaNode{},
+			handler.EnqueueRequestsFromMapFunc(r.walletsTrustingChiaNode),
+			builder.WithPredicates(replicaNodeIDsChangedPredicate),
+		).
 		Complete(r)
 }
 
-// reconcileBaseService reconciles the main Service resource for a ChiaWallet CR
+// walletsTrustingChiaNode returns a reconcile request for every ChiaWallet that trusts the ChiaNode, so new replica peer IDs are written to their trusted_peers
+func (r *ChiaWalletReconciler) walletsTrustingChiaNode(ctx context.Context, node client.Object) []reconcile.Request {
+	var wallets k8schianetv1.ChiaWalletList
+	err := r.List(ctx, &wallets, client.InNamespace(node.GetNamespace()))
+	if err != nil {
+		log.FromContext(ctx).Error(err, fmt.Sprintf("ChiaWalletReconciler unable to list ChiaWallets for ChiaNode %s", node.GetName()))
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, wallet := range wallets.Items {
+		trusted := wallet.Spec.ChiaConfig.TrustedNode
+		if trusted != nil && trusted.ChiaNodeName == node.GetName() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: wallet.Namespace, Name: wallet.Name},
+			})
+		}
+	}
+	return requests
+}
+
+// replicaNodeIDsChangedPredicate only passes ChiaNode updates that change its replicas' node IDs, the only part of a ChiaNode wallets read,
+// so routine ChiaNode status updates don't reconcile every wallet trusting it
+var replicaNodeIDsChangedPredicate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldNode, ok := e.ObjectOld.(*k8schianetv1.ChiaNode)
+		if !ok {
+			return true
+		}
+		newNode, ok := e.ObjectNew.(*k8schianetv1.ChiaNode)
+		if !ok {
+			return true
+		}
+		return !equality.Semantic.DeepEqual(getReplicaNodeIDs(*oldNode), getReplicaNodeIDs(*newNode))
+	},
+}
+
+// getReplicaNodeIDs returns the sorted node IDs reported by a ChiaNode's replicas
+func getReplicaNodeIDs(node k8schianetv1.ChiaNode) []string {
+	var ids []string
+	for _, replica := range node.Status.Replicas {
+		if replica.NodeID != "" {
+			ids = append(ids, replica.NodeID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// assembleBaseService assembles the main peer port Service resource for a ChiaWallet CR
 func (r *ChiaWalletReconciler) assembleBaseService(ctx context.Context, wallet k8schianetv1.ChiaWallet) corev1.Service {
-	return corev1.Service{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:            fmt.Sprintf("%s-wallet", wallet.Name),
-			Namespace:       wallet.Namespace,
-			Labels:          r.getCommonLabels(ctx, wallet, wallet.Spec.AdditionalMetadata.Labels),
-			Annotations:     wallet.Spec.AdditionalMetadata.Annotations,
-			OwnerReferences: r.getOwnerReference(ctx, wallet),
-		},
-		Spec: corev1.ServiceSpec{
-			Type: corev1.ServiceType(wallet.Spec.ServiceType),
-			Ports: []corev1.ServicePort{
-				{
-					Port:       daemonPort,
-					TargetPort: intstr.FromString("daemon"),
-					Protocol:   "TCP",
-					Name:       "daemon",
-				},
-				{
-					Port:       walletPort,
-					TargetPort: intstr.FromString("peers"),
-					Protocol:   "TCP",
-					Name:       "peers",
-				},
-				{
-					Port:       walletRPCPort,
-					TargetPort: intstr.FromString("rpc"),
-					Protocol:   "TCP",
-					Name:       "rpc",
-				},
-			},
-			Selector: r.getCommonLabels(ctx, wallet, wallet.Spec.AdditionalMetadata.Labels),
-		},
+	peers, _, _ := getServiceConfigs(wallet.Spec.Services)
+	objMeta := metav1.ObjectMeta{
+		Name:            fmt.Sprintf("%s-wallet", wallet.Name),
+		Namespace:       wallet.Namespace,
+		Labels:          r.getCommonLabels(ctx, wallet, wallet.Spec.AdditionalMetadata.Labels),
+		Annotations:     wallet.Spec.AdditionalMetadata.Annotations,
+		OwnerReferences: r.getOwnerReference(ctx, wallet),
 	}
+	srv := getPortService(objMeta, r.getCommonLabels(ctx, wallet, wallet.Spec.AdditionalMetadata.Labels), peers, corev1.ServiceType(wallet.Spec.ServiceType), corev1.ServicePort{
+		Port:       walletPort,
+		TargetPort: intstr.FromString("peers"),
+		Protocol:   "TCP",
+		Name:       "peers",
+	})
+	srv.Spec.IPFamilies, srv.Spec.IPFamilyPolicy = getServiceIPFamilies(wallet.Spec.Services)
+	return srv
+}
+
+// assembleDaemonService assembles the daemon port Service resource for a ChiaWallet CR
+func (r *ChiaWalletReconciler) assembleDaemonService(ctx context.Context, wallet k8schianetv1.ChiaWallet) corev1.Service {
+	_, daemon, _ := getServiceConfigs(wallet.Spec.Services)
+	objMeta := metav1.ObjectMeta{
+		Name:            fmt.Sprintf("%s-wallet-daemon", wallet.Name),
+		Namespace:       wallet.Namespace,
+		Labels:          r.getCommonLabels(ctx, wallet, wallet.Spec.AdditionalMetadata.Labels),
+		Annotations:     wallet.Spec.AdditionalMetadata.Annotations,
+		OwnerReferences: r.getOwnerReference(ctx, wallet),
+	}
+	srv := getPortService(objMeta, r.getCommonLabels(ctx, wallet, wallet.Spec.AdditionalMetadata.Labels), daemon, corev1.ServiceTypeClusterIP, corev1.ServicePort{
+		Port:       daemonPort,
+		TargetPort: intstr.FromString("daemon"),
+		Protocol:   "TCP",
+		Name:       "daemon",
+	})
+	srv.Spec.IPFamilies, srv.Spec.IPFamilyPolicy = getServiceIPFamilies(wallet.Spec.Services)
+	return srv
+}
+
+// assembleRPCService assembles the RPC port Service resource for a ChiaWallet CR
+func (r *ChiaWalletReconciler) assembleRPCService(ctx context.Context, wallet k8schianetv1.ChiaWallet) corev1.Service {
+	_, _, rpc := getServiceConfigs(wallet.Spec.Services)
+	objMeta := metav1.ObjectMeta{
+		Name:            fmt.Sprintf("%s-wallet-rpc", wallet.Name),
+		Namespace:       wallet.Namespace,
+		Labels:          r.getCommonLabels(ctx, wallet, wallet.Spec.AdditionalMetadata.Labels),
+		Annotations:     wallet.Spec.AdditionalMetadata.Annotations,
+		OwnerReferences: r.getOwnerReference(ctx, wallet),
+	}
+	srv := getPortService(objMeta, r.getCommonLabels(ctx, wallet, wallet.Spec.AdditionalMetadata.Labels), rpc, corev1.ServiceTypeClusterIP, corev1.ServicePort{
+		Port:       walletRPCPort,
+		TargetPort: intstr.FromString("rpc"),
+		Protocol:   "TCP",
+		Name:       "rpc",
+	})
+	srv.Spec.IPFamilies, srv.Spec.IPFamilyPolicy = getServiceIPFamilies(wallet.Spec.Services)
+	return srv
 }
 
 // assembleChiaExporterService assembles the chia-exporter Service resource for a ChiaWallet CR
 func (r *ChiaWalletReconciler) assembleChiaExporterService(ctx context.Context, wallet k8schianetv1.ChiaWallet) corev1.Service {
+	ipFamilies, ipFamilyPolicy := getServiceIPFamilies(wallet.Spec.Services)
 	return corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            fmt.Sprintf("%s-wallet-metrics", wallet.Name),
 			Namespace:       wallet.Namespace,
-			Labels:          r.getCommonLabels(ctx, wallet, wallet.Spec.AdditionalMetadata.Labels, wallet.Spec.ChiaExporterConfig.ServiceLabels),
+			Labels:          r.getCommonLabels(ctx, wallet, wallet.Spec.AdditionalMetadata.Labels, wallet.Spec.ChiaExporterConfig.ServiceLabels, chiaExporterServiceLabels),
 			Annotations:     wallet.Spec.AdditionalMetadata.Annotations,
 			OwnerReferences: r.getOwnerReference(ctx, wallet),
 		},
 		Spec: corev1.ServiceSpec{
-			Type: corev1.ServiceType("ClusterIP"),
+			Type:           corev1.ServiceType("ClusterIP"),
+			IPFamilies:     ipFamilies,
+			IPFamilyPolicy: ipFamilyPolicy,
 			Ports: []corev1.ServicePort{
 				{
-					Port:       chiaExporterPort,
+					Port:       getChiaExporterPort(wallet.Spec.ChiaExporterConfig),
 					TargetPort: intstr.FromString("metrics"),
 					Protocol:   "TCP",
 					Name:       "metrics",
@@ -170,8 +394,79 @@ func (r *ChiaWalletReconciler) assembleChiaExporterService(ctx context.Context,
 	}
 }
 
+// assembleNetworkPolicy assembles the NetworkPolicy resource for a ChiaWallet CR. The peer port is closed unless sources are listed in peerFrom, since wallets only make outbound peer connections
+func (r *ChiaWalletReconciler) assembleNetworkPolicy(ctx context.Context, wallet k8schianetv1.ChiaWallet) networkingv1.NetworkPolicy {
+	var peerRules []networkingv1.NetworkPolicyIngressRule
+	if wallet.Spec.NetworkPolicy != nil && len(wallet.Spec.NetworkPolicy.PeerFrom) != 0 {
+		peerRules = append(peerRules, getNetworkPolicyIngressRule(walletPort, wallet.Spec.NetworkPolicy.PeerFrom))
+	}
+
+	objMeta := metav1.ObjectMeta{
+		Name:            fmt.Sprintf("%s-wallet", wallet.Name),
+		Namespace:       wallet.Namespace,
+		Labels:          r.getCommonLabels(ctx, wallet, wallet.Spec.AdditionalMetadata.Labels),
+		Annotations:     wallet.Spec.AdditionalMetadata.Annotations,
+		OwnerReferences: r.getOwnerReference(ctx, wallet),
+	}
+	return getNetworkPolicy(objMeta, r.getCommonLabels(ctx, wallet, wallet.Spec.AdditionalMetadata.Labels), wallet.Spec.NetworkPolicy, peerRules, walletRPCPort, wallet.Spec.ChiaExporterConfig)
+}
+
+// getPortExposures gives the Gateway API routes exposing the peer and RPC ports of a ChiaWallet
+func (r *ChiaWalletReconciler) getPortExposures(ctx context.Context, wallet k8schianetv1.ChiaWallet) []portExposure {
+	objMeta := metav1.ObjectMeta{
+		Name:            fmt.Sprintf("%s-wallet", wallet.Name),
+		Namespace:       wallet.Namespace,
+		Labels:          r.getCommonLabels(ctx, wallet, wallet.Spec.AdditionalMetadata.Labels),
+		Annotations:     wallet.Spec.AdditionalMetadata.Annotations,
+		OwnerReferences: r.getOwnerReference(ctx, wallet),
+	}
+	return getPortExposures(objMeta, wallet.Spec.Expose, walletPort, walletRPCPort)
+}
+
+// assembleChiaExporterServiceMonitor assembles the chia-exporter ServiceMonitor resource for a ChiaWallet CR
+func (r *ChiaWalletReconciler) assembleChiaExporterServiceMonitor(ctx context.Context, wallet k8schianetv1.ChiaWallet) *unstructured.Unstructured {
+	objMeta := metav1.ObjectMeta{
+		Name:            fmt.Sprintf("%s-wallet-metrics", wallet.Name),
+		Namespace:       wallet.Namespace,
+		Labels:          r.getCommonLabels(ctx, wallet, wallet.Spec.AdditionalMetadata.Labels),
+		Annotations:     wallet.Spec.AdditionalMetadata.Annotations,
+		OwnerReferences: r.getOwnerReference(ctx, wallet),
+	}
+	return getChiaExporterServiceMonitor(ctx, objMeta, wallet.Spec.ChiaExporterConfig, r.getCommonLabels(ctx, wallet, chiaExporterServiceLabels))
+}
+
+// assembleChiaExporterPrometheusRule assembles the chia-exporter PrometheusRule resource for a ChiaWallet CR
+func (r *ChiaWalletReconciler) assembleChiaExporterPrometheusRule(ctx context.Context, wallet k8schianetv1.ChiaWallet) *unstructured.Unstructured {
+	objMeta := metav1.ObjectMeta{
+		Name:            fmt.Sprintf("%s-wallet", wallet.Name),
+		Namespace:       wallet.Namespace,
+		Labels:          r.getCommonLabels(ctx, wallet, wallet.Spec.AdditionalMetadata.Labels),
+		Annotations:     wallet.Spec.AdditionalMetadata.Annotations,
+		OwnerReferences: r.getOwnerReference(ctx, wallet),
+	}
+	service := fmt.Sprintf("%s-wallet-metrics", wallet.Name)
+	alerts := []chiaExporterAlert{
+		getChiaExporterDownAlert(wallet.Name, wallet.Namespace, service),
+	}
+	return getChiaExporterPrometheusRule(ctx, objMeta, wallet.Spec.ChiaExporterConfig, objMeta.Name, alerts)
+}
+
+// assembleConfigOverridesConfigMap assembles the config.yaml overrides ConfigMap resource for a ChiaWallet CR
+func (r *ChiaWalletReconciler) assembleConfigOverridesConfigMap(ctx context.Context, wallet k8schianetv1.ChiaWallet, data map[string]string) corev1.ConfigMap {
+	return corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            fmt.Sprintf("%s-wallet-config-overrides", wallet.Name),
+			Namespace:       wallet.Namespace,
+			Labels:          r.getCommonLabels(ctx, wallet, wallet.Spec.AdditionalMetadata.Labels),
+			Annotations:     wallet.Spec.AdditionalMetadata.Annotations,
+			OwnerReferences: r.getOwnerReference(ctx, wallet),
+		},
+		Data: data,
+	}
+}
+
 // assembleDeployment reconciles the wallet Deployment resource for a ChiaWallet CR
-func (r *ChiaWalletReconciler) assembleDeployment(ctx context.Context, wallet k8schianetv1.ChiaWallet) appsv1.Deployment {
+func (r *ChiaWalletReconciler) assembleDeployment(ctx context.Context, wallet k8schianetv1.ChiaWallet, overrides map[string]string, overridesRef string) appsv1.Deployment {
 	var chiaSecContext *corev1.SecurityContext
 	if wallet.Spec.ChiaConfig.SecurityContext != nil {
 		chiaSecContext = wallet.Spec.ChiaConfig.SecurityContext
@@ -202,10 +497,8 @@ func (r *ChiaWalletReconciler) assembleDeployment(ctx context.Context, wallet k8
 		imagePullPolicy = *wallet.Spec.ImagePullPolicy
 	}
 
-	var chiaExporterImage = wallet.Spec.ChiaExporterConfig.Image
-	if chiaExporterImage == "" {
-		chiaExporterImage = defaultChiaExporterImage
-	}
+	podAnnotations := getConfigChecksumPodAnnotations(getConfigOverridesPodAnnotations(wallet.Spec.AdditionalMetadata.Annotations, overrides, overridesRef), overrides, chiaWalletTrustedPeersKey, trustedPeersChecksumAnnotation)
+	podAnnotations = getConfigChecksumPodAnnotations(podAnnotations, overrides, chiaIPFamiliesKey, ipFamiliesChecksumAnnotation)
 
 	var deploy appsv1.Deployment = appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -222,7 +515,7 @@ func (r *ChiaWalletReconciler) assembleDeployment(ctx context.Context, wallet k8
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels:      r.getCommonLabels(ctx, wallet, wallet.Spec.AdditionalMetadata.Labels),
-					Annotations: wallet.Spec.AdditionalMetadata.Annotations,
+					Annotations: podAnnotations,
 				},
 				Spec: corev1.PodSpec{
 					// TODO add: imagePullSecret, serviceAccountName config
@@ -277,8 +570,27 @@ func (r *ChiaWalletReconciler) assembleDeployment(ctx context.Context, wallet k8
 		},
 	}
 
-	exporterContainer := getChiaExporterContainer(ctx, chiaExporterImage, chiaSecContext, imagePullPolicy, chiaResources)
-	deploy.Spec.Template.Spec.Containers = append(deploy.Spec.Template.Spec.Containers, exporterContainer)
+	deploy.Spec.Template.Spec.Containers[0].VolumeMounts = append(deploy.Spec.Template.Spec.Containers[0].VolumeMounts, getAdditionalKeyVolumeMounts(wallet.Spec.ChiaConfig.SecretKeys)...)
+
+	verifyKeysContainer, ok := getChiaKeysVerifyInitContainer(ctx, wallet.Spec.ChiaConfig.Image, wallet.Spec.ChiaConfig.SecretKeySpec, wallet.Spec.ChiaConfig.SecretKeys, chiaSecContext, imagePullPolicy)
+	if ok {
+		deploy.Spec.Template.Spec.InitContainers = append(deploy.Spec.Template.Spec.InitContainers, verifyKeysContainer)
+	}
+
+	if wallet.Spec.ChiaConfig.Fingerprint != nil {
+		deploy.Spec.Template.Spec.InitContainers = append(deploy.Spec.Template.Spec.InitContainers, getWalletSelectKeyInitContainer(wallet, chiaSecContext, imagePullPolicy))
+	}
+
+	if chiaExporterEnabled(wallet.Spec.ChiaExporterConfig) {
+		exporterContainer := getChiaExporterContainer(ctx, wallet.Spec.ChiaExporterConfig, chiaSecContext, imagePullPolicy)
+		deploy.Spec.Template.Spec.Containers = append(deploy.Spec.Template.Spec.Containers, exporterContainer)
+	}
+
+	overridesVolume, ok := getChiaConfigOverridesVolume(ctx, wallet.Spec.ChiaConfig.ConfigOverrides, fmt.Sprintf("%s-wallet-config-overrides", wallet.Name), overrides)
+	if ok {
+		deploy.Spec.Template.Spec.Volumes = append(deploy.Spec.Template.Spec.Volumes, overridesVolume)
+		deploy.Spec.Template.Spec.InitContainers = append(deploy.Spec.Template.Spec.InitContainers, getChiaConfigOverridesInitContainer(ctx, wallet.Spec.ChiaConfig.Image, chiaSecContext, imagePullPolicy))
+	}
 
 	if wallet.Spec.PodSecurityContext != nil {
 		deploy.Spec.Template.Spec.SecurityContext = wallet.Spec.PodSecurityContext
@@ -289,6 +601,25 @@ func (r *ChiaWalletReconciler) assembleDeployment(ctx context.Context, wallet k8
 	return deploy
 }
 
+// assembleChiaRootPersistentVolumeClaim assembles the operator-created CHIA_ROOT PersistentVolumeClaim resource for a ChiaWallet CR
+func (r *ChiaWalletReconciler) assembleChiaRootPersistentVolumeClaim(ctx context.Context, wallet k8schianetv1.ChiaWallet) (corev1.PersistentVolumeClaim, error) {
+	spec, err := getPersistentVolumeClaimSpec(wallet.Spec.Storage.ChiaRoot.PersistentVolumeClaim)
+	if err != nil {
+		return corev1.PersistentVolumeClaim{}, err
+	}
+
+	return corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            fmt.Sprintf("%s-wallet-chiaroot", wallet.Name),
+			Namespace:       wallet.Namespace,
+			Labels:          r.getCommonLabels(ctx, wallet, wallet.Spec.AdditionalMetadata.Labels),
+			Annotations:     wallet.Spec.AdditionalMetadata.Annotations,
+			OwnerReferences: r.getOwnerReference(ctx, wallet),
+		},
+		Spec: spec,
+	}, nil
+}
+
 // getChiaVolumes retrieves the requisite volumes from the Chia config struct
 func (r *ChiaWalletReconciler) getChiaVolumes(ctx context.Context, wallet k8schianetv1.ChiaWallet) []corev1.Volume {
 	var v []corev1.Volume
@@ -313,8 +644,11 @@ func (r *ChiaWalletReconciler) getChiaVolumes(ctx context.Context, wallet k8schi
 		},
 	})
 
-	// CHIA_ROOT volume -- PVC is respected first if both it and hostpath are specified, falls back to hostPath if specified
-	// If both are empty, fall back to emptyDir so chia-exporter can mount CHIA_ROOT
+	// additional mnemonic key volumes
+	v = append(v, getAdditionalKeyVolumes(wallet.Spec.ChiaConfig.SecretKeys)...)
+
+	// CHIA_ROOT volume -- PVC is respected first, then hostPath, then NFS, CSI, ephemeral and iSCSI volumes
+	// If none are specified, fall back to emptyDir so chia-exporter can mount CHIA_ROOT
 	var chiaRootAdded bool = false
 	if wallet.Spec.Storage != nil && wallet.Spec.Storage.ChiaRoot != nil {
 		if wallet.Spec.Storage.ChiaRoot.PersistentVolumeClaim != nil {
@@ -322,19 +656,21 @@ func (r *ChiaWalletReconciler) getChiaVolumes(ctx context.Context, wallet k8schi
 				Name: "chiaroot",
 				VolumeSource: corev1.VolumeSource{
 					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
-						ClaimName: wallet.Spec.Storage.ChiaRoot.HostPathVolume.Path,
+						ClaimName: getChiaRootClaimName(wallet.Spec.Storage.ChiaRoot.PersistentVolumeClaim, fmt.Sprintf("%s-wallet-chiaroot", wallet.Name)),
 					},
 				},
 			})
 			chiaRootAdded = true
 		} else if wallet.Spec.Storage.ChiaRoot.HostPathVolume != nil {
 			v = append(v, corev1.Volume{
-				Name: "chiaroot",
-				VolumeSource: corev1.VolumeSource{
-					HostPath: &corev1.HostPathVolumeSource{
-						Path: wallet.Spec.Storage.ChiaRoot.HostPathVolume.Path,
-					},
-				},
+				Name:         "chiaroot",
+				VolumeSource: getHostPathVolumeSource(wallet.Spec.Storage.ChiaRoot.HostPathVolume),
+			})
+			chiaRootAdded = true
+		} else if source, ok := getChiaRootVolumeSource(wallet.Spec.Storage.ChiaRoot); ok {
+			v = append(v, corev1.Volume{
+				Name:         "chiaroot",
+				VolumeSource: source,
 			})
 			chiaRootAdded = true
 		}
@@ -400,7 +736,7 @@ func (r *ChiaWalletReconciler) getChiaEnv(ctx context.Context, wallet k8schianet
 	// keys env var
 	env = append(env, corev1.EnvVar{
 		Name:  "keys",
-		Value: fmt.Sprintf("/key/%s", wallet.Spec.ChiaConfig.SecretKeySpec.Key),
+		Value: getChiaKeysEnv(wallet.Spec.ChiaConfig.SecretKeySpec, wallet.Spec.ChiaConfig.SecretKeys),
 	})
 
 	// node peer env var
@@ -438,3 +774,246 @@ func (r *ChiaWalletReconciler) getOwnerReference(ctx context.Context, wallet k8s
 		},
 	}
 }
+
+// updateWalletStatus gathers the state of the wallet through its RPC server and sets it in the ChiaWallet's status
+func (r *ChiaWalletReconciler) updateWalletStatus(ctx context.Context, wallet *k8schianetv1.ChiaWallet) {
+	log := log.FromContext(ctx)
+	now := metav1.Now()
+	wallet.Status.LastStatusUpdateTime = &now
+
+	condition := metav1.Condition{
+		Type:               k8schianetv1.ChiaWalletConditionSynced,
+		Status:             metav1.ConditionUnknown,
+		ObservedGeneration: wallet.Generation,
+		Reason:             "StatusUnavailable",
+	}
+	defer func() {
+		meta.SetStatusCondition(&wallet.Status.Conditions, condition)
+	}()
+
+	pods, err := listRunningPods(ctx, r.Client, wallet.Namespace, r.getCommonLabels(ctx, *wallet))
+	if err != nil {
+		condition.Message = fmt.Sprintf("unable to list wallet pods: %v", err)
+		return
+	}
+	if len(pods) == 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "NotRunning"
+		condition.Message = "the wallet pod is not running"
+		return
+	}
+
+	tlsConfig, err := getChiaRPCTLSConfig(ctx, r.Client, wallet.Namespace, wallet.Spec.ChiaConfig.CASecretName)
+	if err != nil {
+		condition.Message = fmt.Sprintf("unable to create RPC client from CA Secret: %v", err)
+		return
+	}
+
+	pod := pods[0]
+	host, port := getChiaRPCHostPort(pod.Status.PodIP, walletRPCPort)
+	rpc := chiarpc.NewWalletClient(host, port, tlsConfig)
+
+	syncStatus, err := rpc.GetSyncStatus(ctx)
+	if err != nil {
+		condition.Message = fmt.Sprintf("unable to get sync status from pod %s: %v", pod.Name, err)
+		return
+	}
+	wallet.Status.Synced = syncStatus.Synced
+	wallet.Status.Syncing = syncStatus.Syncing
+	switch {
+	case syncStatus.Synced:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "Synced"
+		condition.Message = "the wallet is synced"
+	case syncStatus.Syncing:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "Syncing"
+		condition.Message = "the wallet is syncing"
+	default:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "NotSynced"
+		condition.Message = "the wallet is neither synced nor syncing"
+	}
+
+	height, err := rpc.GetHeightInfo(ctx)
+	if err != nil {
+		log.Info(fmt.Sprintf("ChiaWalletReconciler unable to get height info from pod %s/%s: %v", pod.Namespace, pod.Name, err))
+	} else {
+		wallet.Status.SyncedHeight = int64(height)
+	}
+
+	fingerprint, err := rpc.GetLoggedInFingerprint(ctx)
+	if err != nil {
+		log.Info(fmt.Sprintf("ChiaWalletReconciler unable to get logged in fingerprint from pod %s/%s: %v", pod.Namespace, pod.Name, err))
+	} else {
+		if fingerprint != nil {
+			fp := int64(*fingerprint)
+			wallet.Status.Fingerprint = &fp
+		} else {
+			wallet.Status.Fingerprint = nil
+		}
+		if keyCondition, ok := getSelectedKeyLoggedInCondition(*wallet, fingerprint); ok {
+			meta.SetStatusCondition(&wallet.Status.Conditions, keyCondition)
+		} else {
+			meta.RemoveStatusCondition(&wallet.Status.Conditions, k8schianetv1.ChiaWalletConditionSelectedKeyLoggedIn)
+		}
+	}
+
+	conns, err := rpc.GetConnections(ctx)
+	if err != nil {
+		log.Info(fmt.Sprintf("ChiaWalletReconciler unable to get connections from pod %s/%s: %v", pod.Namespace, pod.Name, err))
+	} else {
+		wallet.Status.FullNodePeer = getFullNodePeer(conns)
+	}
+
+	if !wallet.Spec.ReportBalances {
+		wallet.Status.Balances = nil
+		return
+	}
+	balances, err := getWalletBalances(ctx, rpc)
+	if err != nil {
+		log.Info(fmt.Sprintf("ChiaWalletReconciler unable to get wallet balances from pod %s/%s: %v", pod.Namespace, pod.Name, err))
+		return
+	}
+	wallet.Status.Balances = balances
+}
+
+// getFullNodePeer returns the address of the first full_node in a wallet's connections, or an empty string if it isn't connected to one
+func getFullNodePeer(conns []chiarpc.Connection) string {
+	for _, conn := range conns {
+		if conn.Type == chiarpc.NodeTypeFullNode {
+			return net.JoinHostPort(conn.PeerHost, strconv.Itoa(int(conn.PeerServerPort)))
+		}
+	}
+	return ""
+}
+
+// getWalletBalances returns the balance of every wallet belonging to the wallet's logged in key
+func getWalletBalances(ctx context.Context, rpc *chiarpc.WalletClient) ([]k8schianetv1.ChiaWalletBalanceStatus, error) {
+	wallets, err := rpc.GetWallets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var balances []k8schianetv1.ChiaWalletBalanceStatus
+	for _, w := range wallets {
+		balance, err := rpc.GetWalletBalance(ctx, w.ID)
+		if err != nil {
+			return nil, err
+		}
+		balances = append(balances, k8schianetv1.ChiaWalletBalanceStatus{
+			WalletID:  int64(w.ID),
+			Name:      w.Name,
+			Type:      int32(w.Type),
+			Confirmed: int64(balance.ConfirmedWalletBalance),
+			Spendable: int64(balance.SpendableBalance),
+		})
+	}
+	return balances, nil
+}
+
+// getTrustedPeers returns the sorted peer IDs the wallet trusts, from its explicit peer IDs and the replicas of its trusted ChiaNode
+func (r *ChiaWalletReconciler) getTrustedPeers(ctx context.Context, wallet k8schianetv1.ChiaWallet) ([]string, error) {
+	trusted := wallet.Spec.ChiaConfig.TrustedNode
+	if trusted == nil {
+		return nil, nil
+	}
+
+	peers := make(map[string]bool)
+	for _, id := range trusted.PeerIDs {
+		peers[id] = true
+	}
+
+	if trusted.ChiaNodeName != "" {
+		var node k8schianetv1.ChiaNode
+		err := r.Get(ctx, types.NamespacedName{Namespace: wallet.Namespace, Name: trusted.ChiaNodeName}, &node)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get trusted ChiaNode %s: %v", trusted.ChiaNodeName, err)
+		}
+		for _, id := range getReplicaNodeIDs(node) {
+			peers[id] = true
+		}
+	}
+
+	var ids []string
+	for id := range peers {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// getTrustedPeersOverride renders a config.yaml override document setting the wallet's trusted_peers.
+// chia only reads the keys of trusted_peers, the values are placeholders
+func getTrustedPeersOverride(peers []string) (string, error) {
+	trusted := make(map[string]string)
+	for _, id := range peers {
+		trusted[id] = "Does_not_matter"
+	}
+
+	out, err := yaml.Marshal(map[string]interface{}{
+		"wallet": map[string]interface{}{
+			"trusted_peers": trusted,
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// getWalletSelectKeyInitContainer assembles an init container that selects the key the wallet logs into when it starts.
+// Changing the fingerprint changes the pod template, so the wallet restarts with the new key
+func getWalletSelectKeyInitContainer(wallet k8schianetv1.ChiaWallet, secContext *corev1.SecurityContext, pullPolicy corev1.PullPolicy) corev1.Container {
+	return corev1.Container{
+		Name:            "select-key",
+		SecurityContext: secContext,
+		Image:           wallet.Spec.ChiaConfig.Image,
+		ImagePullPolicy: pullPolicy,
+		Command:         []string{"/bin/sh", "-c", walletSelectKeyScript},
+		Env: []corev1.EnvVar{
+			{
+				Name:  "CHIA_ROOT",
+				Value: "/chia-data",
+			},
+			{
+				Name:  "WALLET_FINGERPRINT",
+				Value: strconv.FormatInt(*wallet.Spec.ChiaConfig.Fingerprint, 10),
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      "chiaroot",
+				MountPath: "/chia-data",
+			},
+		},
+	}
+}
+
+// getSelectedKeyLoggedInCondition returns the SelectedKeyLoggedIn condition comparing the wallet's logged in fingerprint to its selected one,
+// or false if the ChiaWallet doesn't select a fingerprint
+func getSelectedKeyLoggedInCondition(wallet k8schianetv1.ChiaWallet, current *uint32) (metav1.Condition, bool) {
+	selected := wallet.Spec.ChiaConfig.Fingerprint
+	if selected == nil {
+		return metav1.Condition{}, false
+	}
+
+	condition := metav1.Condition{
+		Type:               k8schianetv1.ChiaWalletConditionSelectedKeyLoggedIn,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: wallet.Generation,
+		Reason:             "LoggedIn",
+		Message:            fmt.Sprintf("the wallet is logged in with fingerprint %d", *selected),
+	}
+	switch {
+	case current == nil:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "NotLoggedIn"
+		condition.Message = fmt.Sprintf("the wallet isn't logged in, expected fingerprint %d", *selected)
+	case int64(*current) != *selected:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "FingerprintMismatch"
+		condition.Message = fmt.Sprintf("the wallet is logged in with fingerprint %d, expected %d", *current, *selected)
+	}
+	return condition, true
+}
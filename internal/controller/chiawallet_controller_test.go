@@ -6,13 +6,21 @@ package controller
 
 import (
 	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"time"
 
 	apiv1 "github.com/chia-network/chia-operator/api/v1"
+	"github.com/chia-network/chia-operator/internal/chiarpc"
+	"github.com/chia-network/chia-operator/internal/chiarpc/chiarpctest"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 )
 
 // +kubebuilder:docs-gen:collapse=Imports
@@ -85,4 +93,170 @@ var _ = Describe("ChiaWallet controller", func() {
 		})
 	})
 
+	Context("When gathering ChiaWallet balances", func() {
+		It("Should report the balance of every wallet", func() {
+			By("By querying a fake wallet RPC server")
+			ctx := context.Background()
+			server, err := chiarpctest.NewServer()
+			Expect(err).NotTo(HaveOccurred())
+			defer server.Close()
+			server.SetResponse("get_wallets", map[string]interface{}{
+				"wallets": []map[string]interface{}{
+					{"id": 1, "name": "Chia Wallet", "type": 0},
+				},
+			})
+			server.SetResponse("get_wallet_balance", map[string]interface{}{
+				"wallet_balance": map[string]interface{}{
+					"wallet_id":                1,
+					"confirmed_wallet_balance": 1750000000000,
+					"spendable_balance":        1000000000000,
+				},
+			})
+
+			tlsConfig, err := chiarpc.NewTLSConfigFromSecretData(server.CASecretData())
+			Expect(err).NotTo(HaveOccurred())
+			host, port := server.HostPort()
+
+			balances, err := getWalletBalances(ctx, chiarpc.NewWalletClient(host, port, tlsConfig))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(balances).Should(Equal([]apiv1.ChiaWalletBalanceStatus{
+				{
+					WalletID:  1,
+					Name:      "Chia Wallet",
+					Confirmed: 1750000000000,
+					Spendable: 1000000000000,
+				},
+			}))
+		})
+	})
+
+	Context("When assembling ChiaWallet CHIA_ROOT storage", func() {
+		It("Should create a PersistentVolumeClaim when no existing claim is named", func() {
+			ctx := context.Background()
+			wallet := apiv1.ChiaWallet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      chiaWalletName,
+					Namespace: chiaWalletNamespace,
+				},
+				Spec: apiv1.ChiaWalletSpec{
+					Storage: &apiv1.StorageConfig{
+						ChiaRoot: &apiv1.ChiaRootConfig{
+							PersistentVolumeClaim: &apiv1.PersistentVolumeClaimConfig{
+								StorageClass:    "fast",
+								ResourceRequest: "50Gi",
+							},
+						},
+					},
+				},
+			}
+
+			r := &ChiaWalletReconciler{}
+			Expect(chiaRootClaimCreated(wallet.Spec.Storage)).Should(BeTrue())
+			pvc, err := r.assembleChiaRootPersistentVolumeClaim(ctx, wallet)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pvc.Name).Should(Equal("test-chiawallet-wallet-chiaroot"))
+			Expect(*pvc.Spec.StorageClassName).Should(Equal("fast"))
+			Expect(pvc.Spec.AccessModes).Should(Equal([]corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}))
+			Expect(pvc.Spec.Resources.Requests[corev1.ResourceStorage]).Should(Equal(resource.MustParse("50Gi")))
+			Expect(r.getChiaVolumes(ctx, wallet)).Should(ContainElement(corev1.Volume{
+				Name: "chiaroot",
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "test-chiawallet-wallet-chiaroot"},
+				},
+			}))
+
+			By("By naming an existing claim")
+			wallet.Spec.Storage.ChiaRoot.PersistentVolumeClaim.ClaimName = "wallet-db"
+			Expect(chiaRootClaimCreated(wallet.Spec.Storage)).Should(BeFalse())
+			Expect(r.getChiaVolumes(ctx, wallet)).Should(ContainElement(corev1.Volume{
+				Name: "chiaroot",
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "wallet-db"},
+				},
+			}))
+		})
+	})
+
+	Context("When configuring ChiaWallet trusted peers", func() {
+		It("Should render trusted_peers and restart pods when they change", func() {
+			override, err := getTrustedPeersOverride([]string{"aaaa", "bbbb"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(override).Should(Equal("wallet:\n  trusted_peers:\n    aaaa: Does_not_matter\n    bbbb: Does_not_matter\n"))
+
+			annotations := map[string]string{"key": "value"}
+			Expect(getConfigChecksumPodAnnotations(annotations, map[string]string{}, chiaWalletTrustedPeersKey, trustedPeersChecksumAnnotation)).Should(Equal(annotations))
+
+			podAnnotations := getConfigChecksumPodAnnotations(annotations, map[string]string{chiaWalletTrustedPeersKey: override}, chiaWalletTrustedPeersKey, trustedPeersChecksumAnnotation)
+			Expect(podAnnotations).Should(HaveKeyWithValue("key", "value"))
+			Expect(podAnnotations).Should(HaveKey(trustedPeersChecksumAnnotation))
+			Expect(annotations).ShouldNot(HaveKey(trustedPeersChecksumAnnotation))
+		})
+	})
+
+	Context("When selecting ChiaWallet keys", func() {
+		It("Should add every key, select the fingerprint at startup and report whether the wallet is logged in with it", func() {
+			ctx := context.Background()
+			selected := int64(2222222222)
+			wallet := apiv1.ChiaWallet{
+				Spec: apiv1.ChiaWalletSpec{
+					ChiaConfig: apiv1.ChiaWalletConfigSpec{
+						SecretKeySpec: apiv1.ChiaKeysSpec{Name: secretKeyName, Key: secretKeyKey},
+						SecretKeys: []apiv1.ChiaKeysSpec{
+							{Name: "otherkeys", Key: "other.txt", Fingerprint: &selected},
+						},
+						Fingerprint: &selected,
+					},
+				},
+			}
+			Expect(getChiaKeysEnv(wallet.Spec.ChiaConfig.SecretKeySpec, wallet.Spec.ChiaConfig.SecretKeys)).Should(Equal("/key/key.txt:/key-1/other.txt"))
+
+			verify, ok := getChiaKeysVerifyInitContainer(ctx, "chia", wallet.Spec.ChiaConfig.SecretKeySpec, wallet.Spec.ChiaConfig.SecretKeys, nil, corev1.PullAlways)
+			Expect(ok).Should(BeTrue())
+			Expect(verify.Env).Should(ContainElement(corev1.EnvVar{Name: "VERIFY_KEYS", Value: "/key-1/other.txt=2222222222"}))
+
+			selectKey := getWalletSelectKeyInitContainer(wallet, nil, corev1.PullAlways)
+			Expect(selectKey.Env).Should(ContainElement(corev1.EnvVar{Name: "WALLET_FINGERPRINT", Value: "2222222222"}))
+			Expect(selectKey.Command).Should(Equal([]string{"/bin/sh", "-c", walletSelectKeyScript}))
+
+			dir := GinkgoT().TempDir()
+			cmd := exec.Command("/bin/sh", "-c", walletSelectKeyScript)
+			cmd.Env = append(os.Environ(), "CHIA_ROOT="+dir, "WALLET_FINGERPRINT=2222222222")
+			out, err := cmd.CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), string(out))
+			Expect(os.ReadFile(filepath.Join(dir, "wallet", "db", "last_used_fingerprint"))).Should(Equal([]byte("2222222222\n")))
+
+			current := uint32(1111111111)
+			condition, ok := getSelectedKeyLoggedInCondition(wallet, &current)
+			Expect(ok).Should(BeTrue())
+			Expect(condition.Status).Should(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).Should(Equal("FingerprintMismatch"))
+
+			current = uint32(selected)
+			condition, _ = getSelectedKeyLoggedInCondition(wallet, &current)
+			Expect(condition.Status).Should(Equal(metav1.ConditionTrue))
+
+			wallet.Spec.ChiaConfig.Fingerprint = nil
+			_, ok = getSelectedKeyLoggedInCondition(wallet, &current)
+			Expect(ok).Should(BeFalse())
+		})
+	})
+
+	Context("When watching trusted ChiaNodes", func() {
+		It("Should only reconcile wallets when the node's replica IDs change", func() {
+			oldNode := &apiv1.ChiaNode{
+				Status: apiv1.ChiaNodeStatus{
+					Replicas: []apiv1.ChiaNodeReplicaStatus{{PodName: "node-0", NodeID: "aaaa"}},
+				},
+			}
+			newNode := oldNode.DeepCopy()
+			now := metav1.Now()
+			newNode.Status.LastStatusUpdateTime = &now
+			newNode.Status.SyncedReplicas = 1
+			Expect(replicaNodeIDsChangedPredicate.Update(event.UpdateEvent{ObjectOld: oldNode, ObjectNew: newNode})).Should(BeFalse())
+
+			newNode.Status.Replicas = append(newNode.Status.Replicas, apiv1.ChiaNodeReplicaStatus{PodName: "node-1", NodeID: "bbbb"})
+			Expect(replicaNodeIDsChangedPredicate.Update(event.UpdateEvent{ObjectOld: oldNode, ObjectNew: newNode})).Should(BeTrue())
+			Expect(getReplicaNodeIDs(*newNode)).Should(Equal([]string{"aaaa", "bbbb"}))
+		})
+	})
 })
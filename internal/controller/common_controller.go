@@ -6,14 +6,37 @@ package controller
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/cisco-open/operator-tools/pkg/reconciler"
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/yaml"
+
+	k8schianetv1 "github.com/chia-network/chia-operator/api/v1"
+	"github.com/chia-network/chia-operator/internal/chiarpc"
 )
 
 const (
@@ -24,24 +47,131 @@ const (
 	chiaExporterPort = 9914
 )
 
+const (
+	// chiaConfigOverridesPath is the directory config.yaml override documents are mounted to in the config-overrides init container
+	chiaConfigOverridesPath = "/chia-config-overrides"
+
+	// chiaConfigOverridesRefKey is the file name a user's referenced ConfigMap key is projected to
+	chiaConfigOverridesRefKey = "10-configmap.yaml"
+
+	// chiaConfigOverridesInlineKey is the key inline overrides are rendered to in the operator managed ConfigMap
+	chiaConfigOverridesInlineKey = "20-inline.yaml"
+
+	// configOverridesChecksumAnnotation is set on pods to restart them when their inline config.yaml overrides change
+	configOverridesChecksumAnnotation = "k8s.chia.net/config-overrides-checksum"
+
+	// configOverridesRefChecksumAnnotation is set on pods to restart them when the document in their referenced overrides ConfigMap changes
+	configOverridesRefChecksumAnnotation = "k8s.chia.net/config-overrides-configmap-checksum"
+
+	// chiaIPFamiliesKey is the config overrides ConfigMap key the IP family settings matching a component's Services are rendered to, merged after any user overrides
+	chiaIPFamiliesKey = "30-ip-families.yaml"
+
+	// ipFamiliesChecksumAnnotation is set on pods to restart them when their IP family settings change
+	ipFamiliesChecksumAnnotation = "k8s.chia.net/ip-families-checksum"
+
+	// chiaConfigOverridesScript initializes config.yaml if it doesn't exist yet and deep-merges each override document into it in lexical order
+	chiaConfigOverridesScript = `set -e
+if [ ! -f "${CHIA_ROOT}/config/config.yaml" ]; then
+  chia init
+fi
+for f in ` + chiaConfigOverridesPath + `/*.yaml; do
+  [ -f "${f}" ] || continue
+  echo "Merging ${f} into ${CHIA_ROOT}/config/config.yaml"
+  yq -i ". *= load(\"${f}\")" "${CHIA_ROOT}/config/config.yaml"
+done
+`
+)
+
+const (
+	// chiaKeysVerifyScript adds each mnemonic key to a throwaway keychain and fails if its fingerprint isn't the expected one
+	chiaKeysVerifyScript = `set -e
+for entry in ${VERIFY_KEYS}; do
+  file="${entry%=*}"
+  expected="${entry##*=}"
+  export CHIA_KEYS_ROOT="$(mktemp -d)"
+  fingerprint=$(chia keys add -f "${file}" | sed -n 's/.*fingerprint \([0-9]*\).*/\1/p')
+  rm -rf "${CHIA_KEYS_ROOT}"
+  if [ "${fingerprint}" != "${expected}" ]; then
+    echo "Failed: ${file} has fingerprint ${fingerprint:-unknown}, expected ${expected}" | tee /dev/termination-log
+    exit 1
+  fi
+  echo "${file} has the expected fingerprint ${expected}"
+done
+`
+)
+
+const (
+	// statusRefreshInterval is how often reconcilers requeue to refresh status gathered from chia RPC servers
+	statusRefreshInterval = time.Minute
+)
+
 const (
 	// defaultChiaExporterImage is the default image name and tag of the chia-exporter image
 	defaultChiaExporterImage = "ghcr.io/chia-network/chia-exporter:latest"
 )
 
+var (
+	// serviceMonitorGVK is the GroupVersionKind of Prometheus Operator ServiceMonitors
+	serviceMonitorGVK = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "ServiceMonitor"}
+
+	// prometheusRuleGVK is the GroupVersionKind of Prometheus Operator PrometheusRules
+	prometheusRuleGVK = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "PrometheusRule"}
+
+	// tcpRouteGVK is the GroupVersionKind of Gateway API TCPRoutes
+	tcpRouteGVK = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1alpha2", Kind: string(k8schianetv1.GatewayTCPRoute)}
+
+	// tlsRouteGVK is the GroupVersionKind of Gateway API TLSRoutes
+	tlsRouteGVK = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1alpha2", Kind: string(k8schianetv1.GatewayTLSRoute)}
+)
+
+// chiaExporterServiceLabels are added to chia-exporter metrics Services so they can be selected apart from a component's other Services
+var chiaExporterServiceLabels = map[string]string{
+	"app.kubernetes.io/component": "chia-exporter",
+}
+
+// chiaRPCTLSConfigs caches chia RPC TLS configs by CA Secret, so a client certificate isn't generated on every reconcile
+// and RPC clients reuse the connections pooled for the config
+var chiaRPCTLSConfigs sync.Map
+
+// cachedTLSConfig is a chia RPC TLS config and the CA Secret resourceVersion it was generated from
+type cachedTLSConfig struct {
+	resourceVersion string
+	config          *tls.Config
+}
+
+// getChiaRPCHostPort returns the host and port a chia RPC server at the given address can be reached on.
+// It is a variable so tests can point reconcilers at a fake RPC server.
+var getChiaRPCHostPort = func(host string, port int) (string, int) {
+	return host, port
+}
+
 // controllerOwner tells k8s objects that the CR that created it is its controller owner
 var controllerOwner = true
 
-// reconcileService uses the ResourceReconciler to determine if the service resource needs to be created or updated
-func reconcileService(ctx context.Context, rec reconciler.ResourceReconciler, service corev1.Service) (*reconcile.Result, error) {
+// reconcileService uses the ResourceReconciler to determine if the service resource needs to be created, updated, or deleted
+func reconcileService(ctx context.Context, rec reconciler.ResourceReconciler, service corev1.Service, present bool) (*reconcile.Result, error) {
+	if !present {
+		return rec.ReconcileResource(&service, reconciler.StateAbsent)
+	}
 	return rec.ReconcileResource(&service, reconciler.StatePresent)
 }
 
-// reconcileDeployment uses the ResourceReconciler to determine if the deployment resource needs to be created or updated
-func reconcileDeployment(ctx context.Context, rec reconciler.ResourceReconciler, deploy appsv1.Deployment) (*reconcile.Result, error) {
+// reconcileDeployment uses the ResourceReconciler to determine if the deployment resource needs to be created, updated, or deleted
+func reconcileDeployment(ctx context.Context, rec reconciler.ResourceReconciler, deploy appsv1.Deployment, present bool) (*reconcile.Result, error) {
+	if !present {
+		return rec.ReconcileResource(&deploy, reconciler.StateAbsent)
+	}
 	return rec.ReconcileResource(&deploy, reconciler.StatePresent)
 }
 
+// reconcileDaemonSet uses the ResourceReconciler to determine if the daemonset resource needs to be created, updated, or deleted
+func reconcileDaemonSet(ctx context.Context, rec reconciler.ResourceReconciler, ds appsv1.DaemonSet, present bool) (*reconcile.Result, error) {
+	if !present {
+		return rec.ReconcileResource(&ds, reconciler.StateAbsent)
+	}
+	return rec.ReconcileResource(&ds, reconciler.StatePresent)
+}
+
 // reconcileStatefulset uses the ResourceReconciler to determine if the statefulset resource needs to be created or updated
 func reconcileStatefulset(ctx context.Context, rec reconciler.ResourceReconciler, stateful appsv1.StatefulSet) (*reconcile.Result, error) {
 	return rec.ReconcileResource(&stateful, reconciler.StatePresent)
@@ -62,6 +192,60 @@ func reconcileRoleBinding(ctx context.Context, rec reconciler.ResourceReconciler
 	return rec.ReconcileResource(&rb, reconciler.StatePresent)
 }
 
+// reconcileConfigMap uses the ResourceReconciler to determine if the configmap resource needs to be created, updated, or deleted
+func reconcileConfigMap(ctx context.Context, rec reconciler.ResourceReconciler, cm corev1.ConfigMap, present bool) (*reconcile.Result, error) {
+	if !present {
+		return rec.ReconcileResource(&cm, reconciler.StateAbsent)
+	}
+	return rec.ReconcileResource(&cm, reconciler.StatePresent)
+}
+
+// reconcileSecret uses the ResourceReconciler to determine if the secret resource needs to be created or updated
+func reconcileSecret(ctx context.Context, rec reconciler.ResourceReconciler, secret corev1.Secret) (*reconcile.Result, error) {
+	return rec.ReconcileResource(&secret, reconciler.StatePresent)
+}
+
+// reconcileNetworkPolicy uses the ResourceReconciler to determine if the networkpolicy resource needs to be created, updated, or deleted
+func reconcileNetworkPolicy(ctx context.Context, rec reconciler.ResourceReconciler, np networkingv1.NetworkPolicy, present bool) (*reconcile.Result, error) {
+	if !present {
+		return rec.ReconcileResource(&np, reconciler.StateAbsent)
+	}
+	return rec.ReconcileResource(&np, reconciler.StatePresent)
+}
+
+// reconcilePersistentVolumeClaim uses the ResourceReconciler to create the persistentvolumeclaim resource if it doesn't exist.
+// Existing claims are left untouched since most of their spec is immutable, and claims are only removed along with their owner to avoid losing data
+func reconcilePersistentVolumeClaim(ctx context.Context, rec reconciler.ResourceReconciler, pvc corev1.PersistentVolumeClaim) (*reconcile.Result, error) {
+	return rec.ReconcileResource(&pvc, reconciler.StateCreated)
+}
+
+// reconcilePersistentVolume uses the ResourceReconciler to create the persistentvolume resource if it doesn't exist.
+// Like claims, existing volumes are left untouched since most of their spec is immutable
+func reconcilePersistentVolume(ctx context.Context, rec reconciler.ResourceReconciler, pv corev1.PersistentVolume) (*reconcile.Result, error) {
+	return rec.ReconcileResource(&pv, reconciler.StateCreated)
+}
+
+// reconcileOptionalResource uses the ResourceReconciler to determine if an unstructured resource needs to be created, updated, or deleted.
+// Resources whose kind is not served by the cluster, such as when an optional CRD isn't installed, are skipped without error.
+func reconcileOptionalResource(ctx context.Context, c client.Client, rec reconciler.ResourceReconciler, obj *unstructured.Unstructured, present bool) (*reconcile.Result, error) {
+	gvk := obj.GroupVersionKind()
+	_, err := c.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	if meta.IsNoMatchError(err) {
+		if present {
+			log.FromContext(ctx).Info(fmt.Sprintf("Skipping %s %s/%s, the %s API is not installed in this cluster", gvk.Kind, obj.GetNamespace(), obj.GetName(), gvk.GroupVersion().String()))
+		}
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !present {
+		return rec.ReconcileResource(obj, reconciler.StateAbsent)
+	}
+	return rec.ReconcileResource(obj, reconciler.StatePresent)
+}
+
 // reconcileJob uses the ResourceReconciler to determine if the job resource needs to be created or updated
 func reconcileJob(ctx context.Context, rec reconciler.ResourceReconciler, job batchv1.Job) (*reconcile.Result, error) {
 	return rec.ReconcileResource(&job, reconciler.StatePresent)
@@ -74,58 +258,907 @@ func getCommonLabels(ctx context.Context, labels map[string]string) map[string]s
 	return labels
 }
 
-// getChiaExporterContainer assembles a chia-exporter container spec
-func getChiaExporterContainer(ctx context.Context, image string, secContext *corev1.SecurityContext, pullPolicy corev1.PullPolicy, resReq corev1.ResourceRequirements) corev1.Container {
-	return corev1.Container{
-		Name:            "chia-exporter",
-		SecurityContext: secContext,
-		Image:           image,
-		ImagePullPolicy: pullPolicy,
-		Env: []corev1.EnvVar{
-			{
-				Name:  "CHIA_ROOT",
-				Value: "/chia-data",
-			},
-		},
-		Ports: []corev1.ContainerPort{
-			{
-				Name:          "metrics",
-				ContainerPort: chiaExporterPort,
-				Protocol:      "TCP",
-			},
-		},
-		LivenessProbe: &corev1.Probe{
+// chiaExporterEnabled returns whether the chia-exporter sidecar and metrics Service should be deployed, defaulting to true
+func chiaExporterEnabled(exporter k8schianetv1.ChiaExporterConfigSpec) bool {
+	return exporter.Enabled == nil || *exporter.Enabled
+}
+
+// getChiaExporterPort returns the port a chia-exporter serves metrics on, defaulting to chiaExporterPort
+func getChiaExporterPort(exporter k8schianetv1.ChiaExporterConfigSpec) int32 {
+	if exporter.MetricsPort != 0 {
+		return exporter.MetricsPort
+	}
+	return chiaExporterPort
+}
+
+// getChiaExporterContainer assembles a chia-exporter container spec. The chia container's security context and the pod's pull policy are used unless overridden.
+func getChiaExporterContainer(ctx context.Context, exporter k8schianetv1.ChiaExporterConfigSpec, chiaSecContext *corev1.SecurityContext, pullPolicy corev1.PullPolicy) corev1.Container {
+	var image = exporter.Image
+	if image == "" {
+		image = defaultChiaExporterImage
+	}
+
+	var secContext = chiaSecContext
+	if exporter.SecurityContext != nil {
+		secContext = exporter.SecurityContext
+	}
+
+	if exporter.ImagePullPolicy != nil {
+		pullPolicy = *exporter.ImagePullPolicy
+	}
+
+	var resources corev1.ResourceRequirements
+	if exporter.Resources != nil {
+		resources = *exporter.Resources
+	}
+
+	port := getChiaExporterPort(exporter)
+	var livenessProbe = exporter.LivenessProbe
+	if livenessProbe == nil {
+		livenessProbe = &corev1.Probe{
 			ProbeHandler: corev1.ProbeHandler{
 				HTTPGet: &corev1.HTTPGetAction{
 					Path: "/healthz",
-					Port: intstr.FromInt(chiaExporterPort),
+					Port: intstr.FromInt(int(port)),
 				},
 			},
-		},
-		ReadinessProbe: &corev1.Probe{
+		}
+	}
+
+	var readinessProbe = exporter.ReadinessProbe
+	if readinessProbe == nil {
+		readinessProbe = &corev1.Probe{
 			ProbeHandler: corev1.ProbeHandler{
 				HTTPGet: &corev1.HTTPGetAction{
 					Path: "/healthz",
-					Port: intstr.FromInt(chiaExporterPort),
+					Port: intstr.FromInt(int(port)),
 				},
 			},
-		},
-		StartupProbe: &corev1.Probe{
+		}
+	}
+
+	var startupProbe = exporter.StartupProbe
+	if startupProbe == nil {
+		startupProbe = &corev1.Probe{
 			ProbeHandler: corev1.ProbeHandler{
 				HTTPGet: &corev1.HTTPGetAction{
 					Path: "/healthz",
-					Port: intstr.FromInt(chiaExporterPort),
+					Port: intstr.FromInt(int(port)),
 				},
 			},
 			FailureThreshold: 30,
 			PeriodSeconds:    10,
+		}
+	}
+
+	env := []corev1.EnvVar{
+		{
+			Name:  "CHIA_ROOT",
+			Value: "/chia-data",
+		},
+		{
+			Name:  "CHIA_EXPORTER_METRICS_PORT",
+			Value: strconv.Itoa(int(port)),
+		},
+	}
+	env = append(env, exporter.Env...)
+
+	return corev1.Container{
+		Name:            "chia-exporter",
+		SecurityContext: secContext,
+		Image:           image,
+		ImagePullPolicy: pullPolicy,
+		Args:            exporter.Args,
+		Env:             env,
+		Ports: []corev1.ContainerPort{
+			{
+				Name:          "metrics",
+				ContainerPort: port,
+				Protocol:      "TCP",
+			},
+		},
+		LivenessProbe:  livenessProbe,
+		ReadinessProbe: readinessProbe,
+		StartupProbe:   startupProbe,
+		Resources:      resources,
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      "chiaroot",
+				MountPath: "/chia-data",
+			},
+		},
+	}
+}
+
+// getChiaConfigOverridesData assembles the data for an operator managed config overrides ConfigMap. An empty map means no ConfigMap is needed.
+func getChiaConfigOverridesData(ctx context.Context, overrides *k8schianetv1.ChiaConfigOverridesSpec) (map[string]string, error) {
+	var data = make(map[string]string)
+	if overrides == nil || overrides.Inline == "" {
+		return data, nil
+	}
+
+	// Catch invalid documents here rather than in a crashlooping init container
+	var doc map[string]interface{}
+	err := yaml.Unmarshal([]byte(overrides.Inline), &doc)
+	if err != nil {
+		return data, fmt.Errorf("configOverrides.inline is not a valid YAML map: %v", err)
+	}
+	data[chiaConfigOverridesInlineKey] = overrides.Inline
+
+	return data, nil
+}
+
+// getChiaConfigOverridesRefData returns the document in the ConfigMap key a component's config overrides reference, or an empty string if there is none.
+// A missing ConfigMap or key is only an error if the reference isn't optional, matching how the kubelet treats the projected volume
+func getChiaConfigOverridesRefData(ctx context.Context, c client.Client, namespace string, overrides *k8schianetv1.ChiaConfigOverridesSpec) (string, error) {
+	if overrides == nil || overrides.ConfigMapRef == nil {
+		return "", nil
+	}
+	ref := overrides.ConfigMapRef
+	optional := ref.Optional != nil && *ref.Optional
+
+	var cm corev1.ConfigMap
+	err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, &cm)
+	if err != nil {
+		if errors.IsNotFound(err) && optional {
+			return "", nil
+		}
+		return "", fmt.Errorf("unable to get configOverrides.configMapRef ConfigMap %s: %v", ref.Name, err)
+	}
+	data, ok := cm.Data[ref.Key]
+	if !ok && !optional {
+		return "", fmt.Errorf("configOverrides.configMapRef ConfigMap %s has no key %s", ref.Name, ref.Key)
+	}
+	return data, nil
+}
+
+// getConfigOverridesPodAnnotations adds checksums of a component's inline and referenced config.yaml overrides to a copy of the pod annotations,
+// so pods restart to merge edits to either
+func getConfigOverridesPodAnnotations(annotations map[string]string, overrides map[string]string, overridesRef string) map[string]string {
+	podAnnotations := getConfigChecksumPodAnnotations(annotations, overrides, chiaConfigOverridesInlineKey, configOverridesChecksumAnnotation)
+	if overridesRef == "" {
+		return podAnnotations
+	}
+	return getConfigChecksumPodAnnotations(podAnnotations, map[string]string{chiaConfigOverridesRefKey: overridesRef}, chiaConfigOverridesRefKey, configOverridesRefChecksumAnnotation)
+}
+
+// getChiaConfigOverridesVolume assembles a projected volume containing every config.yaml override document for a component.
+// Returns false if there are no overrides to mount.
+func getChiaConfigOverridesVolume(ctx context.Context, overrides *k8schianetv1.ChiaConfigOverridesSpec, configMapName string, data map[string]string) (corev1.Volume, bool) {
+	var sources []corev1.VolumeProjection
+	if overrides != nil && overrides.ConfigMapRef != nil {
+		sources = append(sources, corev1.VolumeProjection{
+			ConfigMap: &corev1.ConfigMapProjection{
+				LocalObjectReference: overrides.ConfigMapRef.LocalObjectReference,
+				Items: []corev1.KeyToPath{
+					{
+						Key:  overrides.ConfigMapRef.Key,
+						Path: chiaConfigOverridesRefKey,
+					},
+				},
+				Optional: overrides.ConfigMapRef.Optional,
+			},
+		})
+	}
+	if len(data) != 0 {
+		sources = append(sources, corev1.VolumeProjection{
+			ConfigMap: &corev1.ConfigMapProjection{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: configMapName,
+				},
+			},
+		})
+	}
+	if len(sources) == 0 {
+		return corev1.Volume{}, false
+	}
+
+	return corev1.Volume{
+		Name: "config-overrides",
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources: sources,
+			},
+		},
+	}, true
+}
+
+// getChiaConfigOverridesInitContainer assembles an init container that merges config.yaml overrides into CHIA_ROOT before the chia container starts
+func getChiaConfigOverridesInitContainer(ctx context.Context, image string, secContext *corev1.SecurityContext, pullPolicy corev1.PullPolicy) corev1.Container {
+	return corev1.Container{
+		Name:            "config-overrides",
+		SecurityContext: secContext,
+		Image:           image,
+		ImagePullPolicy: pullPolicy,
+		Command:         []string{"/bin/sh", "-c", chiaConfigOverridesScript},
+		Env: []corev1.EnvVar{
+			{
+				Name:  "CHIA_ROOT",
+				Value: "/chia-data",
+			},
 		},
-		Resources: resReq,
 		VolumeMounts: []corev1.VolumeMount{
 			{
 				Name:      "chiaroot",
 				MountPath: "/chia-data",
 			},
+			{
+				Name:      "config-overrides",
+				MountPath: chiaConfigOverridesPath,
+				ReadOnly:  true,
+			},
 		},
 	}
 }
+
+// serviceMonitorEnabled returns whether a ServiceMonitor should be deployed for a component's chia-exporter
+func serviceMonitorEnabled(exporter k8schianetv1.ChiaExporterConfigSpec) bool {
+	return chiaExporterEnabled(exporter) && exporter.ServiceMonitor != nil && exporter.ServiceMonitor.Enabled
+}
+
+// prometheusRuleEnabled returns whether a PrometheusRule should be deployed for a component's chia-exporter
+func prometheusRuleEnabled(exporter k8schianetv1.ChiaExporterConfigSpec) bool {
+	return chiaExporterEnabled(exporter) && exporter.PrometheusRule != nil && exporter.PrometheusRule.Enabled
+}
+
+// newUnstructured creates an unstructured object of the given kind with the given metadata
+func newUnstructured(gvk schema.GroupVersionKind, objMeta metav1.ObjectMeta) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetName(objMeta.Name)
+	obj.SetNamespace(objMeta.Namespace)
+	obj.SetLabels(objMeta.Labels)
+	obj.SetAnnotations(objMeta.Annotations)
+	obj.SetOwnerReferences(objMeta.OwnerReferences)
+	return obj
+}
+
+// toUnstructuredMap converts a string map to a map that can be set in an unstructured object's content
+func toUnstructuredMap(m map[string]string) map[string]interface{} {
+	var out = make(map[string]interface{}, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// getChiaExporterServiceMonitor assembles a ServiceMonitor that scrapes the metrics port of the chia-exporter Service matching selector
+func getChiaExporterServiceMonitor(ctx context.Context, objMeta metav1.ObjectMeta, exporter k8schianetv1.ChiaExporterConfigSpec, selector map[string]string) *unstructured.Unstructured {
+	var endpoint = map[string]interface{}{
+		"port": "metrics",
+		"path": "/metrics",
+	}
+	if exporter.ServiceMonitor != nil {
+		for k, v := range exporter.ServiceMonitor.Labels {
+			objMeta.Labels[k] = v
+		}
+		if exporter.ServiceMonitor.Interval != "" {
+			endpoint["interval"] = exporter.ServiceMonitor.Interval
+		}
+		if exporter.ServiceMonitor.ScrapeTimeout != "" {
+			endpoint["scrapeTimeout"] = exporter.ServiceMonitor.ScrapeTimeout
+		}
+	}
+
+	monitor := newUnstructured(serviceMonitorGVK, objMeta)
+	monitor.Object["spec"] = map[string]interface{}{
+		"endpoints": []interface{}{endpoint},
+		"selector": map[string]interface{}{
+			"matchLabels": toUnstructuredMap(selector),
+		},
+	}
+	return monitor
+}
+
+// chiaExporterAlert defines a default alert included in a component's PrometheusRule
+type chiaExporterAlert struct {
+	Name        string
+	Expr        string
+	For         string
+	Summary     string
+	Description string
+}
+
+// getChiaExporterAlertSelector returns a PromQL label selector matching the series scraped from a chia-exporter metrics Service
+func getChiaExporterAlertSelector(namespace, service string) string {
+	return fmt.Sprintf(`{namespace="%s",service="%s"}`, namespace, service)
+}
+
+// getChiaExporterDownAlert returns an alert that fires when a chia-exporter metrics Service can no longer be scraped
+func getChiaExporterDownAlert(name, namespace, service string) chiaExporterAlert {
+	return chiaExporterAlert{
+		Name:        "ChiaExporterDown",
+		Expr:        fmt.Sprintf("up%s == 0", getChiaExporterAlertSelector(namespace, service)),
+		For:         "10m",
+		Summary:     fmt.Sprintf("chia-exporter for %s/%s is down", namespace, name),
+		Description: fmt.Sprintf("Prometheus has been unable to scrape %s/%s for 10 minutes.", namespace, service),
+	}
+}
+
+// getChiaExporterPrometheusRule assembles a PrometheusRule with a single group containing the given alerts
+func getChiaExporterPrometheusRule(ctx context.Context, objMeta metav1.ObjectMeta, exporter k8schianetv1.ChiaExporterConfigSpec, groupName string, alerts []chiaExporterAlert) *unstructured.Unstructured {
+	var alertLabels map[string]string
+	if exporter.PrometheusRule != nil {
+		for k, v := range exporter.PrometheusRule.Labels {
+			objMeta.Labels[k] = v
+		}
+		alertLabels = exporter.PrometheusRule.AlertLabels
+	}
+
+	var rules []interface{}
+	for _, alert := range alerts {
+		var labels = map[string]string{
+			"namespace": objMeta.Namespace,
+		}
+		for k, v := range alertLabels {
+			labels[k] = v
+		}
+		rules = append(rules, map[string]interface{}{
+			"alert":  alert.Name,
+			"expr":   alert.Expr,
+			"for":    alert.For,
+			"labels": toUnstructuredMap(labels),
+			"annotations": map[string]interface{}{
+				"summary":     alert.Summary,
+				"description": alert.Description,
+			},
+		})
+	}
+
+	rule := newUnstructured(prometheusRuleGVK, objMeta)
+	rule.Object["spec"] = map[string]interface{}{
+		"groups": []interface{}{
+			map[string]interface{}{
+				"name":  groupName,
+				"rules": rules,
+			},
+		},
+	}
+	return rule
+}
+
+// conditionChanged returns true if a condition's status, reason or message differs from the condition of the same type in conditions, or if there is none
+func conditionChanged(conditions []metav1.Condition, condition metav1.Condition) bool {
+	existing := meta.FindStatusCondition(conditions, condition.Type)
+	return existing == nil || existing.Status != condition.Status || existing.Reason != condition.Reason || existing.Message != condition.Message
+}
+
+// getChiaRPCTLSConfig returns a TLS config for talking to chia RPC servers that use the CA in the given Secret
+func getChiaRPCTLSConfig(ctx context.Context, c client.Client, namespace, secretName string) (*tls.Config, error) {
+	var secret corev1.Secret
+	err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: secretName}, &secret)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("%s/%s", namespace, secretName)
+	cached, ok := chiaRPCTLSConfigs.Load(key)
+	if ok && cached.(cachedTLSConfig).resourceVersion == secret.ResourceVersion {
+		return cached.(cachedTLSConfig).config, nil
+	}
+
+	config, err := chiarpc.NewTLSConfigFromSecretData(secret.Data)
+	if err != nil {
+		return nil, err
+	}
+	chiaRPCTLSConfigs.Store(key, cachedTLSConfig{
+		resourceVersion: secret.ResourceVersion,
+		config:          config,
+	})
+	if ok {
+		// Close the connections made with the replaced config, they're pooled per TLS config and would otherwise stay open
+		chiarpc.CloseIdleConnections(cached.(cachedTLSConfig).config)
+	}
+	return config, nil
+}
+
+// listRunningPods lists the running pods with an IP that match the given labels, sorted by name
+func listRunningPods(ctx context.Context, c client.Client, namespace string, labels map[string]string) ([]corev1.Pod, error) {
+	var podList corev1.PodList
+	err := c.List(ctx, &podList, client.InNamespace(namespace), client.MatchingLabels(labels))
+	if err != nil {
+		return nil, err
+	}
+
+	var pods []corev1.Pod
+	for _, pod := range podList.Items {
+		if pod.Status.Phase == corev1.PodRunning && pod.Status.PodIP != "" && pod.DeletionTimestamp == nil {
+			pods = append(pods, pod)
+		}
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].Name < pods[j].Name
+	})
+	return pods, nil
+}
+
+// getChiaRootVolumeSource returns the volume source for CHIA_ROOT when it is configured with an NFS, CSI, ephemeral or iSCSI volume
+func getChiaRootVolumeSource(config *k8schianetv1.ChiaRootConfig) (corev1.VolumeSource, bool) {
+	if config == nil {
+		return corev1.VolumeSource{}, false
+	}
+	if config.NFSVolume != nil {
+		return getNFSVolumeSource(config.NFSVolume, false), true
+	}
+	if config.CSIVolume != nil {
+		return getCSIVolumeSource(config.CSIVolume, false), true
+	}
+	if config.EphemeralVolume != nil {
+		return getEphemeralVolumeSource(config.EphemeralVolume), true
+	}
+	if config.ISCSIVolume != nil {
+		return getISCSIVolumeSource(config.ISCSIVolume, false), true
+	}
+	return corev1.VolumeSource{}, false
+}
+
+// getHostPathVolumeSource assembles a hostPath volume source from its config
+func getHostPathVolumeSource(config *k8schianetv1.HostPathVolumeConfig) corev1.VolumeSource {
+	return corev1.VolumeSource{
+		HostPath: &corev1.HostPathVolumeSource{
+			Path: config.Path,
+			Type: config.Type,
+		},
+	}
+}
+
+// getNFSVolumeSource assembles an NFS volume source from its config
+func getNFSVolumeSource(config *k8schianetv1.NFSVolumeConfig, readOnly bool) corev1.VolumeSource {
+	return corev1.VolumeSource{
+		NFS: &corev1.NFSVolumeSource{
+			Server:   config.Server,
+			Path:     config.Path,
+			ReadOnly: readOnly,
+		},
+	}
+}
+
+// getCSIVolumeSource assembles an inline CSI volume source from its config
+func getCSIVolumeSource(config *k8schianetv1.CSIVolumeConfig, readOnly bool) corev1.VolumeSource {
+	return corev1.VolumeSource{
+		CSI: &corev1.CSIVolumeSource{
+			Driver:               config.Driver,
+			ReadOnly:             &readOnly,
+			FSType:               config.FSType,
+			VolumeAttributes:     config.VolumeAttributes,
+			NodePublishSecretRef: config.NodePublishSecretRef,
+		},
+	}
+}
+
+// getEphemeralVolumeSource assembles a generic ephemeral volume source from its config
+func getEphemeralVolumeSource(config *k8schianetv1.EphemeralVolumeConfig) corev1.VolumeSource {
+	var accessModes = config.AccessModes
+	if len(accessModes) == 0 {
+		accessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
+	}
+
+	var spec = corev1.PersistentVolumeClaimSpec{
+		AccessModes: accessModes,
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceStorage: config.ResourceRequest,
+			},
+		},
+	}
+	if config.StorageClass != "" {
+		spec.StorageClassName = &config.StorageClass
+	}
+
+	return corev1.VolumeSource{
+		Ephemeral: &corev1.EphemeralVolumeSource{
+			VolumeClaimTemplate: &corev1.PersistentVolumeClaimTemplate{
+				Spec: spec,
+			},
+		},
+	}
+}
+
+// getISCSIVolumeSource assembles an iSCSI volume source from its config
+func getISCSIVolumeSource(config *k8schianetv1.ISCSIVolumeConfig, readOnly bool) corev1.VolumeSource {
+	return corev1.VolumeSource{
+		ISCSI: &corev1.ISCSIVolumeSource{
+			TargetPortal:      config.TargetPortal,
+			IQN:               config.IQN,
+			Lun:               config.Lun,
+			FSType:            config.FSType,
+			Portals:           config.Portals,
+			DiscoveryCHAPAuth: config.ChapAuthDiscovery,
+			SessionCHAPAuth:   config.ChapAuthSession,
+			SecretRef:         config.SecretRef,
+			ReadOnly:          readOnly,
+		},
+	}
+}
+
+// chiaRootClaimCreated returns true if CHIA_ROOT should be stored in a PVC created by the operator rather than an existing claim
+func chiaRootClaimCreated(storage *k8schianetv1.StorageConfig) bool {
+	return storage != nil && storage.ChiaRoot != nil && storage.ChiaRoot.PersistentVolumeClaim != nil && storage.ChiaRoot.PersistentVolumeClaim.ClaimName == ""
+}
+
+// getChiaRootClaimName returns the name of the PVC for CHIA_ROOT, either the configured existing claim or the name of the claim created by the operator
+func getChiaRootClaimName(config *k8schianetv1.PersistentVolumeClaimConfig, createdName string) string {
+	if config.ClaimName != "" {
+		return config.ClaimName
+	}
+	return createdName
+}
+
+// getChiaRootAccessModes returns the access modes for a CHIA_ROOT PVC created by the operator, defaulting to ReadWriteOnce
+func getChiaRootAccessModes(config *k8schianetv1.PersistentVolumeClaimConfig) []corev1.PersistentVolumeAccessMode {
+	if len(config.AccessModes) == 0 {
+		return []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
+	}
+	return config.AccessModes
+}
+
+// getPersistentVolumeClaimSpec assembles the spec of a PVC created by the operator from its config
+func getPersistentVolumeClaimSpec(config *k8schianetv1.PersistentVolumeClaimConfig) (corev1.PersistentVolumeClaimSpec, error) {
+	request, err := resource.ParseQuantity(config.ResourceRequest)
+	if err != nil {
+		return corev1.PersistentVolumeClaimSpec{}, fmt.Errorf("invalid resourceRequest %q: %v", config.ResourceRequest, err)
+	}
+
+	var spec = corev1.PersistentVolumeClaimSpec{
+		AccessModes: getChiaRootAccessModes(config),
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceStorage: request,
+			},
+		},
+	}
+	if config.StorageClass != "" {
+		spec.StorageClassName = &config.StorageClass
+	}
+	return spec, nil
+}
+
+// getChiaKeysEnv returns the value of the keys env var, a colon separated list of every mnemonic key file mounted into the chia container
+func getChiaKeysEnv(primary k8schianetv1.ChiaKeysSpec, additional []k8schianetv1.ChiaKeysSpec) string {
+	files := []string{path.Join("/key", primary.Key)}
+	for i, key := range additional {
+		files = append(files, path.Join(fmt.Sprintf("/key-%d", i+1), key.Key))
+	}
+	return strings.Join(files, ":")
+}
+
+// getAdditionalKeyVolumes assembles a Secret volume for every additional mnemonic key
+func getAdditionalKeyVolumes(additional []k8schianetv1.ChiaKeysSpec) []corev1.Volume {
+	var v []corev1.Volume
+	for i, key := range additional {
+		v = append(v, corev1.Volume{
+			Name: fmt.Sprintf("key-%d", i+1),
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: key.Name,
+				},
+			},
+		})
+	}
+	return v
+}
+
+// getAdditionalKeyVolumeMounts assembles a volume mount for every additional mnemonic key
+func getAdditionalKeyVolumeMounts(additional []k8schianetv1.ChiaKeysSpec) []corev1.VolumeMount {
+	var v []corev1.VolumeMount
+	for i := range additional {
+		v = append(v, corev1.VolumeMount{
+			Name:      fmt.Sprintf("key-%d", i+1),
+			MountPath: fmt.Sprintf("/key-%d", i+1),
+		})
+	}
+	return v
+}
+
+// getChiaKeysVerifyInitContainer assembles an init container that checks every mnemonic key with an expected fingerprint.
+// Returns false if no key has an expected fingerprint
+func getChiaKeysVerifyInitContainer(ctx context.Context, image string, primary k8schianetv1.ChiaKeysSpec, additional []k8schianetv1.ChiaKeysSpec, secContext *corev1.SecurityContext, pullPolicy corev1.PullPolicy) (corev1.Container, bool) {
+	keys := append([]k8schianetv1.ChiaKeysSpec{primary}, additional...)
+	files := strings.Split(getChiaKeysEnv(primary, additional), ":")
+	mounts := append([]corev1.VolumeMount{{Name: "key", MountPath: "/key"}}, getAdditionalKeyVolumeMounts(additional)...)
+
+	var entries []string
+	for i, key := range keys {
+		if key.Fingerprint != nil {
+			entries = append(entries, fmt.Sprintf("%s=%d", files[i], *key.Fingerprint))
+		}
+	}
+	if len(entries) == 0 {
+		return corev1.Container{}, false
+	}
+
+	return corev1.Container{
+		Name:            "verify-keys",
+		SecurityContext: secContext,
+		Image:           image,
+		ImagePullPolicy: pullPolicy,
+		Command:         []string{"/bin/sh", "-c", chiaKeysVerifyScript},
+		Env: []corev1.EnvVar{
+			{
+				Name:  "VERIFY_KEYS",
+				Value: strings.Join(entries, " "),
+			},
+		},
+		VolumeMounts:             mounts,
+		TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+	}, true
+}
+
+// getConfigChecksumPodAnnotations adds a checksum of an operator generated config.yaml override document to a copy of the pod annotations,
+// so pods restart to pick up changes to it since config.yaml is only merged on start
+func getConfigChecksumPodAnnotations(annotations map[string]string, overrides map[string]string, key string, annotation string) map[string]string {
+	data, ok := overrides[key]
+	if !ok {
+		return annotations
+	}
+
+	podAnnotations := make(map[string]string)
+	for k, v := range annotations {
+		podAnnotations[k] = v
+	}
+	podAnnotations[annotation] = fmt.Sprintf("%x", sha256.Sum256([]byte(data)))
+	return podAnnotations
+}
+
+// networkPolicyEnabled returns true if a NetworkPolicy should be created for a component
+func networkPolicyEnabled(config *k8schianetv1.NetworkPolicyConfig) bool {
+	return config != nil && config.Enabled
+}
+
+// getNetworkPolicy assembles a NetworkPolicy allowing the given peer port rules into a component's pods, plus its daemon, RPC and chia-exporter ports.
+// The RPC port is always reachable by the operator, and the daemon port only by the sources in rpcFrom
+func getNetworkPolicy(objMeta metav1.ObjectMeta, selector map[string]string, config *k8schianetv1.NetworkPolicyConfig, peerRules []networkingv1.NetworkPolicyIngressRule, rpcPort int32, exporter k8schianetv1.ChiaExporterConfigSpec) networkingv1.NetworkPolicy {
+	if config == nil {
+		config = &k8schianetv1.NetworkPolicyConfig{}
+	}
+
+	rules := peerRules
+	rules = append(rules, getNetworkPolicyIngressRule(rpcPort, append([]networkingv1.NetworkPolicyPeer{getOperatorNetworkPolicyPeer()}, config.RPCFrom...)))
+	if len(config.RPCFrom) != 0 {
+		rules = append(rules, getNetworkPolicyIngressRule(daemonPort, config.RPCFrom))
+	}
+	if chiaExporterEnabled(exporter) {
+		rules = append(rules, getNetworkPolicyIngressRule(getChiaExporterPort(exporter), config.MetricsFrom))
+	}
+
+	return networkingv1.NetworkPolicy{
+		ObjectMeta: objMeta,
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: selector,
+			},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress:     rules,
+		},
+	}
+}
+
+// getNetworkPolicyIngressRule allows TCP traffic to a port from the given sources, or from any source if there are none
+func getNetworkPolicyIngressRule(port int32, from []networkingv1.NetworkPolicyPeer) networkingv1.NetworkPolicyIngressRule {
+	protocol := corev1.ProtocolTCP
+	target := intstr.FromInt(int(port))
+	return networkingv1.NetworkPolicyIngressRule{
+		Ports: []networkingv1.NetworkPolicyPort{
+			{
+				Protocol: &protocol,
+				Port:     &target,
+			},
+		},
+		From: from,
+	}
+}
+
+// getOperatorNetworkPolicyPeer selects the operator's pods in any namespace, which connect to each component's RPC port to report status
+func getOperatorNetworkPolicyPeer() networkingv1.NetworkPolicyPeer {
+	return networkingv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{},
+		PodSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				"control-plane": "controller-manager",
+			},
+		},
+	}
+}
+
+// portExposure describes how one port of a component's Service is exposed outside the cluster
+type portExposure struct {
+	// objMeta is the metadata for the port's routes, which are named after the Service and port
+	objMeta     metav1.ObjectMeta
+	serviceName string
+	port        int32
+	config      k8schianetv1.PortExposureConfig
+}
+
+// getPortExposures gives the exposures of a component's peer and RPC ports, which are served by the Service described by objMeta and its -rpc Service.
+// Both are returned even when they aren't configured, so their routes are removed
+func getPortExposures(objMeta metav1.ObjectMeta, expose *k8schianetv1.ExposeConfig, peerPort, rpcPort int32) []portExposure {
+	var peers, rpc k8schianetv1.PortExposureConfig
+	if expose != nil && expose.Peers != nil {
+		peers = *expose.Peers
+	}
+	if expose != nil && expose.RPC != nil {
+		rpc = *expose.RPC
+	}
+	return []portExposure{
+		newPortExposure(objMeta, "peers", objMeta.Name, peerPort, peers),
+		newPortExposure(objMeta, "rpc", fmt.Sprintf("%s-rpc", objMeta.Name), rpcPort, rpc),
+	}
+}
+
+// newPortExposure gives the exposure of the named port of a component's Service, with routes named after the component's main Service in objMeta
+func newPortExposure(objMeta metav1.ObjectMeta, portName string, serviceName string, port int32, config k8schianetv1.PortExposureConfig) portExposure {
+	objMeta.Name = fmt.Sprintf("%s-%s", objMeta.Name, portName)
+	return portExposure{
+		objMeta:     objMeta,
+		serviceName: serviceName,
+		port:        port,
+		config:      config,
+	}
+}
+
+// reconcilePortExposure reconciles the Gateway API route exposing a Service port, removing those that aren't enabled.
+// Routes are skipped if the Gateway API CRDs aren't installed in the cluster
+func reconcilePortExposure(ctx context.Context, c client.Client, rec reconciler.ResourceReconciler, exposure portExposure) (*reconcile.Result, error) {
+	gateway := exposure.config.Gateway
+	for _, kind := range []k8schianetv1.GatewayRouteKind{k8schianetv1.GatewayTCPRoute, k8schianetv1.GatewayTLSRoute} {
+		route := getGatewayRoute(exposure, kind)
+		res, err := reconcileOptionalResource(ctx, c, rec, route, gateway != nil && gateway.Enabled && getGatewayRouteKind(gateway) == kind)
+		if err != nil {
+			return res, err
+		}
+	}
+	return nil, nil
+}
+
+// getGatewayRouteKind gives the kind of route configured, defaulting to a TCPRoute
+func getGatewayRouteKind(gateway *k8schianetv1.GatewayRouteConfig) k8schianetv1.GatewayRouteKind {
+	if gateway.Kind == "" {
+		return k8schianetv1.GatewayTCPRoute
+	}
+	return gateway.Kind
+}
+
+// getGatewayRoute assembles a Gateway API route of the given kind to an exposed Service port
+func getGatewayRoute(exposure portExposure, kind k8schianetv1.GatewayRouteKind) *unstructured.Unstructured {
+	gvk := tcpRouteGVK
+	if kind == k8schianetv1.GatewayTLSRoute {
+		gvk = tlsRouteGVK
+	}
+
+	var gateway k8schianetv1.GatewayRouteConfig
+	if exposure.config.Gateway != nil {
+		gateway = *exposure.config.Gateway
+	}
+	objMeta := exposure.objMeta
+	objMeta.Labels = mergeStringMaps(objMeta.Labels, gateway.Labels)
+	objMeta.Annotations = mergeStringMaps(objMeta.Annotations, gateway.Annotations)
+	route := newUnstructured(gvk, objMeta)
+
+	var parentRefs []interface{}
+	for _, ref := range gateway.ParentRefs {
+		parentRef := map[string]interface{}{
+			"name": ref.Name,
+		}
+		if ref.Namespace != nil {
+			parentRef["namespace"] = *ref.Namespace
+		}
+		if ref.SectionName != nil {
+			parentRef["sectionName"] = *ref.SectionName
+		}
+		parentRefs = append(parentRefs, parentRef)
+	}
+	spec := map[string]interface{}{
+		"parentRefs": parentRefs,
+		"rules": []interface{}{
+			map[string]interface{}{
+				"backendRefs": []interface{}{
+					map[string]interface{}{
+						"name": exposure.serviceName,
+						"port": int64(exposure.port),
+					},
+				},
+			},
+		},
+	}
+	if kind == k8schianetv1.GatewayTLSRoute && len(gateway.Hostnames) != 0 {
+		var hostnames []interface{}
+		for _, hostname := range gateway.Hostnames {
+			hostnames = append(hostnames, hostname)
+		}
+		spec["hostnames"] = hostnames
+	}
+	route.Object["spec"] = spec
+	return route
+}
+
+// mergeStringMaps gives a new map with the keys of each map, later maps taking precedence
+func mergeStringMaps(maps ...map[string]string) map[string]string {
+	var merged map[string]string
+	for _, m := range maps {
+		for k, v := range m {
+			if merged == nil {
+				merged = make(map[string]string)
+			}
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+// getServiceConfigs gives the peer, daemon and RPC port Service configs from a component's services config
+func getServiceConfigs(services *k8schianetv1.ChiaServicesConfig) (*k8schianetv1.ChiaServiceConfig, *k8schianetv1.ChiaServiceConfig, *k8schianetv1.ChiaServiceConfig) {
+	if services == nil {
+		return nil, nil, nil
+	}
+	return services.Peers, services.Daemon, services.RPC
+}
+
+// getServiceIPFamilies returns the IP families and IP family policy to set on every Service of a component
+func getServiceIPFamilies(services *k8schianetv1.ChiaServicesConfig) ([]corev1.IPFamily, *corev1.IPFamilyPolicy) {
+	if services == nil {
+		return nil, nil
+	}
+	return services.IPFamilies, services.IPFamilyPolicy
+}
+
+// getIPFamiliesOverride renders a config.yaml override document matching the primary of a component's Service IP families.
+// prefer_ipv6 is set in the component's config section, and when IPv6 is primary self_hostname, which the RPC and daemon servers bind to, is set to the IPv6 wildcard address
+func getIPFamiliesOverride(section string, ipFamilies []corev1.IPFamily) (string, error) {
+	ipv6 := len(ipFamilies) != 0 && ipFamilies[0] == corev1.IPv6Protocol
+	doc := map[string]interface{}{
+		section: map[string]interface{}{
+			"prefer_ipv6": ipv6,
+		},
+	}
+	if ipv6 {
+		doc["self_hostname"] = "::"
+	}
+
+	out, err := yaml.Marshal(doc)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// serviceEnabled returns true if a port's Service should be created, which it is unless disabled
+func serviceEnabled(config *k8schianetv1.ChiaServiceConfig) bool {
+	return config == nil || config.Enabled == nil || *config.Enabled
+}
+
+// getPortService assembles a Service for a single component port, of the configured type or defaultType.
+// The config's labels and annotations are added to those in objMeta, and its node port and load balancer settings are applied when the Service's type uses them
+func getPortService(objMeta metav1.ObjectMeta, selector map[string]string, config *k8schianetv1.ChiaServiceConfig, defaultType corev1.ServiceType, port corev1.ServicePort) corev1.Service {
+	if config == nil {
+		config = &k8schianetv1.ChiaServiceConfig{}
+	}
+	serviceType := defaultType
+	if config.ServiceType != nil {
+		serviceType = *config.ServiceType
+	}
+	objMeta.Labels = mergeStringMaps(objMeta.Labels, config.Labels)
+	objMeta.Annotations = mergeStringMaps(objMeta.Annotations, config.Annotations)
+
+	srv := corev1.Service{
+		ObjectMeta: objMeta,
+		Spec: corev1.ServiceSpec{
+			Type:     serviceType,
+			Selector: selector,
+		},
+	}
+	if serviceType == corev1.ServiceTypeNodePort || serviceType == corev1.ServiceTypeLoadBalancer {
+		if config.NodePort != nil {
+			port.NodePort = *config.NodePort
+		}
+		if config.ExternalTrafficPolicy != nil {
+			srv.Spec.ExternalTrafficPolicy = *config.ExternalTrafficPolicy
+		}
+	}
+	if serviceType == corev1.ServiceTypeLoadBalancer {
+		srv.Spec.LoadBalancerIP = config.LoadBalancerIP
+		srv.Spec.LoadBalancerClass = config.LoadBalancerClass
+		srv.Spec.LoadBalancerSourceRanges = config.LoadBalancerSourceRanges
+	}
+	srv.Spec.Ports = []corev1.ServicePort{port}
+	return srv
+}